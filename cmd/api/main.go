@@ -17,8 +17,10 @@ import (
 	"github.com/Dubjay18/scenee/internal/auth"
 	"github.com/Dubjay18/scenee/internal/handlers"
 	httpserver "github.com/Dubjay18/scenee/internal/http"
+	"github.com/Dubjay18/scenee/internal/jobs"
 	"github.com/Dubjay18/scenee/internal/repositories"
 	"github.com/Dubjay18/scenee/internal/services"
+	"github.com/Dubjay18/scenee/internal/storage"
 	"github.com/Dubjay18/scenee/internal/tmdb"
 	"github.com/go-chi/chi/v5"
 )
@@ -35,6 +37,30 @@ type Config struct {
 	GeminiModel         string `envconfig:"GEMINI_MODEL" default:"gemini-1.5-flash"`
 	EnSendProjectID     string `envconfig:"ENSEND_PROJECT_ID" required:"true"`
 	EnSendProjectSecret string `envconfig:"ENSEND_PROJECT_SECRET" required:"true"`
+	// Addresses (IPs or CIDRs) of reverse proxies whose X-Forwarded-For and
+	// X-Real-IP headers are believed; empty trusts none.
+	TrustedProxies []string `envconfig:"TRUSTED_PROXIES"`
+	// How often watchlist counters are recomputed from source tables.
+	CounterReconcileInterval time.Duration `envconfig:"COUNTER_RECONCILE_INTERVAL" default:"6h"`
+	// How often trending watchlist rankings are rebuilt.
+	TrendingInterval time.Duration `envconfig:"TRENDING_INTERVAL" default:"15m"`
+	// How often watchlist revisions past the retention window are purged.
+	HistoryPurgeInterval time.Duration `envconfig:"HISTORY_PURGE_INTERVAL" default:"24h"`
+	// How often watchlist view rows past their retention are purged.
+	ViewPurgeInterval time.Duration `envconfig:"VIEW_PURGE_INTERVAL" default:"1h"`
+	// How often followed people are checked for new credits.
+	PersonCreditsInterval time.Duration `envconfig:"PERSON_CREDITS_INTERVAL" default:"1h"`
+	// Blob storage for generated and uploaded images: "local" keeps them in
+	// MediaDir and serves them from MediaBaseURL, "s3" uses an S3-compatible bucket.
+	StorageDriver string `envconfig:"STORAGE_DRIVER" default:"local"`
+	MediaDir      string `envconfig:"MEDIA_DIR" default:"media"`
+	MediaBaseURL  string `envconfig:"MEDIA_BASE_URL" default:"/media"`
+	S3Endpoint    string `envconfig:"S3_ENDPOINT"`
+	S3Bucket      string `envconfig:"S3_BUCKET"`
+	S3Region      string `envconfig:"S3_REGION" default:"us-east-1"`
+	S3AccessKey   string `envconfig:"S3_ACCESS_KEY"`
+	S3SecretKey   string `envconfig:"S3_SECRET_KEY"`
+	S3PublicURL   string `envconfig:"S3_PUBLIC_URL"`
 }
 
 func mustLoadEnv() Config {
@@ -68,37 +94,102 @@ func mustDB(dsn string) *gorm.DB {
 	return db
 }
 
+// mustBlobStore returns the configured blob store and, for local storage, the
+// handler that serves it.
+func mustBlobStore(c Config) (storage.BlobStore, http.Handler) {
+	switch c.StorageDriver {
+	case "local":
+		store, err := storage.NewLocalStore(c.MediaDir, c.MediaBaseURL)
+		if err != nil {
+			log.Fatalf("media dir error: %v", err)
+		}
+		return store, store.Handler()
+	case "s3":
+		store, err := storage.NewS3Store(c.S3Endpoint, c.S3Bucket, c.S3Region, c.S3AccessKey, c.S3SecretKey, c.S3PublicURL)
+		if err != nil {
+			log.Fatalf("env error: %v", err)
+		}
+		return store, nil
+	default:
+		log.Fatalf("env error: unknown STORAGE_DRIVER %q", c.StorageDriver)
+		return nil, nil
+	}
+}
+
 func main() {
 	cfg := mustLoadEnv()
 	db := mustDB(cfg.DatabaseURL)
 	tmdbClient := tmdb.New(cfg.TMDBAPIKey, cfg.TMDBBaseURL)
 	aiClient := ai.NewGemini(cfg.GeminiAPIKey, cfg.GeminiModel)
+	blobStore, mediaHandler := mustBlobStore(cfg)
 
 	// Repositories
 	userRepo := repositories.NewUserRepository(db)
 	watchlistRepo := repositories.NewWatchlistRepository(db)
 	followRepo := repositories.NewFollowRepository(db)
 	reviewRepo := repositories.NewReviewRepository(db)
+	movieRepo := repositories.NewMovieRepository(db)
+	watchLogRepo := repositories.NewWatchLogRepository(db)
+	watchlistHistoryRepo := repositories.NewWatchlistHistoryRepository(db)
+	saveRepo := repositories.NewSaveRepository(db)
+	counterRepo := repositories.NewCounterRepository(db)
+	rankingRepo := repositories.NewRankingRepository(db)
+	tagRepo := repositories.NewTagRepository(db)
+	commentRepo := repositories.NewCommentRepository(db)
+	notificationRepo := repositories.NewNotificationRepository(db)
+	ratingRepo := repositories.NewRatingRepository(db)
+	profileRepo := repositories.NewProfileRepository(db)
+	blockRepo := repositories.NewBlockRepository(db)
+	suggestionRepo := repositories.NewSuggestionRepository(db)
+	shareRepo := repositories.NewShareRepository(db)
+	episodeRepo := repositories.NewEpisodeRepository(db)
+	personRepo := repositories.NewPersonRepository(db)
 
 	// Services
-	userService := services.NewUserService(userRepo)
-	watchlistService := services.NewWatchlistService(watchlistRepo, tmdbClient)
+	userService := services.NewUserService(userRepo, blockRepo)
+	avatarService := services.NewAvatarService(userRepo, blobStore)
+	movieService := services.NewMovieService(*tmdbClient, movieRepo, ratingRepo)
+	coverService := services.NewCoverService(watchlistRepo, blobStore)
+	watchlistService := services.NewWatchlistService(watchlistRepo, watchlistHistoryRepo, rankingRepo, watchLogRepo, blockRepo, movieService, coverService)
 	aiService := services.NewAIService(aiClient)
 	authService := services.NewAuthService(userService, cfg.JWTSecret, cfg.EnSendProjectID, cfg.EnSendProjectSecret)
-	followService := services.NewFollowService(followRepo)
-	reviewService := services.NewReviewService(reviewRepo)
+	followService := services.NewFollowService(followRepo, blockRepo, userRepo, notificationRepo)
+	reviewService := services.NewReviewService(reviewRepo, followRepo, blockRepo, notificationRepo)
+	diaryService := services.NewDiaryService(watchLogRepo, reviewRepo, movieRepo, movieService)
+	libraryService := services.NewLibraryService(saveRepo)
+	counterService := services.NewCounterService(counterRepo)
+	tagService := services.NewTagService(tagRepo)
+	commentService := services.NewCommentService(commentRepo, watchlistRepo, userRepo, blockRepo, notificationRepo)
+	profileService := services.NewProfileService(userRepo, watchlistRepo, followRepo, blockRepo, profileRepo)
+	blockService := services.NewBlockService(blockRepo, userRepo)
+	suggestionService := services.NewSuggestionService(suggestionRepo, userRepo, blockRepo)
+	shareService := services.NewShareService(shareRepo, watchlistRepo, userRepo, blockRepo, notificationRepo)
+	seriesService := services.NewSeriesService(*tmdbClient, movieService, episodeRepo)
+	personService := services.NewPersonService(*tmdbClient, personRepo, movieService, notificationRepo)
 
 	// Handlers
-	wlHandler := handlers.NewWatchlistHandler(watchlistService, db)
+	wlHandler := handlers.NewWatchlistHandler(watchlistService, coverService, db)
 	aiHandler := handlers.NewAIHandler(aiService)
-	userHandler := handlers.NewUserHandler(userService)
+	userHandler := handlers.NewUserHandler(userService, avatarService)
 	authHandler := handlers.NewAuthHandler(authService)
-	followHandler := handlers.NewFollowHandler(followService, db)
+	followHandler := handlers.NewFollowHandler(followService)
 	notificationHandler := handlers.NewNotificationHandler(db)
 	reviewHandler := handlers.NewReviewHandler(reviewService)
 	discoverHandler := handlers.NewDiscoverHandler(watchlistService)
-	adminHandler := handlers.NewAdminHandler(userService)
+	adminHandler := handlers.NewAdminHandler(userService, counterService)
 	statsHandler := handlers.NewStatsHandler(db)
+	diaryHandler := handlers.NewDiaryHandler(diaryService)
+	libraryHandler := handlers.NewLibraryHandler(libraryService)
+	tagHandler := handlers.NewTagHandler(tagService)
+	commentHandler := handlers.NewCommentHandler(commentService)
+	movieHandler := handlers.NewMovieHandler(movieService)
+	profileHandler := handlers.NewProfileHandler(profileService)
+	blockHandler := handlers.NewBlockHandler(blockService)
+	suggestionHandler := handlers.NewSuggestionHandler(suggestionService)
+	shareHandler := handlers.NewShareHandler(shareService)
+	tvHandler := handlers.NewTVHandler(seriesService)
+	personHandler := handlers.NewPersonHandler(personService)
+	searchHandler := handlers.NewSearchHandler(watchlistService, userService)
 
 	// Auth middleware
 	verifier := auth.NewJWTVerifier(cfg.JWTSecret)
@@ -107,10 +198,17 @@ func main() {
 		// Public routes
 		r.Group(func(r chi.Router) {
 			r.Get("/search/movies", wlHandler.SearchMovies)
+			r.Get("/movies/top-rated", movieHandler.TopRated)
 			r.Get("/movies/{id}", wlHandler.Movie)
+			r.Get("/search/tv", tvHandler.Search)
+			r.Route("/tv", tvHandler.Routes)
+			r.Get("/search/people", personHandler.Search)
+			r.Get("/people/{id}", personHandler.Get)
 			r.Get("/feed", wlHandler.Feed)
 			r.Get("/watchlists/public/{slug}", wlHandler.GetPublic)
+			r.Get("/u/{username}/{slug}", wlHandler.GetByOwnerSlug)
 			r.Route("/discover", discoverHandler.Routes)
+			r.Route("/tags", tagHandler.Routes)
 			r.Post("/ai/ask", aiHandler.Ask)
 			// Auth routes (public)
 			r.Route("/auth", authHandler.Routes)
@@ -120,28 +218,76 @@ func main() {
 			r.Use(verifier.Middleware)
 			r.Get("/me", userHandler.Me)
 			r.Patch("/me", userHandler.UpdateMe)
+			r.Put("/me/avatar", userHandler.UploadAvatar)
+			r.Delete("/me/avatar", userHandler.DeleteAvatar)
+			r.Route("/me/diary", diaryHandler.Routes)
+			r.Route("/me/saved", libraryHandler.SavedRoutes)
+			r.Route("/me/collections", libraryHandler.CollectionRoutes)
+			r.Route("/me/follow-requests", followHandler.FollowRequestRoutes)
+			r.Get("/me/blocks", blockHandler.Blocked)
+			r.Get("/me/mutes", blockHandler.Muted)
+			r.Route("/me/suggestions", suggestionHandler.Routes)
+			r.Get("/me/shared", shareHandler.Inbox)
+			r.Route("/me/tv", tvHandler.MeRoutes)
+			r.Get("/me/people", personHandler.Followed)
+			r.Get("/people/{id}/follow", personHandler.Following)
+			r.Post("/people/{id}/follow", personHandler.Follow)
+			r.Delete("/people/{id}/follow", personHandler.Unfollow)
 			r.Route("/watchlists", wlHandler.Routes)
+			r.Route("/watchlists/{id}/comments", commentHandler.Routes)
+			r.Post("/watchlists/{id}/share", shareHandler.Share)
 			// trending can be public but keep here for now or move above
 			r.Get("/trending", wlHandler.Trending)
+			r.Route("/search", searchHandler.Routes)
+			r.Get("/users/by-username/{username}", profileHandler.GetByUsername)
 			r.Route("/users/{id}", func(r chi.Router) {
+				r.Get("/", profileHandler.Get)
 				r.Post("/follow", followHandler.Follow)
 				r.Delete("/follow", followHandler.Unfollow)
 				r.Get("/followers", followHandler.GetFollowers)
 				r.Get("/following", followHandler.GetFollowing)
+				r.Get("/mutual-followers", followHandler.MutualFollowers)
+				r.Get("/followers-you-follow", followHandler.FollowersYouFollow)
+				r.Post("/block", blockHandler.Block)
+				r.Delete("/block", blockHandler.Unblock)
+				r.Post("/mute", blockHandler.Mute)
+				r.Delete("/mute", blockHandler.Unmute)
 			})
 			r.Route("/movies/{id}/reviews", func(r chi.Router) {
 				r.Get("/", reviewHandler.GetByMovie)
 				r.Post("/", reviewHandler.Create)
 				r.Put("/", reviewHandler.Update)
 				r.Delete("/{reviewID}", reviewHandler.Delete)
+				r.Post("/{reviewID}/like", reviewHandler.Like)
+				r.Delete("/{reviewID}/like", reviewHandler.Unlike)
+				r.Get("/{reviewID}/replies", reviewHandler.Replies)
+				r.Post("/{reviewID}/replies", reviewHandler.Reply)
+				r.Delete("/{reviewID}/replies/{replyID}", reviewHandler.DeleteReply)
 			})
 			r.Delete("/admin/users/{id}", adminHandler.DeleteUser)
+			r.Get("/admin/counters/drift", adminHandler.CounterDrift)
+			r.Post("/admin/counters/reconcile", adminHandler.ReconcileCounters)
 			r.Get("/stats", statsHandler.GetStats)
 			r.Route("/notifications", notificationHandler.Routes)
 		})
 	}
 
-	srv := httpserver.NewServer(mounter)
+	trustedProxies, err := httpserver.ParseTrustedProxies(cfg.TrustedProxies)
+	if err != nil {
+		log.Fatalf("env error: %v", err)
+	}
+	srv := httpserver.NewServer(trustedProxies, mounter)
+	if mediaHandler != nil {
+		srv.Router.Handle("/media/*", http.StripPrefix("/media", mediaHandler))
+	}
+
+	// Background jobs
+	jobs.Every(context.Background(), cfg.CounterReconcileInterval, "reconcile-counters", counterService.ReconcileJob)
+	jobs.Every(context.Background(), cfg.TrendingInterval, "trending-rankings", watchlistService.RecomputeTrending)
+	jobs.Every(context.Background(), cfg.PersonCreditsInterval, "person-credits", personService.CheckNewCredits)
+	jobs.Every(context.Background(), cfg.HistoryPurgeInterval, "purge-history", watchlistService.PurgeHistory)
+	jobs.Every(context.Background(), cfg.ViewPurgeInterval, "purge-views", watchlistService.PurgeViews)
+	jobs.Once(context.Background(), "normalize-tags", tagService.NormalizeStored)
 
 	addr := ":" + cfg.Port
 	log.Printf("listening on %s", addr)
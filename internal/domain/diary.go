@@ -0,0 +1,68 @@
+package domain
+
+import (
+	"time"
+
+	"github.com/Dubjay18/scenee/internal/models"
+	"github.com/google/uuid"
+)
+
+// DiaryEntry is a watch log entry with enough movie data to render a diary row
+type DiaryEntry struct {
+	ID        uuid.UUID  `json:"id"`
+	WatchedOn string     `json:"watched_on"`
+	Rewatch   bool       `json:"rewatch"`
+	Note      string     `json:"note,omitempty"`
+	Rating    int        `json:"rating,omitempty"`
+	ReviewID  *uuid.UUID `json:"review_id,omitempty"`
+	MovieID   uuid.UUID  `json:"movie_id"`
+	TMDBID    int        `json:"tmdb_id"`
+	Title     string     `json:"title"`
+	Year      int        `json:"year,omitempty"`
+	PosterURL string     `json:"poster_url,omitempty"`
+}
+
+// DiaryMonth groups diary entries by the month they were watched in
+type DiaryMonth struct {
+	Month   string       `json:"month"` // YYYY-MM
+	Count   int          `json:"count"`
+	Entries []DiaryEntry `json:"entries"`
+}
+
+// DiaryEntryFromModel converts models.WatchLog (with Movie and Review preloaded) to domain.DiaryEntry
+func DiaryEntryFromModel(model *models.WatchLog) *DiaryEntry {
+	if model == nil {
+		return nil
+	}
+	entry := &DiaryEntry{
+		ID:        model.ID,
+		WatchedOn: model.WatchedOn.Format(time.DateOnly),
+		Rewatch:   model.Rewatch,
+		Note:      model.Note,
+		ReviewID:  model.ReviewID,
+		MovieID:   model.MovieID,
+		TMDBID:    model.Movie.TMDBID,
+		Title:     model.Movie.Title,
+		Year:      model.Movie.Year,
+		PosterURL: model.Movie.PosterURL,
+	}
+	if model.Review != nil {
+		entry.Rating = model.Review.Rating
+	}
+	return entry
+}
+
+// DiaryFromModel groups watch logs (already sorted newest first) into months
+func DiaryFromModel(logs []models.WatchLog) []DiaryMonth {
+	months := make([]DiaryMonth, 0)
+	for i := range logs {
+		key := logs[i].WatchedOn.Format("2006-01")
+		if len(months) == 0 || months[len(months)-1].Month != key {
+			months = append(months, DiaryMonth{Month: key, Entries: []DiaryEntry{}})
+		}
+		m := &months[len(months)-1]
+		m.Entries = append(m.Entries, *DiaryEntryFromModel(&logs[i]))
+		m.Count++
+	}
+	return months
+}
@@ -2,6 +2,7 @@ package domain
 
 import (
 	"encoding/json"
+	"math"
 	"time"
 
 	"github.com/google/uuid"
@@ -9,10 +10,12 @@ import (
 	"gorm.io/datatypes"
 )
 
-// Movie represents a movie in the domain layer
+// Movie represents a movie in the domain layer, or a series when MediaType
+// is models.MediaTV.
 type Movie struct {
 	ID          uuid.UUID
 	TMDBID      int
+	MediaType   string
 	Title       string
 	Year        int
 	ReleaseDate *time.Time
@@ -23,6 +26,165 @@ type Movie struct {
 	Metadata    map[string]interface{}
 	CreatedAt   time.Time
 	UpdatedAt   time.Time
+	// Community is how Scenee users rated the movie; nil until someone has.
+	Community *RatingSummary
+	// Details is the TMDB enrichment stored under Metadata["details"]; nil
+	// for movies only seen in search results.
+	Details *MovieDetails
+	// Series is the season list of a series, stored under
+	// Metadata["series"]; nil for movies.
+	Series *SeriesInfo
+}
+
+// SeriesInfo is what a series has beyond the fields it shares with movies.
+// Like MovieDetails it is stored as JSON in metadata.
+type SeriesInfo struct {
+	Status       string          `json:"status"`
+	SeasonCount  int             `json:"season_count"`
+	EpisodeCount int             `json:"episode_count"`
+	LastAirDate  *time.Time      `json:"last_air_date"`
+	Seasons      []SeasonSummary `json:"seasons"`
+	FetchedAt    time.Time       `json:"fetched_at"`
+}
+
+// SeasonSummary describes a season without its episodes. Number 0 is
+// TMDB's "Specials".
+type SeasonSummary struct {
+	Number       int        `json:"number"`
+	Name         string     `json:"name"`
+	Overview     string     `json:"overview"`
+	EpisodeCount int        `json:"episode_count"`
+	AirDate      *time.Time `json:"air_date"`
+	PosterPath   string     `json:"poster_path"`
+}
+
+// Season returns the summary of season number, if the series has it.
+func (si *SeriesInfo) Season(number int) (SeasonSummary, bool) {
+	if si == nil {
+		return SeasonSummary{}, false
+	}
+	for _, s := range si.Seasons {
+		if s.Number == number {
+			return s, true
+		}
+	}
+	return SeasonSummary{}, false
+}
+
+// Stale reports whether the series was fetched more than ttl before now.
+func (si *SeriesInfo) Stale(now time.Time, ttl time.Duration) bool {
+	return si == nil || now.Sub(si.FetchedAt) > ttl
+}
+
+// MovieDetails is what TMDB adds to a movie beyond the basics. It is stored
+// as JSON in the movie's metadata, so the tags are part of the schema.
+type MovieDetails struct {
+	Cast     []CastMember `json:"cast"`
+	Crew     []CrewMember `json:"crew"`
+	Trailers []Video      `json:"trailers"`
+	// Releases and Providers are keyed by ISO 3166-1 region code.
+	Releases        map[string][]Release      `json:"releases"`
+	Providers       map[string]WatchProviders `json:"providers"`
+	Similar         []MovieRef                `json:"similar"`
+	Recommendations []MovieRef                `json:"recommendations"`
+	FetchedAt       time.Time                 `json:"fetched_at"`
+}
+
+type CastMember struct {
+	PersonID    int    `json:"person_id"`
+	Name        string `json:"name"`
+	Character   string `json:"character"`
+	ProfilePath string `json:"profile_path"`
+	Order       int    `json:"order"`
+}
+
+type CrewMember struct {
+	PersonID    int    `json:"person_id"`
+	Name        string `json:"name"`
+	Job         string `json:"job"`
+	Department  string `json:"department"`
+	ProfilePath string `json:"profile_path"`
+}
+
+// Video is a trailer or teaser hosted on Site (YouTube or Vimeo) under Key.
+type Video struct {
+	Name        string    `json:"name"`
+	Site        string    `json:"site"`
+	Key         string    `json:"key"`
+	Type        string    `json:"type"`
+	Official    bool      `json:"official"`
+	PublishedAt time.Time `json:"published_at"`
+}
+
+// Release is one release in a region. Type follows TMDB: 1 premiere,
+// 2 limited theatrical, 3 theatrical, 4 digital, 5 physical, 6 TV.
+type Release struct {
+	Date          time.Time `json:"date"`
+	Type          int       `json:"type"`
+	Certification string    `json:"certification"`
+}
+
+// WatchProviders is where a movie can be watched in one region. Link is
+// TMDB's page for the region, which must be credited (JustWatch data).
+type WatchProviders struct {
+	Link    string     `json:"link"`
+	Stream  []Provider `json:"stream"`
+	Rent    []Provider `json:"rent"`
+	Buy     []Provider `json:"buy"`
+	FreeAds []Provider `json:"free_with_ads"`
+	Free    []Provider `json:"free"`
+}
+
+type Provider struct {
+	ID       int    `json:"id"`
+	Name     string `json:"name"`
+	LogoPath string `json:"logo_path"`
+}
+
+// MovieRef is a movie mentioned by another, such as a similar title.
+type MovieRef struct {
+	TMDBID     int    `json:"tmdb_id"`
+	Title      string `json:"title"`
+	Year       int    `json:"year"`
+	PosterPath string `json:"poster_path"`
+}
+
+// ForRegion drops releases and providers outside region.
+func (d *MovieDetails) ForRegion(region string) {
+	if d == nil {
+		return
+	}
+	releases, hasReleases := d.Releases[region]
+	providers, hasProviders := d.Providers[region]
+	d.Releases = map[string][]Release{}
+	d.Providers = map[string]WatchProviders{}
+	if hasReleases {
+		d.Releases[region] = releases
+	}
+	if hasProviders {
+		d.Providers[region] = providers
+	}
+}
+
+// Stale reports whether the details were fetched more than ttl before now.
+func (d *MovieDetails) Stale(now time.Time, ttl time.Duration) bool {
+	return d == nil || now.Sub(d.FetchedAt) > ttl
+}
+
+// RatingSummary aggregates Scenee ratings. Histogram[i] counts ratings of i+1.
+type RatingSummary struct {
+	Average   float64
+	Count     int
+	Histogram []int
+}
+
+// RatingSummaryFromModel converts models.MovieRatingStats, returning nil
+// when there are no ratings.
+func RatingSummaryFromModel(s *models.MovieRatingStats) *RatingSummary {
+	if s == nil || s.Count == 0 {
+		return nil
+	}
+	return &RatingSummary{Average: math.Round(s.Average()*100) / 100, Count: s.Count, Histogram: s.Histogram}
 }
 
 type SearchResult struct {
@@ -50,9 +212,24 @@ func (m *Movie) FromModel(model *models.Movie) *Movie {
 		_ = json.Unmarshal(model.Metadata, &metadata)
 	}
 
+	// Details and series are returned typed rather than a second time in
+	// Metadata
+	var stored struct {
+		Details *MovieDetails `json:"details"`
+		Series  *SeriesInfo   `json:"series"`
+	}
+	_, hasDetails := metadata["details"]
+	_, hasSeries := metadata["series"]
+	if hasDetails || hasSeries {
+		_ = json.Unmarshal(model.Metadata, &stored)
+		delete(metadata, "details")
+		delete(metadata, "series")
+	}
+
 	return &Movie{
 		ID:          model.ID,
 		TMDBID:      model.TMDBID,
+		MediaType:   model.MediaType,
 		Title:       model.Title,
 		Year:        model.Year,
 		ReleaseDate: model.ReleaseDate,
@@ -63,6 +240,8 @@ func (m *Movie) FromModel(model *models.Movie) *Movie {
 		Metadata:    metadata,
 		CreatedAt:   model.CreatedAt,
 		UpdatedAt:   model.UpdatedAt,
+		Details:     stored.Details,
+		Series:      stored.Series,
 	}
 }
 
@@ -79,16 +258,32 @@ func (m *Movie) ToModel() *models.Movie {
 		genresJSON = datatypes.JSON(genresBytes)
 	}
 
-	// Encode metadata
+	// Encode metadata, with Details and Series folded back in
 	var metadataJSON datatypes.JSON
-	if m.Metadata != nil {
-		metadataBytes, _ := json.Marshal(m.Metadata)
+	if m.Metadata != nil || m.Details != nil || m.Series != nil {
+		metadata := make(map[string]interface{}, len(m.Metadata)+2)
+		for k, v := range m.Metadata {
+			metadata[k] = v
+		}
+		if m.Details != nil {
+			metadata["details"] = m.Details
+		}
+		if m.Series != nil {
+			metadata["series"] = m.Series
+		}
+		metadataBytes, _ := json.Marshal(metadata)
 		metadataJSON = datatypes.JSON(metadataBytes)
 	}
 
+	mediaType := m.MediaType
+	if mediaType == "" {
+		mediaType = models.MediaMovie
+	}
+
 	return &models.Movie{
 		ID:          m.ID,
 		TMDBID:      m.TMDBID,
+		MediaType:   mediaType,
 		Title:       m.Title,
 		Year:        m.Year,
 		ReleaseDate: m.ReleaseDate,
@@ -0,0 +1,106 @@
+package domain
+
+import (
+	"encoding/json"
+	"time"
+
+	"github.com/google/uuid"
+	"gorm.io/datatypes"
+
+	"github.com/Dubjay18/scenee/internal/models"
+)
+
+// Person is an actor or crew member with their filmography.
+type Person struct {
+	ID                 uuid.UUID  `json:"id"`
+	TMDBID             int        `json:"tmdb_id"`
+	Name               string     `json:"name"`
+	Biography          string     `json:"biography"`
+	KnownForDepartment string     `json:"known_for_department"`
+	ProfilePath        string     `json:"profile_path"`
+	Birthday           *time.Time `json:"birthday"`
+	Deathday           *time.Time `json:"deathday"`
+	PlaceOfBirth       string     `json:"place_of_birth"`
+	Credits            []Credit   `json:"credits"`
+	FetchedAt          time.Time  `json:"fetched_at"`
+}
+
+// Credit is one movie or series a person worked on. Cast credits have a
+// Character and the department "Acting"; crew credits have a Job. Someone
+// who both directed and starred in a film has two credits for it.
+type Credit struct {
+	TMDBID      int        `json:"tmdb_id"`
+	MediaType   string     `json:"media_type"`
+	Title       string     `json:"title"`
+	Year        int        `json:"year"`
+	ReleaseDate *time.Time `json:"release_date"`
+	PosterPath  string     `json:"poster_path"`
+	Department  string     `json:"department"`
+	Character   string     `json:"character,omitempty"`
+	Job         string     `json:"job,omitempty"`
+}
+
+// Stale reports whether the person was fetched more than ttl before now.
+func (p *Person) Stale(now time.Time, ttl time.Duration) bool {
+	return p == nil || now.Sub(p.FetchedAt) > ttl
+}
+
+// Filter keeps the credits of mediaType and department; empty matches all.
+func (p *Person) Filter(mediaType, department string) {
+	kept := p.Credits[:0]
+	for _, c := range p.Credits {
+		if (mediaType == "" || c.MediaType == mediaType) && (department == "" || c.Department == department) {
+			kept = append(kept, c)
+		}
+	}
+	p.Credits = kept
+}
+
+// PersonFromModel converts models.Person to domain.Person
+func PersonFromModel(model *models.Person) *Person {
+	if model == nil {
+		return nil
+	}
+	credits := []Credit{}
+	if len(model.Credits) > 0 {
+		_ = json.Unmarshal(model.Credits, &credits)
+	}
+	return &Person{
+		ID:                 model.ID,
+		TMDBID:             model.TMDBID,
+		Name:               model.Name,
+		Biography:          model.Biography,
+		KnownForDepartment: model.KnownForDepartment,
+		ProfilePath:        model.ProfilePath,
+		Birthday:           model.Birthday,
+		Deathday:           model.Deathday,
+		PlaceOfBirth:       model.PlaceOfBirth,
+		Credits:            credits,
+		FetchedAt:          model.FetchedAt,
+	}
+}
+
+// ToModel converts domain.Person to models.Person
+func (p *Person) ToModel() *models.Person {
+	if p == nil {
+		return nil
+	}
+	credits := p.Credits
+	if credits == nil {
+		credits = []Credit{}
+	}
+	creditsJSON, _ := json.Marshal(credits)
+	return &models.Person{
+		ID:                 p.ID,
+		TMDBID:             p.TMDBID,
+		Name:               p.Name,
+		Biography:          p.Biography,
+		KnownForDepartment: p.KnownForDepartment,
+		ProfilePath:        p.ProfilePath,
+		Birthday:           p.Birthday,
+		Deathday:           p.Deathday,
+		PlaceOfBirth:       p.PlaceOfBirth,
+		Credits:            datatypes.JSON(creditsJSON),
+		FetchedAt:          p.FetchedAt,
+	}
+}
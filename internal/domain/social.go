@@ -61,9 +61,10 @@ func LikesFromModel(modelLikes []models.Like) []Like {
 
 // Save represents a save in the domain layer
 type Save struct {
-	UserID      uuid.UUID
-	WatchlistID uuid.UUID
-	CreatedAt   time.Time
+	UserID       uuid.UUID
+	WatchlistID  uuid.UUID
+	CollectionID *uuid.UUID
+	CreatedAt    time.Time
 }
 
 // FromModel converts models.Save to domain.Save
@@ -72,9 +73,10 @@ func (s *Save) FromModel(model *models.Save) *Save {
 		return nil
 	}
 	return &Save{
-		UserID:      model.UserID,
-		WatchlistID: model.WatchlistID,
-		CreatedAt:   model.CreatedAt,
+		UserID:       model.UserID,
+		WatchlistID:  model.WatchlistID,
+		CollectionID: model.CollectionID,
+		CreatedAt:    model.CreatedAt,
 	}
 }
 
@@ -84,9 +86,10 @@ func (s *Save) ToModel() *models.Save {
 		return nil
 	}
 	return &models.Save{
-		UserID:      s.UserID,
-		WatchlistID: s.WatchlistID,
-		CreatedAt:   s.CreatedAt,
+		UserID:       s.UserID,
+		WatchlistID:  s.WatchlistID,
+		CollectionID: s.CollectionID,
+		CreatedAt:    s.CreatedAt,
 	}
 }
 
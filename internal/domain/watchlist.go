@@ -21,7 +21,6 @@ type Watchlist struct {
 	LikeCount   int
 	SaveCount   int
 	ItemCount   int
-	SavedBy     []string
 }
 
 // FromModel converts models.Watchlist to domain.Watchlist
@@ -35,12 +34,6 @@ func (w *Watchlist) FromModel(model *models.Watchlist) *Watchlist {
 		owner = UserFromModel(&model.Owner)
 	}
 
-	// Map SavedBy user IDs
-	savedBy := make([]string, 0, len(model.SavedBy))
-	for _, id := range model.SavedBy {
-		savedBy = append(savedBy, id)
-	}
-
 	return &Watchlist{
 		ID:          model.ID,
 		CreatedAt:   model.CreatedAt,
@@ -54,7 +47,6 @@ func (w *Watchlist) FromModel(model *models.Watchlist) *Watchlist {
 		LikeCount:   model.LikeCount,
 		SaveCount:   model.SaveCount,
 		ItemCount:   model.ItemCount,
-		SavedBy:     savedBy,
 	}
 }
 
@@ -76,7 +68,6 @@ func (w *Watchlist) ToModel() *models.Watchlist {
 		LikeCount:   w.LikeCount,
 		SaveCount:   w.SaveCount,
 		ItemCount:   w.ItemCount,
-		SavedBy:     w.SavedBy,
 	}
 
 	if w.Owner != nil {
@@ -10,11 +10,57 @@ import (
 )
 
 type AdminHandler struct {
-	UserService *services.UserService
+	UserService    *services.UserService
+	CounterService *services.CounterService
 }
 
-func NewAdminHandler(us *services.UserService) *AdminHandler {
-	return &AdminHandler{UserService: us}
+func NewAdminHandler(us *services.UserService, cs *services.CounterService) *AdminHandler {
+	return &AdminHandler{UserService: us, CounterService: cs}
+}
+
+// requireAdmin writes 401/403 and returns false unless the caller is an admin.
+func (h *AdminHandler) requireAdmin(w http.ResponseWriter, r *http.Request) bool {
+	uid := auth.UserID(r.Context())
+	if uid == "" {
+		w.WriteHeader(http.StatusUnauthorized)
+		return false
+	}
+	role, err := h.UserService.GetRole(r.Context(), uid)
+	if err != nil || role != "admin" {
+		w.WriteHeader(http.StatusForbidden)
+		return false
+	}
+	return true
+}
+
+// CounterDrift handles GET /v1/admin/counters/drift
+// Lists watchlists whose like/save/item/view counters disagree with source rows.
+func (h *AdminHandler) CounterDrift(w http.ResponseWriter, r *http.Request) {
+	if !h.requireAdmin(w, r) {
+		return
+	}
+	report, err := h.CounterService.Drift(r.Context())
+	if err != nil {
+		w.WriteHeader(http.StatusInternalServerError)
+		_ = json.NewEncoder(w).Encode(map[string]string{"error": err.Error()})
+		return
+	}
+	_ = json.NewEncoder(w).Encode(report)
+}
+
+// ReconcileCounters handles POST /v1/admin/counters/reconcile
+// Runs the reconciliation job now and returns the drift it fixed.
+func (h *AdminHandler) ReconcileCounters(w http.ResponseWriter, r *http.Request) {
+	if !h.requireAdmin(w, r) {
+		return
+	}
+	report, err := h.CounterService.Reconcile(r.Context())
+	if err != nil {
+		w.WriteHeader(http.StatusInternalServerError)
+		_ = json.NewEncoder(w).Encode(map[string]string{"error": err.Error()})
+		return
+	}
+	_ = json.NewEncoder(w).Encode(report)
 }
 
 func (h *AdminHandler) DeleteUser(w http.ResponseWriter, r *http.Request) {
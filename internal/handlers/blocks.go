@@ -0,0 +1,99 @@
+package handlers
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"net/http"
+
+	"github.com/go-chi/chi/v5"
+	"gorm.io/gorm"
+
+	"github.com/Dubjay18/scenee/internal/auth"
+	"github.com/Dubjay18/scenee/internal/services"
+)
+
+type BlockHandler struct {
+	Service *services.BlockService
+}
+
+func NewBlockHandler(s *services.BlockService) *BlockHandler {
+	return &BlockHandler{Service: s}
+}
+
+// Block handles POST /v1/users/{id}/block
+func (h *BlockHandler) Block(w http.ResponseWriter, r *http.Request) {
+	h.apply(w, r, h.Service.Block)
+}
+
+// Unblock handles DELETE /v1/users/{id}/block
+func (h *BlockHandler) Unblock(w http.ResponseWriter, r *http.Request) {
+	h.apply(w, r, h.Service.Unblock)
+}
+
+// Mute handles POST /v1/users/{id}/mute
+func (h *BlockHandler) Mute(w http.ResponseWriter, r *http.Request) {
+	h.apply(w, r, h.Service.Mute)
+}
+
+// Unmute handles DELETE /v1/users/{id}/mute
+func (h *BlockHandler) Unmute(w http.ResponseWriter, r *http.Request) {
+	h.apply(w, r, h.Service.Unmute)
+}
+
+func (h *BlockHandler) apply(w http.ResponseWriter, r *http.Request, fn func(ctx context.Context, userID, targetID string) error) {
+	uid := auth.UserID(r.Context())
+	if uid == "" {
+		w.WriteHeader(http.StatusUnauthorized)
+		return
+	}
+	if err := fn(r.Context(), uid, chi.URLParam(r, "id")); err != nil {
+		writeBlockError(w, err)
+		return
+	}
+	w.WriteHeader(http.StatusNoContent)
+}
+
+// Blocked handles GET /v1/me/blocks
+func (h *BlockHandler) Blocked(w http.ResponseWriter, r *http.Request) {
+	uid := auth.UserID(r.Context())
+	if uid == "" {
+		w.WriteHeader(http.StatusUnauthorized)
+		return
+	}
+	users, err := h.Service.Blocked(r.Context(), uid)
+	if err != nil {
+		writeBlockError(w, err)
+		return
+	}
+	_ = json.NewEncoder(w).Encode(users)
+}
+
+// Muted handles GET /v1/me/mutes
+func (h *BlockHandler) Muted(w http.ResponseWriter, r *http.Request) {
+	uid := auth.UserID(r.Context())
+	if uid == "" {
+		w.WriteHeader(http.StatusUnauthorized)
+		return
+	}
+	users, err := h.Service.Muted(r.Context(), uid)
+	if err != nil {
+		writeBlockError(w, err)
+		return
+	}
+	_ = json.NewEncoder(w).Encode(users)
+}
+
+func writeBlockError(w http.ResponseWriter, err error) {
+	switch {
+	case errors.Is(err, services.ErrUnauthorized):
+		w.WriteHeader(http.StatusUnauthorized)
+	case errors.Is(err, services.ErrBlockSelf):
+		w.WriteHeader(http.StatusBadRequest)
+	case errors.Is(err, gorm.ErrRecordNotFound):
+		w.WriteHeader(http.StatusNotFound)
+	default:
+		w.WriteHeader(http.StatusInternalServerError)
+	}
+	_ = json.NewEncoder(w).Encode(map[string]string{"error": err.Error()})
+}
@@ -0,0 +1,170 @@
+package handlers
+
+import (
+	"encoding/json"
+	"errors"
+	"net/http"
+	"strings"
+
+	"github.com/go-chi/chi/v5"
+	"gorm.io/gorm"
+
+	"github.com/Dubjay18/scenee/internal/auth"
+	"github.com/Dubjay18/scenee/internal/services"
+	"github.com/Dubjay18/scenee/internal/validate"
+)
+
+type CommentHandler struct {
+	Service *services.CommentService
+}
+
+func NewCommentHandler(s *services.CommentService) *CommentHandler {
+	return &CommentHandler{Service: s}
+}
+
+// Routes is mounted under /watchlists/{id}/comments in main.
+func (h *CommentHandler) Routes(r chi.Router) {
+	r.Get("/", h.list)
+	r.Post("/", h.create)
+	r.Get("/{commentId}/replies", h.replies)
+	r.Patch("/{commentId}", h.edit)
+	r.Delete("/{commentId}", h.delete)
+}
+
+type commentPageQuery struct {
+	Page  int `validate:"gte=1"`
+	Limit int `validate:"gte=1,lte=100"`
+}
+
+// list handles GET /v1/watchlists/{id}/comments?page=1&limit=20
+// Top-level comments, oldest first, each with its first replies.
+func (h *CommentHandler) list(w http.ResponseWriter, r *http.Request) {
+	uid := auth.UserID(r.Context())
+	if uid == "" {
+		w.WriteHeader(http.StatusUnauthorized)
+		return
+	}
+	q := commentPageQuery{Page: queryInt(r, "page", 1), Limit: queryInt(r, "limit", 20)}
+	if errs := validate.Map(q); errs != nil {
+		w.WriteHeader(http.StatusBadRequest)
+		_ = json.NewEncoder(w).Encode(errs)
+		return
+	}
+	page, err := h.Service.List(r.Context(), uid, chi.URLParam(r, "id"), q.Page, q.Limit)
+	if err != nil {
+		writeCommentError(w, err)
+		return
+	}
+	_ = json.NewEncoder(w).Encode(page)
+}
+
+// replies handles GET /v1/watchlists/{id}/comments/{commentId}/replies?page=1&limit=20
+func (h *CommentHandler) replies(w http.ResponseWriter, r *http.Request) {
+	uid := auth.UserID(r.Context())
+	if uid == "" {
+		w.WriteHeader(http.StatusUnauthorized)
+		return
+	}
+	q := commentPageQuery{Page: queryInt(r, "page", 1), Limit: queryInt(r, "limit", 20)}
+	if errs := validate.Map(q); errs != nil {
+		w.WriteHeader(http.StatusBadRequest)
+		_ = json.NewEncoder(w).Encode(errs)
+		return
+	}
+	page, err := h.Service.Replies(r.Context(), uid, chi.URLParam(r, "id"), chi.URLParam(r, "commentId"), q.Page, q.Limit)
+	if err != nil {
+		writeCommentError(w, err)
+		return
+	}
+	_ = json.NewEncoder(w).Encode(page)
+}
+
+// create handles POST /v1/watchlists/{id}/comments {"body":"...", "parent_id":"..."}
+// parent_id makes it a reply to a top-level comment.
+func (h *CommentHandler) create(w http.ResponseWriter, r *http.Request) {
+	uid := auth.UserID(r.Context())
+	if uid == "" {
+		w.WriteHeader(http.StatusUnauthorized)
+		return
+	}
+	var b struct {
+		Body     string `json:"body" validate:"required,min=1,max=2000"`
+		ParentID string `json:"parent_id" validate:"omitempty,uuid"`
+	}
+	if err := json.NewDecoder(r.Body).Decode(&b); err != nil {
+		w.WriteHeader(http.StatusBadRequest)
+		return
+	}
+	b.Body = strings.TrimSpace(b.Body) // so a blank body fails "required"
+	if errs := validate.Map(b); errs != nil {
+		w.WriteHeader(http.StatusBadRequest)
+		_ = json.NewEncoder(w).Encode(errs)
+		return
+	}
+	c, err := h.Service.Create(r.Context(), uid, chi.URLParam(r, "id"), b.ParentID, b.Body)
+	if err != nil {
+		writeCommentError(w, err)
+		return
+	}
+	w.WriteHeader(http.StatusCreated)
+	_ = json.NewEncoder(w).Encode(c)
+}
+
+// edit handles PATCH /v1/watchlists/{id}/comments/{commentId} {"body":"..."}
+func (h *CommentHandler) edit(w http.ResponseWriter, r *http.Request) {
+	uid := auth.UserID(r.Context())
+	if uid == "" {
+		w.WriteHeader(http.StatusUnauthorized)
+		return
+	}
+	var b struct {
+		Body string `json:"body" validate:"required,min=1,max=2000"`
+	}
+	if err := json.NewDecoder(r.Body).Decode(&b); err != nil {
+		w.WriteHeader(http.StatusBadRequest)
+		return
+	}
+	b.Body = strings.TrimSpace(b.Body) // so a blank body fails "required"
+	if errs := validate.Map(b); errs != nil {
+		w.WriteHeader(http.StatusBadRequest)
+		_ = json.NewEncoder(w).Encode(errs)
+		return
+	}
+	c, err := h.Service.Edit(r.Context(), uid, chi.URLParam(r, "id"), chi.URLParam(r, "commentId"), b.Body)
+	if err != nil {
+		writeCommentError(w, err)
+		return
+	}
+	_ = json.NewEncoder(w).Encode(c)
+}
+
+// delete handles DELETE /v1/watchlists/{id}/comments/{commentId}
+// Allowed for the author and for the list owner; replies go with it.
+func (h *CommentHandler) delete(w http.ResponseWriter, r *http.Request) {
+	uid := auth.UserID(r.Context())
+	if uid == "" {
+		w.WriteHeader(http.StatusUnauthorized)
+		return
+	}
+	if err := h.Service.Delete(r.Context(), uid, chi.URLParam(r, "id"), chi.URLParam(r, "commentId")); err != nil {
+		writeCommentError(w, err)
+		return
+	}
+	w.WriteHeader(http.StatusNoContent)
+}
+
+func writeCommentError(w http.ResponseWriter, err error) {
+	switch {
+	case errors.Is(err, services.ErrUnauthorized):
+		w.WriteHeader(http.StatusUnauthorized)
+	case errors.Is(err, services.ErrForbidden), errors.Is(err, services.ErrCommentsDisabled), errors.Is(err, services.ErrBlocked):
+		w.WriteHeader(http.StatusForbidden)
+	case errors.Is(err, services.ErrNestedReply), errors.Is(err, services.ErrEmptyComment):
+		w.WriteHeader(http.StatusBadRequest)
+	case errors.Is(err, gorm.ErrRecordNotFound):
+		w.WriteHeader(http.StatusNotFound)
+	default:
+		w.WriteHeader(http.StatusInternalServerError)
+	}
+	_ = json.NewEncoder(w).Encode(map[string]string{"error": err.Error()})
+}
@@ -0,0 +1,64 @@
+package handlers
+
+import (
+	"encoding/json"
+	"errors"
+	"net/http"
+
+	"github.com/go-chi/chi/v5"
+	"gorm.io/gorm"
+
+	"github.com/Dubjay18/scenee/internal/auth"
+	"github.com/Dubjay18/scenee/internal/services"
+)
+
+// uploadCover handles PUT /v1/watchlists/{id}/cover (multipart, field "file")
+// Replaces the generated collage with the owner's own image.
+func (h *WatchlistHandler) uploadCover(w http.ResponseWriter, r *http.Request) {
+	uid := auth.UserID(r.Context())
+	if uid == "" {
+		w.WriteHeader(http.StatusUnauthorized)
+		return
+	}
+	file, ok := formImage(w, r)
+	if !ok {
+		return
+	}
+	defer file.Close()
+	wl, err := h.Covers.UploadCustom(r.Context(), uid, chi.URLParam(r, "id"), file)
+	if err != nil {
+		writeCoverError(w, err)
+		return
+	}
+	_ = json.NewEncoder(w).Encode(wl)
+}
+
+// deleteCover handles DELETE /v1/watchlists/{id}/cover
+// Drops a custom cover and goes back to the generated collage.
+func (h *WatchlistHandler) deleteCover(w http.ResponseWriter, r *http.Request) {
+	uid := auth.UserID(r.Context())
+	if uid == "" {
+		w.WriteHeader(http.StatusUnauthorized)
+		return
+	}
+	wl, err := h.Covers.RemoveCustom(r.Context(), uid, chi.URLParam(r, "id"))
+	if err != nil {
+		writeCoverError(w, err)
+		return
+	}
+	_ = json.NewEncoder(w).Encode(wl)
+}
+
+func writeCoverError(w http.ResponseWriter, err error) {
+	switch {
+	case uploadStatus(err) != 0:
+		w.WriteHeader(uploadStatus(err))
+	case errors.Is(err, services.ErrUnauthorized):
+		w.WriteHeader(http.StatusUnauthorized)
+	case errors.Is(err, gorm.ErrRecordNotFound):
+		w.WriteHeader(http.StatusNotFound)
+	default:
+		w.WriteHeader(http.StatusInternalServerError)
+	}
+	_ = json.NewEncoder(w).Encode(map[string]string{"error": err.Error()})
+}
@@ -0,0 +1,151 @@
+package handlers
+
+import (
+	"encoding/json"
+	"errors"
+	"net/http"
+	"strconv"
+	"time"
+
+	"github.com/go-chi/chi/v5"
+	"github.com/google/uuid"
+	"gorm.io/gorm"
+
+	"github.com/Dubjay18/scenee/internal/auth"
+	"github.com/Dubjay18/scenee/internal/services"
+	"github.com/Dubjay18/scenee/internal/validate"
+)
+
+type DiaryHandler struct {
+	Service *services.DiaryService
+}
+
+func NewDiaryHandler(s *services.DiaryService) *DiaryHandler {
+	return &DiaryHandler{Service: s}
+}
+
+// Routes is mounted under /me/diary in main.
+func (h *DiaryHandler) Routes(r chi.Router) {
+	r.Get("/", h.list)
+	r.Post("/", h.create)
+	r.Delete("/{id}", h.delete)
+}
+
+// list handles GET /v1/me/diary?year=2025&month=11
+// Returns the user's diary grouped by month, newest first.
+func (h *DiaryHandler) list(w http.ResponseWriter, r *http.Request) {
+	uid := auth.UserID(r.Context())
+	if uid == "" {
+		w.WriteHeader(http.StatusUnauthorized)
+		return
+	}
+	type queryT struct {
+		Year  int `validate:"gte=1888,lte=9999"`
+		Month int `validate:"omitempty,gte=1,lte=12"`
+	}
+	q := queryT{Year: time.Now().Year()}
+	if v := r.URL.Query().Get("year"); v != "" {
+		if n, err := strconv.Atoi(v); err == nil {
+			q.Year = n
+		}
+	}
+	if v := r.URL.Query().Get("month"); v != "" {
+		if n, err := strconv.Atoi(v); err == nil {
+			q.Month = n
+		}
+	}
+	if errs := validate.Map(q); errs != nil {
+		w.WriteHeader(http.StatusBadRequest)
+		_ = json.NewEncoder(w).Encode(errs)
+		return
+	}
+	months, err := h.Service.Diary(r.Context(), uid, q.Year, q.Month)
+	if err != nil {
+		w.WriteHeader(http.StatusInternalServerError)
+		_ = json.NewEncoder(w).Encode(map[string]string{"error": err.Error()})
+		return
+	}
+	_ = json.NewEncoder(w).Encode(map[string]interface{}{
+		"year":   q.Year,
+		"months": months,
+	})
+}
+
+// create handles POST /v1/me/diary
+// Logs a watch by tmdb_id (or movie_id from a watchlist item), optionally with a rating.
+func (h *DiaryHandler) create(w http.ResponseWriter, r *http.Request) {
+	uid := auth.UserID(r.Context())
+	if uid == "" {
+		w.WriteHeader(http.StatusUnauthorized)
+		return
+	}
+	type bodyT struct {
+		TMDBID    int    `json:"tmdb_id" validate:"required_without=MovieID,omitempty,gt=0"`
+		MovieID   string `json:"movie_id" validate:"omitempty,uuid"`
+		WatchedOn string `json:"watched_on" validate:"omitempty,datetime=2006-01-02"`
+		Rewatch   *bool  `json:"rewatch"`
+		Rating    int    `json:"rating" validate:"omitempty,min=1,max=10"`
+		Review    string `json:"review" validate:"max=20000,textmax=5000"`
+		Note      string `json:"note" validate:"max=500"`
+	}
+	var b bodyT
+	if err := json.NewDecoder(r.Body).Decode(&b); err != nil {
+		w.WriteHeader(http.StatusBadRequest)
+		_ = json.NewEncoder(w).Encode(map[string]string{"error": "invalid json"})
+		return
+	}
+	if errs := validate.Map(b); errs != nil {
+		w.WriteHeader(http.StatusBadRequest)
+		_ = json.NewEncoder(w).Encode(errs)
+		return
+	}
+	in := services.LogWatchInput{TMDBID: b.TMDBID, Rewatch: b.Rewatch, Rating: b.Rating, Review: b.Review, Note: b.Note}
+	if b.MovieID != "" {
+		in.MovieID = uuid.MustParse(b.MovieID)
+	}
+	if b.WatchedOn != "" {
+		in.WatchedOn, _ = time.Parse(time.DateOnly, b.WatchedOn)
+	}
+	entry, err := h.Service.LogWatch(r.Context(), uid, in)
+	if err != nil {
+		switch {
+		case errors.Is(err, services.ErrUnauthorized):
+			w.WriteHeader(http.StatusUnauthorized)
+		case errors.Is(err, services.ErrWatchedInFuture):
+			w.WriteHeader(http.StatusBadRequest)
+		case errors.Is(err, gorm.ErrRecordNotFound):
+			w.WriteHeader(http.StatusNotFound)
+		default:
+			w.WriteHeader(http.StatusInternalServerError)
+		}
+		_ = json.NewEncoder(w).Encode(map[string]string{"error": err.Error()})
+		return
+	}
+	w.WriteHeader(http.StatusCreated)
+	_ = json.NewEncoder(w).Encode(entry)
+}
+
+// delete handles DELETE /v1/me/diary/{id}
+func (h *DiaryHandler) delete(w http.ResponseWriter, r *http.Request) {
+	uid := auth.UserID(r.Context())
+	if uid == "" {
+		w.WriteHeader(http.StatusUnauthorized)
+		return
+	}
+	id, err := uuid.Parse(chi.URLParam(r, "id"))
+	if err != nil {
+		w.WriteHeader(http.StatusBadRequest)
+		_ = json.NewEncoder(w).Encode(map[string]string{"error": "invalid diary entry id"})
+		return
+	}
+	if err := h.Service.Delete(r.Context(), uid, id.String()); err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			w.WriteHeader(http.StatusNotFound)
+		} else {
+			w.WriteHeader(http.StatusInternalServerError)
+		}
+		_ = json.NewEncoder(w).Encode(map[string]string{"error": err.Error()})
+		return
+	}
+	w.WriteHeader(http.StatusNoContent)
+}
@@ -65,8 +65,7 @@ func (h *DiscoverHandler) getTrending(w http.ResponseWriter, r *http.Request) {
 
 	body, _, err := h.Service.FetchFeed(r.Context(), opts)
 	if err != nil {
-		w.WriteHeader(http.StatusBadGateway)
-		_ = json.NewEncoder(w).Encode(map[string]string{"error": err.Error()})
+		writeUpstreamError(w, err)
 		return
 	}
 
@@ -111,8 +110,7 @@ func (h *DiscoverHandler) getNew(w http.ResponseWriter, r *http.Request) {
 
 	body, _, err := h.Service.FetchFeed(r.Context(), opts)
 	if err != nil {
-		w.WriteHeader(http.StatusBadGateway)
-		_ = json.NewEncoder(w).Encode(map[string]string{"error": err.Error()})
+		writeUpstreamError(w, err)
 		return
 	}
 
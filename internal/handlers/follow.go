@@ -1,52 +1,41 @@
 package handlers
 
 import (
+	"context"
 	"encoding/json"
+	"errors"
 	"net/http"
 
 	"github.com/Dubjay18/scenee/internal/auth"
-	"github.com/Dubjay18/scenee/internal/models"
 	"github.com/Dubjay18/scenee/internal/services"
 	"github.com/go-chi/chi/v5"
-	"github.com/google/uuid"
 	"gorm.io/gorm"
 )
 
 type FollowHandler struct {
 	Follows *services.FollowService
-	DB      *gorm.DB
 }
 
-func NewFollowHandler(s *services.FollowService, db *gorm.DB) *FollowHandler {
-	return &FollowHandler{Follows: s, DB: db}
+func NewFollowHandler(s *services.FollowService) *FollowHandler {
+	return &FollowHandler{Follows: s}
 }
 
+// Follow handles POST /v1/users/{id}/follow
+// Private accounts get a follow request; the response says which happened.
 func (h *FollowHandler) Follow(w http.ResponseWriter, r *http.Request) {
 	followerID := auth.UserID(r.Context())
 	followeeID := chi.URLParam(r, "id")
-	if followerID == followeeID {
-		w.WriteHeader(http.StatusBadRequest)
-		_ = json.NewEncoder(w).Encode(map[string]string{"error": "cannot follow yourself"})
-		return
-	}
-	if err := h.Follows.Follow(r.Context(), followerID, followeeID); err != nil {
-		w.WriteHeader(http.StatusInternalServerError)
-		_ = json.NewEncoder(w).Encode(map[string]string{"error": "failed to follow"})
+	status, err := h.Follows.Follow(r.Context(), followerID, followeeID)
+	if err != nil {
+		writeFollowError(w, err)
 		return
 	}
-	// Create notification
-	followerUUID, _ := uuid.Parse(followerID)
-	followeeUUID, _ := uuid.Parse(followeeID)
-	notification := models.Notification{
-		UserID:   followeeUUID,
-		Type:     "follow",
-		ActorID:  followerUUID,
-		EntityID: followerUUID, // or followee? for follow, entity is the follower
+	if status == services.FollowStatusRequested {
+		w.WriteHeader(http.StatusAccepted)
+	} else {
+		w.WriteHeader(http.StatusCreated)
 	}
-	h.DB.WithContext(r.Context()).Create(&notification)
-
-	w.WriteHeader(http.StatusCreated)
-	_ = json.NewEncoder(w).Encode(map[string]string{"message": "followed"})
+	_ = json.NewEncoder(w).Encode(map[string]string{"status": status})
 }
 
 func (h *FollowHandler) Unfollow(w http.ResponseWriter, r *http.Request) {
@@ -61,24 +50,88 @@ func (h *FollowHandler) Unfollow(w http.ResponseWriter, r *http.Request) {
 	_ = json.NewEncoder(w).Encode(map[string]string{"message": "unfollowed"})
 }
 
+// GetFollowers handles GET /v1/users/{id}/followers?cursor=&limit=
 func (h *FollowHandler) GetFollowers(w http.ResponseWriter, r *http.Request) {
-	userID := chi.URLParam(r, "id")
-	followers, err := h.Follows.GetFollowers(r.Context(), userID)
+	h.list(w, r, h.Follows.Followers)
+}
+
+// GetFollowing handles GET /v1/users/{id}/following?cursor=&limit=
+func (h *FollowHandler) GetFollowing(w http.ResponseWriter, r *http.Request) {
+	h.list(w, r, h.Follows.Following)
+}
+
+// MutualFollowers handles GET /v1/users/{id}/mutual-followers
+func (h *FollowHandler) MutualFollowers(w http.ResponseWriter, r *http.Request) {
+	h.list(w, r, h.Follows.MutualFollowers)
+}
+
+// FollowersYouFollow handles GET /v1/users/{id}/followers-you-follow
+func (h *FollowHandler) FollowersYouFollow(w http.ResponseWriter, r *http.Request) {
+	h.list(w, r, h.Follows.FollowersYouFollow)
+}
+
+func (h *FollowHandler) list(w http.ResponseWriter, r *http.Request, fn func(ctx context.Context, viewer, userID, cursor string, limit int) (*services.FollowPage, error)) {
+	uid := auth.UserID(r.Context())
+	if uid == "" {
+		w.WriteHeader(http.StatusUnauthorized)
+		return
+	}
+	page, err := fn(r.Context(), uid, chi.URLParam(r, "id"), r.URL.Query().Get("cursor"), queryInt(r, "limit", 20))
 	if err != nil {
-		w.WriteHeader(http.StatusInternalServerError)
-		_ = json.NewEncoder(w).Encode(map[string]string{"error": "failed to get followers"})
+		writeFollowError(w, err)
 		return
 	}
-	_ = json.NewEncoder(w).Encode(followers)
+	_ = json.NewEncoder(w).Encode(page)
 }
 
-func (h *FollowHandler) GetFollowing(w http.ResponseWriter, r *http.Request) {
-	userID := chi.URLParam(r, "id")
-	following, err := h.Follows.GetFollowing(r.Context(), userID)
+// FollowRequestRoutes is mounted under /me/follow-requests in main.
+func (h *FollowHandler) FollowRequestRoutes(r chi.Router) {
+	r.Get("/", h.requests)
+	r.Post("/{id}/approve", h.approve)
+	r.Delete("/{id}", h.deny)
+}
+
+// requests handles GET /v1/me/follow-requests
+func (h *FollowHandler) requests(w http.ResponseWriter, r *http.Request) {
+	reqs, err := h.Follows.Requests(r.Context(), auth.UserID(r.Context()))
 	if err != nil {
-		w.WriteHeader(http.StatusInternalServerError)
-		_ = json.NewEncoder(w).Encode(map[string]string{"error": "failed to get following"})
+		writeFollowError(w, err)
 		return
 	}
-	_ = json.NewEncoder(w).Encode(following)
+	_ = json.NewEncoder(w).Encode(reqs)
+}
+
+// approve handles POST /v1/me/follow-requests/{id}/approve where id is the
+// requester.
+func (h *FollowHandler) approve(w http.ResponseWriter, r *http.Request) {
+	if err := h.Follows.Approve(r.Context(), auth.UserID(r.Context()), chi.URLParam(r, "id")); err != nil {
+		writeFollowError(w, err)
+		return
+	}
+	w.WriteHeader(http.StatusNoContent)
+}
+
+// deny handles DELETE /v1/me/follow-requests/{id}
+func (h *FollowHandler) deny(w http.ResponseWriter, r *http.Request) {
+	if err := h.Follows.Deny(r.Context(), auth.UserID(r.Context()), chi.URLParam(r, "id")); err != nil {
+		writeFollowError(w, err)
+		return
+	}
+	w.WriteHeader(http.StatusNoContent)
+}
+
+func writeFollowError(w http.ResponseWriter, err error) {
+	switch {
+	case errors.Is(err, services.ErrUnauthorized):
+		w.WriteHeader(http.StatusUnauthorized)
+	case errors.Is(err, services.ErrFollowSelf), errors.Is(err, services.ErrInvalidCursor):
+		w.WriteHeader(http.StatusBadRequest)
+	case errors.Is(err, services.ErrBlocked), errors.Is(err, services.ErrForbidden):
+		w.WriteHeader(http.StatusForbidden)
+	case errors.Is(err, gorm.ErrRecordNotFound):
+		w.WriteHeader(http.StatusNotFound)
+	default:
+		w.WriteHeader(http.StatusInternalServerError)
+	}
+	_ = json.NewEncoder(w).Encode(map[string]string{"error": err.Error()})
 }
@@ -0,0 +1,198 @@
+package handlers
+
+import (
+	"encoding/json"
+	"errors"
+	"net/http"
+	"strconv"
+	"strings"
+
+	"github.com/go-chi/chi/v5"
+	"github.com/google/uuid"
+	"gorm.io/gorm"
+
+	"github.com/Dubjay18/scenee/internal/auth"
+	"github.com/Dubjay18/scenee/internal/services"
+	"github.com/Dubjay18/scenee/internal/validate"
+)
+
+type LibraryHandler struct {
+	Service *services.LibraryService
+}
+
+func NewLibraryHandler(s *services.LibraryService) *LibraryHandler {
+	return &LibraryHandler{Service: s}
+}
+
+// SavedRoutes is mounted under /me/saved in main.
+func (h *LibraryHandler) SavedRoutes(r chi.Router) {
+	r.Get("/", h.listSaved)
+	r.Patch("/{watchlistId}", h.moveSaved)
+}
+
+// CollectionRoutes is mounted under /me/collections in main.
+func (h *LibraryHandler) CollectionRoutes(r chi.Router) {
+	r.Get("/", h.listCollections)
+	r.Post("/", h.createCollection)
+	r.Patch("/{id}", h.renameCollection)
+	r.Delete("/{id}", h.deleteCollection)
+}
+
+// listSaved handles GET /v1/me/saved?collection=<id>|unfiled&sort=saved_desc&page=1&limit=20
+func (h *LibraryHandler) listSaved(w http.ResponseWriter, r *http.Request) {
+	uid := auth.UserID(r.Context())
+	if uid == "" {
+		w.WriteHeader(http.StatusUnauthorized)
+		return
+	}
+	type queryT struct {
+		Collection string `validate:"omitempty,uuid|eq=unfiled"`
+		Sort       string `validate:"omitempty,oneof=saved_desc saved_asc title updated popular"`
+		Page       int    `validate:"gte=1"`
+		Limit      int    `validate:"gte=1,lte=100"`
+	}
+	q := queryT{Collection: r.URL.Query().Get("collection"), Sort: r.URL.Query().Get("sort"), Page: 1, Limit: 20}
+	if v := r.URL.Query().Get("page"); v != "" {
+		if n, err := strconv.Atoi(v); err == nil {
+			q.Page = n
+		}
+	}
+	if v := r.URL.Query().Get("limit"); v != "" {
+		if n, err := strconv.Atoi(v); err == nil {
+			q.Limit = n
+		}
+	}
+	if errs := validate.Map(q); errs != nil {
+		w.WriteHeader(http.StatusBadRequest)
+		_ = json.NewEncoder(w).Encode(errs)
+		return
+	}
+	page, err := h.Service.ListSaved(r.Context(), uid, q.Collection, q.Sort, q.Page, q.Limit)
+	if err != nil {
+		writeLibraryError(w, err)
+		return
+	}
+	_ = json.NewEncoder(w).Encode(page)
+}
+
+// moveSaved handles PATCH /v1/me/saved/{watchlistId} {"collection_id":"<id>"|null}
+func (h *LibraryHandler) moveSaved(w http.ResponseWriter, r *http.Request) {
+	uid := auth.UserID(r.Context())
+	if uid == "" {
+		w.WriteHeader(http.StatusUnauthorized)
+		return
+	}
+	var b struct {
+		CollectionID *uuid.UUID `json:"collection_id"`
+	}
+	if err := json.NewDecoder(r.Body).Decode(&b); err != nil {
+		w.WriteHeader(http.StatusBadRequest)
+		return
+	}
+	if err := h.Service.MoveSaved(r.Context(), uid, chi.URLParam(r, "watchlistId"), b.CollectionID); err != nil {
+		writeLibraryError(w, err)
+		return
+	}
+	w.WriteHeader(http.StatusNoContent)
+}
+
+// listCollections handles GET /v1/me/collections
+func (h *LibraryHandler) listCollections(w http.ResponseWriter, r *http.Request) {
+	uid := auth.UserID(r.Context())
+	if uid == "" {
+		w.WriteHeader(http.StatusUnauthorized)
+		return
+	}
+	cs, err := h.Service.Collections(r.Context(), uid)
+	if err != nil {
+		writeLibraryError(w, err)
+		return
+	}
+	_ = json.NewEncoder(w).Encode(cs)
+}
+
+type collectionBody struct {
+	Name string `json:"name" validate:"required,min=1,max=100"`
+}
+
+// createCollection handles POST /v1/me/collections {"name":"..."}
+func (h *LibraryHandler) createCollection(w http.ResponseWriter, r *http.Request) {
+	uid := auth.UserID(r.Context())
+	if uid == "" {
+		w.WriteHeader(http.StatusUnauthorized)
+		return
+	}
+	var b collectionBody
+	if err := json.NewDecoder(r.Body).Decode(&b); err != nil {
+		w.WriteHeader(http.StatusBadRequest)
+		return
+	}
+	b.Name = strings.TrimSpace(b.Name) // so a blank name fails "required"
+	if errs := validate.Map(b); errs != nil {
+		w.WriteHeader(http.StatusBadRequest)
+		_ = json.NewEncoder(w).Encode(errs)
+		return
+	}
+	c, err := h.Service.CreateCollection(r.Context(), uid, b.Name)
+	if err != nil {
+		writeLibraryError(w, err)
+		return
+	}
+	w.WriteHeader(http.StatusCreated)
+	_ = json.NewEncoder(w).Encode(c)
+}
+
+// renameCollection handles PATCH /v1/me/collections/{id} {"name":"..."}
+func (h *LibraryHandler) renameCollection(w http.ResponseWriter, r *http.Request) {
+	uid := auth.UserID(r.Context())
+	if uid == "" {
+		w.WriteHeader(http.StatusUnauthorized)
+		return
+	}
+	var b collectionBody
+	if err := json.NewDecoder(r.Body).Decode(&b); err != nil {
+		w.WriteHeader(http.StatusBadRequest)
+		return
+	}
+	b.Name = strings.TrimSpace(b.Name) // so a blank name fails "required"
+	if errs := validate.Map(b); errs != nil {
+		w.WriteHeader(http.StatusBadRequest)
+		_ = json.NewEncoder(w).Encode(errs)
+		return
+	}
+	c, err := h.Service.RenameCollection(r.Context(), uid, chi.URLParam(r, "id"), b.Name)
+	if err != nil {
+		writeLibraryError(w, err)
+		return
+	}
+	_ = json.NewEncoder(w).Encode(c)
+}
+
+// deleteCollection handles DELETE /v1/me/collections/{id}
+// The saves in it stay in the library, unfiled.
+func (h *LibraryHandler) deleteCollection(w http.ResponseWriter, r *http.Request) {
+	uid := auth.UserID(r.Context())
+	if uid == "" {
+		w.WriteHeader(http.StatusUnauthorized)
+		return
+	}
+	if err := h.Service.DeleteCollection(r.Context(), uid, chi.URLParam(r, "id")); err != nil {
+		writeLibraryError(w, err)
+		return
+	}
+	w.WriteHeader(http.StatusNoContent)
+}
+
+func writeLibraryError(w http.ResponseWriter, err error) {
+	switch {
+	case errors.Is(err, services.ErrUnauthorized):
+		w.WriteHeader(http.StatusUnauthorized)
+	case errors.Is(err, services.ErrCollectionExists):
+		w.WriteHeader(http.StatusConflict)
+	case errors.Is(err, gorm.ErrRecordNotFound):
+		w.WriteHeader(http.StatusNotFound)
+	default:
+		w.WriteHeader(http.StatusInternalServerError)
+	}
+	_ = json.NewEncoder(w).Encode(map[string]string{"error": err.Error()})
+}
@@ -0,0 +1,82 @@
+package handlers
+
+import (
+	"encoding/json"
+	"errors"
+	"log"
+	"net/http"
+
+	"github.com/Dubjay18/scenee/internal/services"
+	"github.com/Dubjay18/scenee/internal/tmdb"
+	"github.com/Dubjay18/scenee/internal/validate"
+)
+
+type MovieHandler struct {
+	Movies *services.MovieService
+}
+
+func NewMovieHandler(s *services.MovieService) *MovieHandler {
+	return &MovieHandler{Movies: s}
+}
+
+// TopRated handles GET /v1/movies/top-rated?genre=&min_votes=10&page=1&limit=20
+// Ranked by Scenee ratings with a Bayesian minimum-vote threshold.
+func (h *MovieHandler) TopRated(w http.ResponseWriter, r *http.Request) {
+	type queryT struct {
+		Genre    string `validate:"omitempty,max=50"`
+		MinVotes int    `validate:"gte=1,lte=1000"`
+		Page     int    `validate:"gte=1"`
+		Limit    int    `validate:"gte=1,lte=100"`
+	}
+	q := queryT{
+		Genre:    r.URL.Query().Get("genre"),
+		MinVotes: queryInt(r, "min_votes", services.DefaultTopRatedMinVotes),
+		Page:     queryInt(r, "page", 1),
+		Limit:    queryInt(r, "limit", 20),
+	}
+	if errs := validate.Map(q); errs != nil {
+		w.WriteHeader(http.StatusBadRequest)
+		_ = json.NewEncoder(w).Encode(errs)
+		return
+	}
+	page, err := h.Movies.TopRated(r.Context(), q.Genre, q.MinVotes, q.Page, q.Limit)
+	if err != nil {
+		w.WriteHeader(http.StatusInternalServerError)
+		_ = json.NewEncoder(w).Encode(map[string]string{"error": err.Error()})
+		return
+	}
+	w.Header().Set("Cache-Control", "public, max-age=300")
+	_ = json.NewEncoder(w).Encode(page)
+}
+
+// upstreamStatus is the status to answer with when a TMDB-backed call
+// fails: TMDB's 404 passes through, throttling and outages say so, and
+// anything else is a bad gateway.
+// writeUpstreamError answers a failed TMDB-backed request with a fixed
+// message for its status and logs the error, whose text isn't for clients.
+func writeUpstreamError(w http.ResponseWriter, err error) {
+	status := upstreamStatus(err)
+	log.Printf("upstream error: %v", err)
+	w.WriteHeader(status)
+	_ = json.NewEncoder(w).Encode(map[string]string{"error": upstreamMessages[status]})
+}
+
+var upstreamMessages = map[int]string{
+	http.StatusNotFound:           "not found",
+	http.StatusTooManyRequests:    "movie data is rate limited, try again shortly",
+	http.StatusServiceUnavailable: "movie data is temporarily unavailable",
+	http.StatusBadGateway:         "movie data request failed",
+}
+
+func upstreamStatus(err error) int {
+	switch {
+	case errors.Is(err, tmdb.ErrNotFound):
+		return http.StatusNotFound
+	case errors.Is(err, tmdb.ErrRateLimited):
+		return http.StatusTooManyRequests
+	case errors.Is(err, tmdb.ErrUnavailable):
+		return http.StatusServiceUnavailable
+	default:
+		return http.StatusBadGateway
+	}
+}
@@ -46,6 +46,10 @@ func (h *NotificationHandler) getNotifications(w http.ResponseWriter, r *http.Re
 
 	var notifications []models.Notification
 	query := h.DB.WithContext(r.Context()).Where("user_id = ?", userUUID)
+	// Hide activity from muted users and users blocked either way
+	query = query.Where("actor_id NOT IN (SELECT muted_id FROM user_mutes WHERE muter_id = ?)", userUUID).
+		Where("actor_id NOT IN (SELECT blocked_id FROM user_blocks WHERE blocker_id = ?)", userUUID).
+		Where("actor_id NOT IN (SELECT blocker_id FROM user_blocks WHERE blocked_id = ?)", userUUID)
 
 	if unreadOnly {
 		query = query.Where("is_read = ?", false)
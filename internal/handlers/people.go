@@ -0,0 +1,147 @@
+package handlers
+
+import (
+	"encoding/json"
+	"errors"
+	"net/http"
+
+	"gorm.io/gorm"
+
+	"github.com/Dubjay18/scenee/internal/auth"
+	"github.com/Dubjay18/scenee/internal/services"
+	"github.com/Dubjay18/scenee/internal/validate"
+)
+
+type PersonHandler struct {
+	People *services.PersonService
+}
+
+func NewPersonHandler(s *services.PersonService) *PersonHandler {
+	return &PersonHandler{People: s}
+}
+
+// Search handles GET /v1/search/people?q=&page=
+func (h *PersonHandler) Search(w http.ResponseWriter, r *http.Request) {
+	q := r.URL.Query().Get("q")
+	if q == "" {
+		w.WriteHeader(http.StatusBadRequest)
+		_ = json.NewEncoder(w).Encode(map[string]string{"error": "q is required"})
+		return
+	}
+	res, err := h.People.Search(r.Context(), q, queryInt(r, "page", 1))
+	if err != nil {
+		writeUpstreamError(w, err)
+		return
+	}
+	_ = json.NewEncoder(w).Encode(res)
+}
+
+// Get handles GET /v1/people/{id}?media_type=movie|tv&department=Directing
+// A person by TMDB ID with their credits, newest first, optionally narrowed
+// to one media type and department ("Acting" for cast).
+func (h *PersonHandler) Get(w http.ResponseWriter, r *http.Request) {
+	id, ok := pathInt(w, r, "id", 1)
+	if !ok {
+		return
+	}
+	type queryT struct {
+		MediaType  string `validate:"omitempty,oneof=movie tv"`
+		Department string `validate:"max=50"`
+	}
+	q := queryT{MediaType: r.URL.Query().Get("media_type"), Department: r.URL.Query().Get("department")}
+	if errs := validate.Map(q); errs != nil {
+		w.WriteHeader(http.StatusBadRequest)
+		_ = json.NewEncoder(w).Encode(errs)
+		return
+	}
+	p, err := h.People.Get(r.Context(), id)
+	if err != nil {
+		writePersonError(w, err)
+		return
+	}
+	p.Filter(q.MediaType, q.Department)
+	_ = json.NewEncoder(w).Encode(p)
+}
+
+// Follow handles POST /v1/people/{id}/follow
+func (h *PersonHandler) Follow(w http.ResponseWriter, r *http.Request) {
+	uid := auth.UserID(r.Context())
+	if uid == "" {
+		w.WriteHeader(http.StatusUnauthorized)
+		return
+	}
+	id, ok := pathInt(w, r, "id", 1)
+	if !ok {
+		return
+	}
+	if err := h.People.Follow(r.Context(), uid, id); err != nil {
+		writePersonError(w, err)
+		return
+	}
+	w.WriteHeader(http.StatusNoContent)
+}
+
+// Unfollow handles DELETE /v1/people/{id}/follow
+func (h *PersonHandler) Unfollow(w http.ResponseWriter, r *http.Request) {
+	uid := auth.UserID(r.Context())
+	if uid == "" {
+		w.WriteHeader(http.StatusUnauthorized)
+		return
+	}
+	id, ok := pathInt(w, r, "id", 1)
+	if !ok {
+		return
+	}
+	if err := h.People.Unfollow(r.Context(), uid, id); err != nil {
+		writePersonError(w, err)
+		return
+	}
+	w.WriteHeader(http.StatusNoContent)
+}
+
+// Following handles GET /v1/people/{id}/follow, {"following":true|false}
+func (h *PersonHandler) Following(w http.ResponseWriter, r *http.Request) {
+	uid := auth.UserID(r.Context())
+	if uid == "" {
+		w.WriteHeader(http.StatusUnauthorized)
+		return
+	}
+	id, ok := pathInt(w, r, "id", 1)
+	if !ok {
+		return
+	}
+	following, err := h.People.IsFollowing(r.Context(), uid, id)
+	if err != nil {
+		writePersonError(w, err)
+		return
+	}
+	_ = json.NewEncoder(w).Encode(map[string]bool{"following": following})
+}
+
+// Followed handles GET /v1/me/people?page=&limit=
+func (h *PersonHandler) Followed(w http.ResponseWriter, r *http.Request) {
+	uid := auth.UserID(r.Context())
+	if uid == "" {
+		w.WriteHeader(http.StatusUnauthorized)
+		return
+	}
+	page, err := h.People.Followed(r.Context(), uid, queryInt(r, "page", 1), queryInt(r, "limit", 20))
+	if err != nil {
+		writePersonError(w, err)
+		return
+	}
+	_ = json.NewEncoder(w).Encode(page)
+}
+
+func writePersonError(w http.ResponseWriter, err error) {
+	switch {
+	case errors.Is(err, services.ErrUnauthorized):
+		w.WriteHeader(http.StatusUnauthorized)
+	case errors.Is(err, gorm.ErrRecordNotFound):
+		w.WriteHeader(http.StatusNotFound)
+	default:
+		writeUpstreamError(w, err)
+		return
+	}
+	_ = json.NewEncoder(w).Encode(map[string]string{"error": err.Error()})
+}
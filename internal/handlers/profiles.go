@@ -0,0 +1,68 @@
+package handlers
+
+import (
+	"encoding/json"
+	"errors"
+	"net/http"
+
+	"github.com/go-chi/chi/v5"
+	"github.com/google/uuid"
+	"gorm.io/gorm"
+
+	"github.com/Dubjay18/scenee/internal/auth"
+	"github.com/Dubjay18/scenee/internal/services"
+)
+
+type ProfileHandler struct {
+	Service *services.ProfileService
+}
+
+func NewProfileHandler(s *services.ProfileService) *ProfileHandler {
+	return &ProfileHandler{Service: s}
+}
+
+// Get handles GET /v1/users/{id}
+func (h *ProfileHandler) Get(w http.ResponseWriter, r *http.Request) {
+	uid := auth.UserID(r.Context())
+	if uid == "" {
+		w.WriteHeader(http.StatusUnauthorized)
+		return
+	}
+	id := chi.URLParam(r, "id")
+	if _, err := uuid.Parse(id); err != nil {
+		writeProfileError(w, gorm.ErrRecordNotFound)
+		return
+	}
+	p, err := h.Service.Get(r.Context(), uid, id)
+	if err != nil {
+		writeProfileError(w, err)
+		return
+	}
+	_ = json.NewEncoder(w).Encode(p)
+}
+
+// GetByUsername handles GET /v1/users/by-username/{username}
+func (h *ProfileHandler) GetByUsername(w http.ResponseWriter, r *http.Request) {
+	uid := auth.UserID(r.Context())
+	if uid == "" {
+		w.WriteHeader(http.StatusUnauthorized)
+		return
+	}
+	p, err := h.Service.GetByUsername(r.Context(), uid, chi.URLParam(r, "username"))
+	if err != nil {
+		writeProfileError(w, err)
+		return
+	}
+	_ = json.NewEncoder(w).Encode(p)
+}
+
+func writeProfileError(w http.ResponseWriter, err error) {
+	switch {
+	case errors.Is(err, gorm.ErrRecordNotFound):
+		w.WriteHeader(http.StatusNotFound)
+		_ = json.NewEncoder(w).Encode(map[string]string{"error": "user not found"})
+	default:
+		w.WriteHeader(http.StatusInternalServerError)
+		_ = json.NewEncoder(w).Encode(map[string]string{"error": "failed to load profile"})
+	}
+}
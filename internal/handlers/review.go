@@ -2,13 +2,17 @@ package handlers
 
 import (
 	"encoding/json"
+	"errors"
 	"net/http"
+	"strings"
 
 	"github.com/Dubjay18/scenee/internal/auth"
 	"github.com/Dubjay18/scenee/internal/models"
 	"github.com/Dubjay18/scenee/internal/services"
+	"github.com/Dubjay18/scenee/internal/validate"
 	"github.com/go-chi/chi/v5"
 	"github.com/google/uuid"
+	"gorm.io/gorm"
 )
 
 type ReviewHandler struct {
@@ -34,26 +38,44 @@ func (h *ReviewHandler) Create(w http.ResponseWriter, r *http.Request) {
 	}
 
 	var body struct {
-		Rating int    `json:"rating" validate:"required,min=1,max=10"`
-		Review string `json:"review"`
+		Rating          int      `json:"rating" validate:"required,min=1,max=10"`
+		Review          string   `json:"review" validate:"max=20000,textmax=5000"`
+		Spoiler         bool     `json:"spoiler"`
+		ContentWarnings []string `json:"content_warnings" validate:"max=5"`
 	}
 	if err := json.NewDecoder(r.Body).Decode(&body); err != nil {
 		w.WriteHeader(http.StatusBadRequest)
 		_ = json.NewEncoder(w).Encode(map[string]string{"error": "invalid json"})
 		return
 	}
+	if errs := validate.Map(body); errs != nil {
+		w.WriteHeader(http.StatusBadRequest)
+		_ = json.NewEncoder(w).Encode(errs)
+		return
+	}
 
 	userID, _ := uuid.Parse(uid)
 	review := &models.Review{
-		UserID:  userID,
-		MovieID: movieID,
-		Rating:  body.Rating,
-		Review:  body.Review,
+		UserID:          userID,
+		MovieID:         movieID,
+		Rating:          body.Rating,
+		Review:          body.Review,
+		Spoiler:         body.Spoiler,
+		ContentWarnings: body.ContentWarnings,
 	}
 
 	if err := h.Service.Create(r.Context(), review); err != nil {
-		w.WriteHeader(http.StatusInternalServerError)
-		_ = json.NewEncoder(w).Encode(map[string]string{"error": "failed to create review"})
+		switch {
+		case errors.Is(err, services.ErrUnknownContentWarning):
+			w.WriteHeader(http.StatusBadRequest)
+			_ = json.NewEncoder(w).Encode(map[string]string{"error": err.Error()})
+		case errors.Is(err, services.ErrReviewExists):
+			w.WriteHeader(http.StatusConflict)
+			_ = json.NewEncoder(w).Encode(map[string]string{"error": err.Error()})
+		default:
+			w.WriteHeader(http.StatusInternalServerError)
+			_ = json.NewEncoder(w).Encode(map[string]string{"error": "failed to create review"})
+		}
 		return
 	}
 
@@ -61,15 +83,31 @@ func (h *ReviewHandler) Create(w http.ResponseWriter, r *http.Request) {
 	_ = json.NewEncoder(w).Encode(review)
 }
 
+// GetByMovie handles GET /v1/movies/{id}/reviews?sort=recent|popular|rating&following=true&page=1&limit=20
 func (h *ReviewHandler) GetByMovie(w http.ResponseWriter, r *http.Request) {
-	movieID := chi.URLParam(r, "id")
-	reviews, err := h.Service.GetByMovieID(r.Context(), movieID)
+	type queryT struct {
+		Sort      string `validate:"omitempty,oneof=recent popular rating"`
+		Following bool
+		Page      int `validate:"gte=1"`
+		Limit     int `validate:"gte=1,lte=100"`
+	}
+	q := queryT{
+		Sort:      r.URL.Query().Get("sort"),
+		Following: r.URL.Query().Get("following") == "true",
+		Page:      queryInt(r, "page", 1),
+		Limit:     queryInt(r, "limit", 20),
+	}
+	if errs := validate.Map(q); errs != nil {
+		w.WriteHeader(http.StatusBadRequest)
+		_ = json.NewEncoder(w).Encode(errs)
+		return
+	}
+	page, err := h.Service.ListByMovie(r.Context(), auth.UserID(r.Context()), chi.URLParam(r, "id"), q.Sort, q.Following, q.Page, q.Limit)
 	if err != nil {
-		w.WriteHeader(http.StatusInternalServerError)
-		_ = json.NewEncoder(w).Encode(map[string]string{"error": "failed to get reviews"})
+		writeReviewError(w, err)
 		return
 	}
-	_ = json.NewEncoder(w).Encode(reviews)
+	_ = json.NewEncoder(w).Encode(page)
 }
 
 func (h *ReviewHandler) Update(w http.ResponseWriter, r *http.Request) {
@@ -80,14 +118,21 @@ func (h *ReviewHandler) Update(w http.ResponseWriter, r *http.Request) {
 	}
 
 	var body struct {
-		Rating int    `json:"rating" validate:"min=1,max=10"`
-		Review string `json:"review"`
+		Rating          int       `json:"rating" validate:"omitempty,min=1,max=10"`
+		Review          string    `json:"review" validate:"max=20000,textmax=5000"`
+		Spoiler         *bool     `json:"spoiler"`
+		ContentWarnings *[]string `json:"content_warnings" validate:"omitempty,max=5"`
 	}
 	if err := json.NewDecoder(r.Body).Decode(&body); err != nil {
 		w.WriteHeader(http.StatusBadRequest)
 		_ = json.NewEncoder(w).Encode(map[string]string{"error": "invalid json"})
 		return
 	}
+	if errs := validate.Map(body); errs != nil {
+		w.WriteHeader(http.StatusBadRequest)
+		_ = json.NewEncoder(w).Encode(errs)
+		return
+	}
 
 	// Get existing review
 	existing, err := h.Service.GetByUserAndMovie(r.Context(), uid, chi.URLParam(r, "id"))
@@ -101,8 +146,19 @@ func (h *ReviewHandler) Update(w http.ResponseWriter, r *http.Request) {
 		existing.Rating = body.Rating
 	}
 	existing.Review = body.Review
+	if body.Spoiler != nil {
+		existing.Spoiler = *body.Spoiler
+	}
+	if body.ContentWarnings != nil {
+		existing.ContentWarnings = *body.ContentWarnings
+	}
 
 	if err := h.Service.Update(r.Context(), existing); err != nil {
+		if errors.Is(err, services.ErrUnknownContentWarning) {
+			w.WriteHeader(http.StatusBadRequest)
+			_ = json.NewEncoder(w).Encode(map[string]string{"error": err.Error()})
+			return
+		}
 		w.WriteHeader(http.StatusInternalServerError)
 		_ = json.NewEncoder(w).Encode(map[string]string{"error": "failed to update review"})
 		return
@@ -125,3 +181,113 @@ func (h *ReviewHandler) Delete(w http.ResponseWriter, r *http.Request) {
 	}
 	w.WriteHeader(http.StatusNoContent)
 }
+
+// Like handles POST /v1/movies/{id}/reviews/{reviewID}/like
+func (h *ReviewHandler) Like(w http.ResponseWriter, r *http.Request) {
+	uid := auth.UserID(r.Context())
+	if uid == "" {
+		w.WriteHeader(http.StatusUnauthorized)
+		return
+	}
+	if err := h.Service.Like(r.Context(), uid, chi.URLParam(r, "id"), chi.URLParam(r, "reviewID")); err != nil {
+		writeReviewError(w, err)
+		return
+	}
+	w.WriteHeader(http.StatusNoContent)
+}
+
+// Unlike handles DELETE /v1/movies/{id}/reviews/{reviewID}/like
+func (h *ReviewHandler) Unlike(w http.ResponseWriter, r *http.Request) {
+	uid := auth.UserID(r.Context())
+	if uid == "" {
+		w.WriteHeader(http.StatusUnauthorized)
+		return
+	}
+	if err := h.Service.Unlike(r.Context(), uid, chi.URLParam(r, "id"), chi.URLParam(r, "reviewID")); err != nil {
+		writeReviewError(w, err)
+		return
+	}
+	w.WriteHeader(http.StatusNoContent)
+}
+
+// Replies handles GET /v1/movies/{id}/reviews/{reviewID}/replies?page=1&limit=20
+func (h *ReviewHandler) Replies(w http.ResponseWriter, r *http.Request) {
+	type queryT struct {
+		Page  int `validate:"gte=1"`
+		Limit int `validate:"gte=1,lte=100"`
+	}
+	q := queryT{Page: queryInt(r, "page", 1), Limit: queryInt(r, "limit", 20)}
+	if errs := validate.Map(q); errs != nil {
+		w.WriteHeader(http.StatusBadRequest)
+		_ = json.NewEncoder(w).Encode(errs)
+		return
+	}
+	thread, err := h.Service.Replies(r.Context(), auth.UserID(r.Context()), chi.URLParam(r, "id"), chi.URLParam(r, "reviewID"), q.Page, q.Limit)
+	if err != nil {
+		writeReviewError(w, err)
+		return
+	}
+	_ = json.NewEncoder(w).Encode(thread)
+}
+
+// Reply handles POST /v1/movies/{id}/reviews/{reviewID}/replies {"body":"..."}
+func (h *ReviewHandler) Reply(w http.ResponseWriter, r *http.Request) {
+	uid := auth.UserID(r.Context())
+	if uid == "" {
+		w.WriteHeader(http.StatusUnauthorized)
+		return
+	}
+	var body struct {
+		Body string `json:"body" validate:"required,min=1,max=2000"`
+	}
+	if err := json.NewDecoder(r.Body).Decode(&body); err != nil {
+		w.WriteHeader(http.StatusBadRequest)
+		_ = json.NewEncoder(w).Encode(map[string]string{"error": "invalid json"})
+		return
+	}
+	body.Body = strings.TrimSpace(body.Body) // so a blank body fails "required"
+	if errs := validate.Map(body); errs != nil {
+		w.WriteHeader(http.StatusBadRequest)
+		_ = json.NewEncoder(w).Encode(errs)
+		return
+	}
+	reply, err := h.Service.Reply(r.Context(), uid, chi.URLParam(r, "id"), chi.URLParam(r, "reviewID"), body.Body)
+	if err != nil {
+		writeReviewError(w, err)
+		return
+	}
+	w.WriteHeader(http.StatusCreated)
+	_ = json.NewEncoder(w).Encode(reply)
+}
+
+// DeleteReply handles DELETE /v1/movies/{id}/reviews/{reviewID}/replies/{replyID}
+// Allowed for the reply's author and the review's author.
+func (h *ReviewHandler) DeleteReply(w http.ResponseWriter, r *http.Request) {
+	uid := auth.UserID(r.Context())
+	if uid == "" {
+		w.WriteHeader(http.StatusUnauthorized)
+		return
+	}
+	err := h.Service.DeleteReply(r.Context(), uid, chi.URLParam(r, "id"), chi.URLParam(r, "reviewID"), chi.URLParam(r, "replyID"))
+	if err != nil {
+		writeReviewError(w, err)
+		return
+	}
+	w.WriteHeader(http.StatusNoContent)
+}
+
+func writeReviewError(w http.ResponseWriter, err error) {
+	switch {
+	case errors.Is(err, services.ErrUnauthorized):
+		w.WriteHeader(http.StatusUnauthorized)
+	case errors.Is(err, services.ErrForbidden), errors.Is(err, services.ErrBlocked):
+		w.WriteHeader(http.StatusForbidden)
+	case errors.Is(err, services.ErrEmptyReply):
+		w.WriteHeader(http.StatusBadRequest)
+	case errors.Is(err, gorm.ErrRecordNotFound):
+		w.WriteHeader(http.StatusNotFound)
+	default:
+		w.WriteHeader(http.StatusInternalServerError)
+	}
+	_ = json.NewEncoder(w).Encode(map[string]string{"error": err.Error()})
+}
@@ -6,6 +6,7 @@ import (
 	"strconv"
 
 	"github.com/go-chi/chi/v5"
+	"github.com/Dubjay18/scenee/internal/auth"
 	"github.com/Dubjay18/scenee/internal/services"
 	"github.com/Dubjay18/scenee/internal/validate"
 )
@@ -27,9 +28,15 @@ func (h *SearchHandler) Routes(r chi.Router) {
 	r.Get("/", h.search)
 }
 
+// searchPageSize is the page size for user and watchlist results.
+const searchPageSize = 20
+
 // search handles GET /v1/search?q=...&type=movie|user|watchlist
-// Searches across movies, users, or watchlists based on type parameter
+// Searches across movies, users, or watchlists based on type parameter.
+// Users and watchlists blocked either way are left out of the results.
 func (h *SearchHandler) search(w http.ResponseWriter, r *http.Request) {
+	uid := auth.UserID(r.Context())
+
 	type queryT struct {
 		Q    string `validate:"required,min=1"`
 		Type string `validate:"required,oneof=movie user watchlist"`
@@ -62,27 +69,32 @@ func (h *SearchHandler) search(w http.ResponseWriter, r *http.Request) {
 		// Search movies using TMDb
 		result, err = h.WatchlistService.SearchMovies(r.Context(), q.Q, q.Page)
 		if err != nil {
-			w.WriteHeader(http.StatusBadGateway)
-			_ = json.NewEncoder(w).Encode(map[string]string{"error": err.Error()})
+			writeUpstreamError(w, err)
 			return
 		}
 
 	case "user":
-		// TODO: Implement SearchUsers method in UserService
-		// users, searchErr := h.UserService.SearchUsers(r.Context(), q.Q, 20, (q.Page-1)*20)
+		users, searchErr := h.UserService.SearchUsers(r.Context(), uid, q.Q, searchPageSize, (q.Page-1)*searchPageSize)
+		if searchErr != nil {
+			w.WriteHeader(http.StatusInternalServerError)
+			_ = json.NewEncoder(w).Encode(map[string]string{"error": searchErr.Error()})
+			return
+		}
 		result = map[string]interface{}{
-			"results": []interface{}{},
+			"results": users,
 			"page":    q.Page,
-			"message": "User search not yet implemented",
 		}
 
 	case "watchlist":
-		// TODO: Implement SearchWatchlists method in WatchlistService
-		// watchlists, searchErr := h.WatchlistService.SearchWatchlists(r.Context(), q.Q, 20, (q.Page-1)*20)
+		watchlists, searchErr := h.WatchlistService.SearchWatchlists(r.Context(), uid, q.Q, searchPageSize, (q.Page-1)*searchPageSize)
+		if searchErr != nil {
+			w.WriteHeader(http.StatusInternalServerError)
+			_ = json.NewEncoder(w).Encode(map[string]string{"error": searchErr.Error()})
+			return
+		}
 		result = map[string]interface{}{
-			"results": []interface{}{},
+			"results": watchlists,
 			"page":    q.Page,
-			"message": "Watchlist search not yet implemented",
 		}
 
 	default:
@@ -92,7 +104,8 @@ func (h *SearchHandler) search(w http.ResponseWriter, r *http.Request) {
 	}
 
 	w.Header().Set("Content-Type", "application/json")
-	w.Header().Set("Cache-Control", "public, max-age=60")
+	// Results depend on the viewer's blocks
+	w.Header().Set("Cache-Control", "private, max-age=60")
 	_ = json.NewEncoder(w).Encode(result)
 }
 
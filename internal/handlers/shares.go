@@ -0,0 +1,84 @@
+package handlers
+
+import (
+	"encoding/json"
+	"errors"
+	"net/http"
+
+	"github.com/go-chi/chi/v5"
+	"gorm.io/gorm"
+
+	"github.com/Dubjay18/scenee/internal/auth"
+	"github.com/Dubjay18/scenee/internal/services"
+	"github.com/Dubjay18/scenee/internal/validate"
+)
+
+type ShareHandler struct {
+	Service *services.ShareService
+}
+
+func NewShareHandler(s *services.ShareService) *ShareHandler {
+	return &ShareHandler{Service: s}
+}
+
+// Share handles POST /v1/watchlists/{id}/share
+// {"recipients":["<userId>",...], "message":"..."}
+func (h *ShareHandler) Share(w http.ResponseWriter, r *http.Request) {
+	uid := auth.UserID(r.Context())
+	if uid == "" {
+		w.WriteHeader(http.StatusUnauthorized)
+		return
+	}
+	var body struct {
+		Recipients []string `json:"recipients" validate:"required,min=1,max=20"`
+		Message    string   `json:"message" validate:"max=500"`
+	}
+	if err := json.NewDecoder(r.Body).Decode(&body); err != nil {
+		w.WriteHeader(http.StatusBadRequest)
+		_ = json.NewEncoder(w).Encode(map[string]string{"error": "invalid json"})
+		return
+	}
+	if errs := validate.Map(body); errs != nil {
+		w.WriteHeader(http.StatusBadRequest)
+		_ = json.NewEncoder(w).Encode(errs)
+		return
+	}
+	shares, err := h.Service.Share(r.Context(), uid, chi.URLParam(r, "id"), body.Recipients, body.Message)
+	if err != nil {
+		writeShareError(w, err)
+		return
+	}
+	w.WriteHeader(http.StatusCreated)
+	_ = json.NewEncoder(w).Encode(shares)
+}
+
+// Inbox handles GET /v1/me/shared?page=&limit=
+func (h *ShareHandler) Inbox(w http.ResponseWriter, r *http.Request) {
+	uid := auth.UserID(r.Context())
+	if uid == "" {
+		w.WriteHeader(http.StatusUnauthorized)
+		return
+	}
+	page, err := h.Service.Inbox(r.Context(), uid, queryInt(r, "page", 1), queryInt(r, "limit", 20))
+	if err != nil {
+		writeShareError(w, err)
+		return
+	}
+	_ = json.NewEncoder(w).Encode(page)
+}
+
+func writeShareError(w http.ResponseWriter, err error) {
+	switch {
+	case errors.Is(err, services.ErrUnauthorized):
+		w.WriteHeader(http.StatusUnauthorized)
+	case errors.Is(err, services.ErrShareSelf), errors.Is(err, services.ErrUnknownRecipient), errors.Is(err, services.ErrNoRecipients):
+		w.WriteHeader(http.StatusBadRequest)
+	case errors.Is(err, services.ErrForbidden), errors.Is(err, services.ErrBlocked):
+		w.WriteHeader(http.StatusForbidden)
+	case errors.Is(err, gorm.ErrRecordNotFound):
+		w.WriteHeader(http.StatusNotFound)
+	default:
+		w.WriteHeader(http.StatusInternalServerError)
+	}
+	_ = json.NewEncoder(w).Encode(map[string]string{"error": err.Error()})
+}
@@ -0,0 +1,69 @@
+package handlers
+
+import (
+	"encoding/json"
+	"errors"
+	"net/http"
+
+	"github.com/go-chi/chi/v5"
+	"gorm.io/gorm"
+
+	"github.com/Dubjay18/scenee/internal/auth"
+	"github.com/Dubjay18/scenee/internal/services"
+)
+
+type SuggestionHandler struct {
+	Service *services.SuggestionService
+}
+
+func NewSuggestionHandler(s *services.SuggestionService) *SuggestionHandler {
+	return &SuggestionHandler{Service: s}
+}
+
+// Routes is mounted under /me/suggestions in main.
+func (h *SuggestionHandler) Routes(r chi.Router) {
+	r.Get("/", h.List)
+	r.Delete("/{id}", h.Dismiss)
+}
+
+// List handles GET /v1/me/suggestions?limit=
+func (h *SuggestionHandler) List(w http.ResponseWriter, r *http.Request) {
+	uid := auth.UserID(r.Context())
+	if uid == "" {
+		w.WriteHeader(http.StatusUnauthorized)
+		return
+	}
+	out, err := h.Service.Suggest(r.Context(), uid, queryInt(r, "limit", 10))
+	if err != nil {
+		writeSuggestionError(w, err)
+		return
+	}
+	_ = json.NewEncoder(w).Encode(out)
+}
+
+// Dismiss handles DELETE /v1/me/suggestions/{id} where id is the suggested
+// user.
+func (h *SuggestionHandler) Dismiss(w http.ResponseWriter, r *http.Request) {
+	uid := auth.UserID(r.Context())
+	if uid == "" {
+		w.WriteHeader(http.StatusUnauthorized)
+		return
+	}
+	if err := h.Service.Dismiss(r.Context(), uid, chi.URLParam(r, "id")); err != nil {
+		writeSuggestionError(w, err)
+		return
+	}
+	w.WriteHeader(http.StatusNoContent)
+}
+
+func writeSuggestionError(w http.ResponseWriter, err error) {
+	switch {
+	case errors.Is(err, services.ErrUnauthorized):
+		w.WriteHeader(http.StatusUnauthorized)
+	case errors.Is(err, gorm.ErrRecordNotFound):
+		w.WriteHeader(http.StatusNotFound)
+	default:
+		w.WriteHeader(http.StatusInternalServerError)
+	}
+	_ = json.NewEncoder(w).Encode(map[string]string{"error": err.Error()})
+}
@@ -0,0 +1,100 @@
+package handlers
+
+import (
+	"encoding/json"
+	"net/http"
+	"strconv"
+
+	"github.com/go-chi/chi/v5"
+
+	"github.com/Dubjay18/scenee/internal/services"
+	"github.com/Dubjay18/scenee/internal/validate"
+)
+
+type TagHandler struct {
+	Service *services.TagService
+}
+
+func NewTagHandler(s *services.TagService) *TagHandler {
+	return &TagHandler{Service: s}
+}
+
+// Routes is mounted under /tags in main.
+func (h *TagHandler) Routes(r chi.Router) {
+	r.Get("/", h.autocomplete)
+	r.Get("/popular", h.popular)
+	r.Get("/{tag}/watchlists", h.watchlists)
+}
+
+func queryInt(r *http.Request, key string, def int) int {
+	if v := r.URL.Query().Get(key); v != "" {
+		if n, err := strconv.Atoi(v); err == nil {
+			return n
+		}
+	}
+	return def
+}
+
+// autocomplete handles GET /v1/tags?q=hor&limit=10
+func (h *TagHandler) autocomplete(w http.ResponseWriter, r *http.Request) {
+	type queryT struct {
+		Q     string `validate:"required,max=50"`
+		Limit int    `validate:"gte=1,lte=50"`
+	}
+	q := queryT{Q: r.URL.Query().Get("q"), Limit: queryInt(r, "limit", 10)}
+	if errs := validate.Map(q); errs != nil {
+		w.WriteHeader(http.StatusBadRequest)
+		_ = json.NewEncoder(w).Encode(errs)
+		return
+	}
+	out, err := h.Service.Autocomplete(r.Context(), q.Q, q.Limit)
+	if err != nil {
+		w.WriteHeader(http.StatusInternalServerError)
+		_ = json.NewEncoder(w).Encode(map[string]string{"error": err.Error()})
+		return
+	}
+	_ = json.NewEncoder(w).Encode(out)
+}
+
+// popular handles GET /v1/tags/popular?limit=20
+func (h *TagHandler) popular(w http.ResponseWriter, r *http.Request) {
+	type queryT struct {
+		Limit int `validate:"gte=1,lte=100"`
+	}
+	q := queryT{Limit: queryInt(r, "limit", 20)}
+	if errs := validate.Map(q); errs != nil {
+		w.WriteHeader(http.StatusBadRequest)
+		_ = json.NewEncoder(w).Encode(errs)
+		return
+	}
+	out, err := h.Service.Popular(r.Context(), q.Limit)
+	if err != nil {
+		w.WriteHeader(http.StatusInternalServerError)
+		_ = json.NewEncoder(w).Encode(map[string]string{"error": err.Error()})
+		return
+	}
+	w.Header().Set("Cache-Control", "public, max-age=300")
+	_ = json.NewEncoder(w).Encode(out)
+}
+
+// watchlists handles GET /v1/tags/{tag}/watchlists?sort=popular|recent&page=1&limit=20
+func (h *TagHandler) watchlists(w http.ResponseWriter, r *http.Request) {
+	type queryT struct {
+		Sort  string `validate:"omitempty,oneof=popular recent"`
+		Page  int    `validate:"gte=1"`
+		Limit int    `validate:"gte=1,lte=100"`
+	}
+	q := queryT{Sort: r.URL.Query().Get("sort"), Page: queryInt(r, "page", 1), Limit: queryInt(r, "limit", 20)}
+	if errs := validate.Map(q); errs != nil {
+		w.WriteHeader(http.StatusBadRequest)
+		_ = json.NewEncoder(w).Encode(errs)
+		return
+	}
+	page, err := h.Service.Watchlists(r.Context(), chi.URLParam(r, "tag"), q.Sort, q.Page, q.Limit)
+	if err != nil {
+		w.WriteHeader(http.StatusInternalServerError)
+		_ = json.NewEncoder(w).Encode(map[string]string{"error": err.Error()})
+		return
+	}
+	_ = json.NewEncoder(w).Encode(page)
+}
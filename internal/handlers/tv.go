@@ -0,0 +1,186 @@
+package handlers
+
+import (
+	"encoding/json"
+	"errors"
+	"net/http"
+	"strconv"
+
+	"github.com/go-chi/chi/v5"
+	"gorm.io/gorm"
+
+	"github.com/Dubjay18/scenee/internal/auth"
+	"github.com/Dubjay18/scenee/internal/services"
+)
+
+type TVHandler struct {
+	Series *services.SeriesService
+}
+
+func NewTVHandler(s *services.SeriesService) *TVHandler {
+	return &TVHandler{Series: s}
+}
+
+// Routes mounts the public series pages under /v1/tv.
+func (h *TVHandler) Routes(r chi.Router) {
+	r.Get("/{id}", h.Get)
+	r.Get("/{id}/seasons/{season}", h.Season)
+}
+
+// MeRoutes mounts the viewer's episode tracking under /v1/me/tv.
+func (h *TVHandler) MeRoutes(r chi.Router) {
+	r.Get("/{id}/progress", h.Progress)
+	r.Get("/{id}/seasons/{season}", h.Season)
+	r.Post("/{id}/seasons/{season}/watched", h.markSeason(true))
+	r.Delete("/{id}/seasons/{season}/watched", h.markSeason(false))
+	r.Post("/{id}/seasons/{season}/episodes/{episode}/watched", h.markEpisode(true))
+	r.Delete("/{id}/seasons/{season}/episodes/{episode}/watched", h.markEpisode(false))
+}
+
+// Search handles GET /v1/search/tv?q=&page=
+func (h *TVHandler) Search(w http.ResponseWriter, r *http.Request) {
+	q := r.URL.Query().Get("q")
+	if q == "" {
+		w.WriteHeader(http.StatusBadRequest)
+		_ = json.NewEncoder(w).Encode(map[string]string{"error": "q is required"})
+		return
+	}
+	res, err := h.Series.Search(r.Context(), q, queryInt(r, "page", 1))
+	if err != nil {
+		writeUpstreamError(w, err)
+		return
+	}
+	_ = json.NewEncoder(w).Encode(res)
+}
+
+// Get handles GET /v1/tv/{id}, a series by TMDB ID with its seasons.
+func (h *TVHandler) Get(w http.ResponseWriter, r *http.Request) {
+	id, ok := pathInt(w, r, "id", 1)
+	if !ok {
+		return
+	}
+	series, err := h.Series.Series(r.Context(), id)
+	if err != nil {
+		writeTVError(w, err)
+		return
+	}
+	_ = json.NewEncoder(w).Encode(series)
+}
+
+// Season handles GET /v1/tv/{id}/seasons/{season} and, with the viewer's
+// watched flags, GET /v1/me/tv/{id}/seasons/{season}.
+func (h *TVHandler) Season(w http.ResponseWriter, r *http.Request) {
+	id, ok := pathInt(w, r, "id", 1)
+	if !ok {
+		return
+	}
+	season, ok := pathInt(w, r, "season", 0)
+	if !ok {
+		return
+	}
+	view, err := h.Series.Season(r.Context(), auth.UserID(r.Context()), id, season)
+	if err != nil {
+		writeTVError(w, err)
+		return
+	}
+	_ = json.NewEncoder(w).Encode(view)
+}
+
+// Progress handles GET /v1/me/tv/{id}/progress
+func (h *TVHandler) Progress(w http.ResponseWriter, r *http.Request) {
+	uid := auth.UserID(r.Context())
+	if uid == "" {
+		w.WriteHeader(http.StatusUnauthorized)
+		return
+	}
+	id, ok := pathInt(w, r, "id", 1)
+	if !ok {
+		return
+	}
+	progress, err := h.Series.Progress(r.Context(), uid, id)
+	if err != nil {
+		writeTVError(w, err)
+		return
+	}
+	_ = json.NewEncoder(w).Encode(progress)
+}
+
+// markSeason handles POST and DELETE /v1/me/tv/{id}/seasons/{season}/watched.
+// Marking covers the episodes aired so far.
+func (h *TVHandler) markSeason(watched bool) http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		uid := auth.UserID(r.Context())
+		if uid == "" {
+			w.WriteHeader(http.StatusUnauthorized)
+			return
+		}
+		id, ok := pathInt(w, r, "id", 1)
+		if !ok {
+			return
+		}
+		season, ok := pathInt(w, r, "season", 0)
+		if !ok {
+			return
+		}
+		n, err := h.Series.SetSeasonWatched(r.Context(), uid, id, season, watched)
+		if err != nil {
+			writeTVError(w, err)
+			return
+		}
+		_ = json.NewEncoder(w).Encode(map[string]int{"episodes": n})
+	}
+}
+
+// markEpisode handles POST and DELETE
+// /v1/me/tv/{id}/seasons/{season}/episodes/{episode}/watched
+func (h *TVHandler) markEpisode(watched bool) http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		uid := auth.UserID(r.Context())
+		if uid == "" {
+			w.WriteHeader(http.StatusUnauthorized)
+			return
+		}
+		id, ok := pathInt(w, r, "id", 1)
+		if !ok {
+			return
+		}
+		season, ok := pathInt(w, r, "season", 0)
+		if !ok {
+			return
+		}
+		episode, ok := pathInt(w, r, "episode", 1)
+		if !ok {
+			return
+		}
+		if err := h.Series.SetEpisodeWatched(r.Context(), uid, id, season, episode, watched); err != nil {
+			writeTVError(w, err)
+			return
+		}
+		w.WriteHeader(http.StatusNoContent)
+	}
+}
+
+// pathInt reads an integer URL parameter of at least minimum, writing a 400
+// when it isn't one.
+func pathInt(w http.ResponseWriter, r *http.Request, key string, minimum int) (int, bool) {
+	n, err := strconv.Atoi(chi.URLParam(r, key))
+	if err != nil || n < minimum {
+		w.WriteHeader(http.StatusBadRequest)
+		_ = json.NewEncoder(w).Encode(map[string]string{"error": key + " must be an integer of at least " + strconv.Itoa(minimum)})
+		return 0, false
+	}
+	return n, true
+}
+
+func writeTVError(w http.ResponseWriter, err error) {
+	switch {
+	case errors.Is(err, services.ErrUnauthorized):
+		w.WriteHeader(http.StatusUnauthorized)
+	case errors.Is(err, gorm.ErrRecordNotFound):
+		w.WriteHeader(http.StatusNotFound)
+	default:
+		writeUpstreamError(w, err)
+		return
+	}
+	_ = json.NewEncoder(w).Encode(map[string]string{"error": err.Error()})
+}
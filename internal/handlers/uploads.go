@@ -0,0 +1,41 @@
+package handlers
+
+import (
+	"encoding/json"
+	"errors"
+	"mime/multipart"
+	"net/http"
+
+	"github.com/Dubjay18/scenee/internal/uploads"
+)
+
+// formImage returns the multipart "file" field of an image upload. The body
+// is capped a little above the image limit to leave room for the multipart
+// framing; the exact limit is enforced by uploads.DecodeImage.
+func formImage(w http.ResponseWriter, r *http.Request) (multipart.File, bool) {
+	r.Body = http.MaxBytesReader(w, r.Body, uploads.MaxImageBytes+1<<10)
+	file, _, err := r.FormFile("file")
+	if err != nil {
+		var tooLarge *http.MaxBytesError
+		if errors.As(err, &tooLarge) {
+			w.WriteHeader(http.StatusRequestEntityTooLarge)
+		} else {
+			w.WriteHeader(http.StatusBadRequest)
+		}
+		_ = json.NewEncoder(w).Encode(map[string]string{"error": "multipart field \"file\" is required (max 5MB)"})
+		return nil, false
+	}
+	return file, true
+}
+
+// uploadStatus maps image validation errors to a status code, or 0 when err
+// is not one of them.
+func uploadStatus(err error) int {
+	switch {
+	case errors.Is(err, uploads.ErrTooLarge):
+		return http.StatusRequestEntityTooLarge
+	case errors.Is(err, uploads.ErrUnsupported):
+		return http.StatusUnsupportedMediaType
+	}
+	return 0
+}
@@ -2,15 +2,23 @@ package handlers
 
 import (
 	"encoding/json"
+	"errors"
 	"net/http"
 
+	"gorm.io/gorm"
+
 	"github.com/Dubjay18/scenee/internal/auth"
 	"github.com/Dubjay18/scenee/internal/services"
 )
 
-type UserHandler struct{ Users *services.UserService }
+type UserHandler struct {
+	Users   *services.UserService
+	Avatars *services.AvatarService
+}
 
-func NewUserHandler(s *services.UserService) *UserHandler { return &UserHandler{Users: s} }
+func NewUserHandler(s *services.UserService, avatars *services.AvatarService) *UserHandler {
+	return &UserHandler{Users: s, Avatars: avatars}
+}
 
 func (h *UserHandler) Me(w http.ResponseWriter, r *http.Request) {
 	uid := auth.UserID(r.Context())
@@ -41,21 +49,89 @@ func (h *UserHandler) UpdateMe(w http.ResponseWriter, r *http.Request) {
 		return
 	}
 
-	// Only allow updating bio and avatar_url
-	allowedFields := map[string]bool{"bio": true, "avatar_url": true}
+	// Only allow updating bio, avatar_url and is_private
+	allowedFields := map[string]bool{"bio": true, "avatar_url": true, "is_private": true}
 	filteredUpdates := make(map[string]interface{})
 	for k, v := range updates {
 		if allowedFields[k] {
 			filteredUpdates[k] = v
 		}
 	}
+	if v, ok := filteredUpdates["is_private"]; ok {
+		if _, isBool := v.(bool); !isBool {
+			w.WriteHeader(http.StatusBadRequest)
+			_ = json.NewEncoder(w).Encode(map[string]string{"is_private": "must be a boolean"})
+			return
+		}
+	}
+	// An external avatar URL replaces (and deletes) an uploaded avatar.
+	if v, ok := filteredUpdates["avatar_url"]; ok {
+		delete(filteredUpdates, "avatar_url")
+		avatarURL, _ := v.(string)
+		if err := h.Avatars.SetExternal(r.Context(), uid, avatarURL); err != nil {
+			w.WriteHeader(http.StatusInternalServerError)
+			_ = json.NewEncoder(w).Encode(map[string]string{"error": "failed to update user"})
+			return
+		}
+	}
 
-	if err := h.Users.Update(r.Context(), uid, filteredUpdates); err != nil {
-		w.WriteHeader(http.StatusInternalServerError)
-		_ = json.NewEncoder(w).Encode(map[string]string{"error": "failed to update user"})
-		return
+	if len(filteredUpdates) > 0 {
+		if err := h.Users.Update(r.Context(), uid, filteredUpdates); err != nil {
+			w.WriteHeader(http.StatusInternalServerError)
+			_ = json.NewEncoder(w).Encode(map[string]string{"error": "failed to update user"})
+			return
+		}
 	}
 
 	w.WriteHeader(http.StatusOK)
 	_ = json.NewEncoder(w).Encode(map[string]string{"message": "profile updated"})
 }
+
+// UploadAvatar handles PUT /v1/me/avatar (multipart, field "file")
+// Stores 64, 128 and 256px square versions and returns their URLs.
+func (h *UserHandler) UploadAvatar(w http.ResponseWriter, r *http.Request) {
+	uid := auth.UserID(r.Context())
+	if uid == "" {
+		w.WriteHeader(http.StatusUnauthorized)
+		return
+	}
+	file, ok := formImage(w, r)
+	if !ok {
+		return
+	}
+	defer file.Close()
+	avatar, err := h.Avatars.Upload(r.Context(), uid, file)
+	if err != nil {
+		writeAvatarError(w, err)
+		return
+	}
+	_ = json.NewEncoder(w).Encode(avatar)
+}
+
+// DeleteAvatar handles DELETE /v1/me/avatar
+func (h *UserHandler) DeleteAvatar(w http.ResponseWriter, r *http.Request) {
+	uid := auth.UserID(r.Context())
+	if uid == "" {
+		w.WriteHeader(http.StatusUnauthorized)
+		return
+	}
+	if err := h.Avatars.Remove(r.Context(), uid); err != nil {
+		writeAvatarError(w, err)
+		return
+	}
+	w.WriteHeader(http.StatusNoContent)
+}
+
+func writeAvatarError(w http.ResponseWriter, err error) {
+	switch {
+	case uploadStatus(err) != 0:
+		w.WriteHeader(uploadStatus(err))
+	case errors.Is(err, services.ErrUnauthorized):
+		w.WriteHeader(http.StatusUnauthorized)
+	case errors.Is(err, gorm.ErrRecordNotFound):
+		w.WriteHeader(http.StatusNotFound)
+	default:
+		w.WriteHeader(http.StatusInternalServerError)
+	}
+	_ = json.NewEncoder(w).Encode(map[string]string{"error": err.Error()})
+}
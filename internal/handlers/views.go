@@ -0,0 +1,73 @@
+package handlers
+
+import (
+	"crypto/sha256"
+	"encoding/hex"
+	"log"
+	"net"
+	"net/http"
+	"strings"
+
+	"github.com/Dubjay18/scenee/internal/auth"
+	"github.com/Dubjay18/scenee/internal/models"
+)
+
+// botMarkers are user-agent fragments of crawlers and link unfurlers whose
+// hits shouldn't count as views.
+var botMarkers = []string{
+	"bot", "crawl", "spider", "slurp", "preview", "facebookexternalhit",
+	"headless", "curl/", "wget/", "python-requests", "go-http-client",
+}
+
+func isBot(userAgent string) bool {
+	ua := strings.ToLower(userAgent)
+	if ua == "" {
+		return true
+	}
+	for _, m := range botMarkers {
+		if strings.Contains(ua, m) {
+			return true
+		}
+	}
+	return false
+}
+
+// clientIP is the client's address: the peer, or the address a trusted
+// proxy forwarded, which httpserver.RealIP puts in RemoteAddr. IPv6 addresses are cut to their /64, since a device rotates
+// through addresses within it.
+func clientIP(r *http.Request) string {
+	host, _, err := net.SplitHostPort(r.RemoteAddr)
+	if err != nil {
+		host = r.RemoteAddr
+	}
+	ip := net.ParseIP(host)
+	if ip == nil {
+		return host
+	}
+	if ip.To4() == nil {
+		return ip.Mask(net.CIDRMask(64, 128)).String()
+	}
+	return ip.String()
+}
+
+// viewerKey identifies a viewer for view dedupe: the user ID when signed in,
+// otherwise a hash of client IP and user agent. Owners and bots get "".
+func viewerKey(r *http.Request, ownerID string) string {
+	if isBot(r.UserAgent()) {
+		return ""
+	}
+	if uid := auth.UserID(r.Context()); uid != "" {
+		if uid == ownerID {
+			return ""
+		}
+		return "u:" + uid
+	}
+	sum := sha256.Sum256([]byte(clientIP(r) + "|" + r.UserAgent()))
+	return "a:" + hex.EncodeToString(sum[:16])
+}
+
+func (h *WatchlistHandler) recordView(r *http.Request, wl *models.Watchlist) {
+	if err := h.Service.RecordView(r.Context(), wl.ID, viewerKey(r, wl.OwnerID)); err != nil {
+		log.Printf("Failed to record view of watchlist %s: %v", wl.ID, err)
+	}
+}
@@ -0,0 +1,94 @@
+package handlers
+
+import (
+	"encoding/json"
+	"errors"
+	"net/http"
+	"strconv"
+
+	"github.com/go-chi/chi/v5"
+	"gorm.io/gorm"
+
+	"github.com/Dubjay18/scenee/internal/auth"
+	"github.com/Dubjay18/scenee/internal/services"
+)
+
+// history handles GET /v1/watchlists/{id}/history?before=<seq>&limit=50
+// Owner-only change log, newest first. Pass the last revision's seq as before to page.
+func (h *WatchlistHandler) history(w http.ResponseWriter, r *http.Request) {
+	uid := auth.UserID(r.Context())
+	if uid == "" {
+		w.WriteHeader(http.StatusUnauthorized)
+		return
+	}
+	var before int64
+	if v := r.URL.Query().Get("before"); v != "" {
+		n, err := strconv.ParseInt(v, 10, 64)
+		if err != nil || n < 1 {
+			w.WriteHeader(http.StatusBadRequest)
+			_ = json.NewEncoder(w).Encode(map[string]string{"error": "before must be a revision seq"})
+			return
+		}
+		before = n
+	}
+	limit, _ := strconv.Atoi(r.URL.Query().Get("limit"))
+	revs, err := h.Service.History(r.Context(), uid, chi.URLParam(r, "id"), before, limit)
+	if err != nil {
+		writeHistoryError(w, err)
+		return
+	}
+	_ = json.NewEncoder(w).Encode(revs)
+}
+
+// restore handles POST /v1/watchlists/{id}/restore
+// Brings back a watchlist deleted within the retention window.
+func (h *WatchlistHandler) restore(w http.ResponseWriter, r *http.Request) {
+	uid := auth.UserID(r.Context())
+	if uid == "" {
+		w.WriteHeader(http.StatusUnauthorized)
+		return
+	}
+	wl, err := h.Service.RestoreWatchlist(r.Context(), uid, chi.URLParam(r, "id"))
+	if err != nil {
+		writeHistoryError(w, err)
+		return
+	}
+	_ = json.NewEncoder(w).Encode(wl)
+}
+
+// revert handles POST /v1/watchlists/{id}/history/{revisionId}/revert
+// Puts the list back the way it was right after the revision, undoing every
+// later change. The revert is itself recorded in the history.
+func (h *WatchlistHandler) revert(w http.ResponseWriter, r *http.Request) {
+	uid := auth.UserID(r.Context())
+	if uid == "" {
+		w.WriteHeader(http.StatusUnauthorized)
+		return
+	}
+	wl, err := h.Service.RevertRevision(r.Context(), uid, chi.URLParam(r, "id"), chi.URLParam(r, "revisionId"))
+	if err != nil {
+		writeHistoryError(w, err)
+		return
+	}
+	_ = json.NewEncoder(w).Encode(wl)
+}
+
+func writeHistoryError(w http.ResponseWriter, err error) {
+	switch {
+	case errors.Is(err, services.ErrUnauthorized):
+		w.WriteHeader(http.StatusUnauthorized)
+	case errors.Is(err, services.ErrForbidden):
+		w.WriteHeader(http.StatusForbidden)
+	case errors.Is(err, services.ErrRetentionExpired):
+		w.WriteHeader(http.StatusGone)
+	case errors.Is(err, services.ErrNotRevertible), errors.Is(err, services.ErrInvalidSlug), errors.Is(err, services.ErrNotSmartList):
+		w.WriteHeader(http.StatusBadRequest)
+	case errors.Is(err, services.ErrSlugTaken), errors.Is(err, services.ErrSmartListReadOnly):
+		w.WriteHeader(http.StatusConflict)
+	case errors.Is(err, gorm.ErrRecordNotFound):
+		w.WriteHeader(http.StatusNotFound)
+	default:
+		w.WriteHeader(http.StatusInternalServerError)
+	}
+	_ = json.NewEncoder(w).Encode(map[string]string{"error": err.Error()})
+}
@@ -5,7 +5,9 @@ import (
 	"errors"
 	"fmt"
 	"net/http"
+	"net/url"
 	"strconv"
+	"strings"
 
 	"github.com/go-chi/chi/v5"
 	"github.com/google/uuid"
@@ -14,16 +16,19 @@ import (
 	"github.com/Dubjay18/scenee/internal/auth"
 	"github.com/Dubjay18/scenee/internal/models"
 	"github.com/Dubjay18/scenee/internal/services"
+	"github.com/Dubjay18/scenee/internal/tags"
+	"github.com/Dubjay18/scenee/internal/tmdb"
 	"github.com/Dubjay18/scenee/internal/validate"
 )
 
 type WatchlistHandler struct {
 	Service *services.WatchlistService
+	Covers  *services.CoverService
 	DB      *gorm.DB
 }
 
-func NewWatchlistHandler(s *services.WatchlistService, db *gorm.DB) *WatchlistHandler {
-	return &WatchlistHandler{Service: s, DB: db}
+func NewWatchlistHandler(s *services.WatchlistService, covers *services.CoverService, db *gorm.DB) *WatchlistHandler {
+	return &WatchlistHandler{Service: s, Covers: covers, DB: db}
 }
 
 // Routes is mounted under /watchlists in main.
@@ -35,18 +40,37 @@ func (h *WatchlistHandler) Routes(r chi.Router) {
 	r.Delete("/{id}", h.delete)
 	// items
 	r.Post("/{id}/items", h.addItem)
+	r.Post("/{id}/items/batch", h.batchItems)
+	r.Patch("/{id}/items/{itemId}", h.updateItem)
 	r.Delete("/{id}/items/{itemId}", h.removeItem)
+	// history
+	r.Get("/{id}/history", h.history)
+	r.Post("/{id}/history/{revisionId}/revert", h.revert)
+	r.Post("/{id}/restore", h.restore)
+	// cover
+	r.Put("/{id}/cover", h.uploadCover)
+	r.Delete("/{id}/cover", h.deleteCover)
+	// smart lists
+	r.Post("/{id}/refresh", h.refresh)
 	// likes
 	r.Post("/{id}/like", h.like)
 	r.Delete("/{id}/like", h.unlike)
 	// save
 	r.Post("/{id}/save", h.save)
+	// fork
+	r.Post("/{id}/fork", h.fork)
+	r.Delete("/{id}/save", h.unsave)
 }
 
 func (h *WatchlistHandler) GetPublic(w http.ResponseWriter, r *http.Request) {
 	slug := chi.URLParam(r, "slug")
 	wl, err := h.Service.GetBySlug(r.Context(), slug)
 	if err != nil {
+		var moved *services.SlugMovedError
+		if errors.As(err, &moved) {
+			http.Redirect(w, r, "/v1/watchlists/public/"+moved.Watchlist.Slug, http.StatusMovedPermanently)
+			return
+		}
 		if errors.Is(err, gorm.ErrRecordNotFound) {
 			w.WriteHeader(http.StatusNotFound)
 		} else {
@@ -55,8 +79,31 @@ func (h *WatchlistHandler) GetPublic(w http.ResponseWriter, r *http.Request) {
 		_ = json.NewEncoder(w).Encode(map[string]string{"error": "watchlist not found"})
 		return
 	}
-	// Increment view count
-	h.DB.WithContext(r.Context()).Model(&models.Watchlist{}).Where("id = ?", wl.ID).Update("view_count", gorm.Expr("view_count + 1"))
+	h.recordView(r, wl)
+	_ = json.NewEncoder(w).Encode(wl)
+}
+
+// Public: GET /v1/u/{username}/{slug}
+// Vanity URL for a public or unlisted watchlist. Renamed slugs redirect permanently.
+func (h *WatchlistHandler) GetByOwnerSlug(w http.ResponseWriter, r *http.Request) {
+	username := chi.URLParam(r, "username")
+	slug := chi.URLParam(r, "slug")
+	wl, err := h.Service.GetByOwnerSlug(r.Context(), username, slug)
+	if err != nil {
+		var moved *services.SlugMovedError
+		if errors.As(err, &moved) {
+			http.Redirect(w, r, "/v1/u/"+url.PathEscape(moved.Watchlist.Owner.Username)+"/"+moved.Watchlist.Slug, http.StatusMovedPermanently)
+			return
+		}
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			w.WriteHeader(http.StatusNotFound)
+		} else {
+			w.WriteHeader(http.StatusInternalServerError)
+		}
+		_ = json.NewEncoder(w).Encode(map[string]string{"error": "watchlist not found"})
+		return
+	}
+	h.recordView(r, wl)
 	_ = json.NewEncoder(w).Encode(wl)
 }
 
@@ -67,19 +114,26 @@ func (h *WatchlistHandler) save(w http.ResponseWriter, r *http.Request) {
 		return
 	}
 	wlID := chi.URLParam(r, "id")
-	if err := h.Service.SaveWatchlist(r.Context(), uid, wlID); err != nil {
+	created, err := h.Service.SaveWatchlist(r.Context(), uid, wlID)
+	if err != nil {
 		switch {
 		case errors.Is(err, services.ErrUnauthorized):
 			w.WriteHeader(http.StatusUnauthorized)
+		case errors.Is(err, services.ErrCannotSaveOwn):
+			w.WriteHeader(http.StatusBadRequest)
+		case errors.Is(err, services.ErrBlocked):
+			w.WriteHeader(http.StatusForbidden)
+		case errors.Is(err, gorm.ErrRecordNotFound):
+			w.WriteHeader(http.StatusNotFound)
 		default:
 			w.WriteHeader(http.StatusInternalServerError)
 		}
 		_ = json.NewEncoder(w).Encode(map[string]string{"error": err.Error()})
 		return
 	}
-	// Create notification
+	// Create notification, once per save
 	wl, err := h.Service.GetByID(r.Context(), wlID)
-	if err == nil && wl.OwnerID != uid {
+	if created && err == nil && wl.OwnerID != uid {
 		// Notify the owner
 		actorUUID, _ := uuid.Parse(uid)
 		ownerUUID, _ := uuid.Parse(wl.OwnerID)
@@ -94,6 +148,50 @@ func (h *WatchlistHandler) save(w http.ResponseWriter, r *http.Request) {
 	w.WriteHeader(http.StatusNoContent)
 }
 
+// fork handles POST /v1/watchlists/{id}/fork
+// Copies the list into a new private watchlist owned by the caller.
+func (h *WatchlistHandler) fork(w http.ResponseWriter, r *http.Request) {
+	uid := auth.UserID(r.Context())
+	if uid == "" {
+		w.WriteHeader(http.StatusUnauthorized)
+		return
+	}
+	wl, err := h.Service.ForkWatchlist(r.Context(), uid, chi.URLParam(r, "id"))
+	if err != nil {
+		switch {
+		case errors.Is(err, services.ErrUnauthorized):
+			w.WriteHeader(http.StatusUnauthorized)
+		case errors.Is(err, services.ErrForbidden), errors.Is(err, gorm.ErrRecordNotFound):
+			w.WriteHeader(http.StatusNotFound)
+		default:
+			w.WriteHeader(http.StatusInternalServerError)
+		}
+		_ = json.NewEncoder(w).Encode(map[string]string{"error": err.Error()})
+		return
+	}
+	w.WriteHeader(http.StatusCreated)
+	_ = json.NewEncoder(w).Encode(wl)
+}
+
+func (h *WatchlistHandler) unsave(w http.ResponseWriter, r *http.Request) {
+	uid := auth.UserID(r.Context())
+	if uid == "" {
+		w.WriteHeader(http.StatusUnauthorized)
+		return
+	}
+	if err := h.Service.UnsaveWatchlist(r.Context(), uid, chi.URLParam(r, "id")); err != nil {
+		switch {
+		case errors.Is(err, services.ErrUnauthorized):
+			w.WriteHeader(http.StatusUnauthorized)
+		default:
+			w.WriteHeader(http.StatusInternalServerError)
+		}
+		_ = json.NewEncoder(w).Encode(map[string]string{"error": err.Error()})
+		return
+	}
+	w.WriteHeader(http.StatusNoContent)
+}
+
 // Public: /v1/search/movies
 func (h *WatchlistHandler) SearchMovies(w http.ResponseWriter, r *http.Request) {
 	q := r.URL.Query().Get("q")
@@ -105,8 +203,7 @@ func (h *WatchlistHandler) SearchMovies(w http.ResponseWriter, r *http.Request)
 	page, _ := strconv.Atoi(r.URL.Query().Get("page"))
 	res, err := h.Service.SearchMovies(r.Context(), q, page)
 	if err != nil {
-		w.WriteHeader(http.StatusBadGateway)
-		_ = json.NewEncoder(w).Encode(map[string]string{"error": err.Error()})
+		writeUpstreamError(w, err)
 		return
 	}
 	_ = json.NewEncoder(w).Encode(res)
@@ -130,20 +227,29 @@ func (h *WatchlistHandler) Movie(w http.ResponseWriter, r *http.Request) {
 
 	mv, err := h.Service.GetMovie(r.Context(), int(id))
 	if err != nil {
-		w.WriteHeader(http.StatusBadGateway)
-		_ = json.NewEncoder(w).Encode(map[string]string{"error": err.Error()})
+		writeUpstreamError(w, err)
 		return
 	}
+	// ?region=GB keeps only that region's release dates and providers
+	if region := r.URL.Query().Get("region"); region != "" {
+		mv.Details.ForRegion(strings.ToUpper(region))
+	}
 	_ = json.NewEncoder(w).Encode(mv)
 }
 
-// Public (or semi-public): /v1/trending?window=week|month&limit=20
+// Public (or semi-public): /v1/trending?window=day|week|month&genre=&tag=&limit=20
+// Lists are ranked by time-decayed likes, saves, views and forks.
 func (h *WatchlistHandler) Trending(w http.ResponseWriter, r *http.Request) {
 	type qT struct {
-		Window string `validate:"oneof= week month"`
+		Window string `validate:"oneof=day week month"`
+		Genre  string `validate:"omitempty,max=50"`
+		Tag    string `validate:"omitempty,max=50"`
 		Limit  int    `validate:"gte=1,lte=100"`
 	}
-	q := qT{Window: r.URL.Query().Get("window"), Limit: 20}
+	q := qT{Window: r.URL.Query().Get("window"), Genre: r.URL.Query().Get("genre"), Tag: r.URL.Query().Get("tag"), Limit: 20}
+	if q.Window == "" {
+		q.Window = "week"
+	}
 	if v := r.URL.Query().Get("limit"); v != "" {
 		if n, err := strconv.Atoi(v); err == nil {
 			q.Limit = n
@@ -156,7 +262,7 @@ func (h *WatchlistHandler) Trending(w http.ResponseWriter, r *http.Request) {
 		_ = json.NewEncoder(w).Encode(errs)
 		return
 	}
-	lists, err := h.Service.TrendingWatchlists(r.Context(), q.Window, q.Limit)
+	lists, err := h.Service.TrendingWatchlists(r.Context(), auth.UserID(r.Context()), q.Window, q.Genre, q.Tag, q.Limit)
 	if err != nil {
 		w.WriteHeader(http.StatusInternalServerError)
 		_ = json.NewEncoder(w).Encode(map[string]string{"error": err.Error()})
@@ -180,6 +286,7 @@ func (h *WatchlistHandler) get(w http.ResponseWriter, r *http.Request) {
 		}
 		return
 	}
+	h.recordView(r, wl)
 	_ = json.NewEncoder(w).Encode(wl)
 }
 
@@ -213,6 +320,9 @@ func (h *WatchlistHandler) create(w http.ResponseWriter, r *http.Request) {
 		Title       string `validate:"required,min=1,max=200"`
 		Description string `validate:"max=1000"`
 		IsPublic    bool
+		Tags        []string `validate:"max=50"`
+		// Rules makes this a smart watchlist whose items are computed.
+		Rules *models.SmartRules
 	}
 	var b bodyT
 	if err := json.NewDecoder(r.Body).Decode(&b); err != nil {
@@ -224,10 +334,18 @@ func (h *WatchlistHandler) create(w http.ResponseWriter, r *http.Request) {
 		_ = json.NewEncoder(w).Encode(errs)
 		return
 	}
-	wl := &models.Watchlist{Title: b.Title, Description: b.Description, Visibility: models.PublicVisibility}
+	wl := &models.Watchlist{Title: b.Title, Description: b.Description, Tags: b.Tags, Visibility: models.PublicVisibility, Kind: models.ManualWatchlist}
+	if b.Rules != nil {
+		wl.Kind = models.SmartWatchlist
+		wl.Rules = models.EncodeSmartRules(b.Rules)
+	}
 	if err := h.Service.CreateWatchlist(r.Context(), uid, wl); err != nil {
 		if errors.Is(err, services.ErrUnauthorized) {
 			w.WriteHeader(http.StatusUnauthorized)
+		} else if errors.Is(err, services.ErrForbidden) {
+			w.WriteHeader(http.StatusForbidden)
+		} else if errors.Is(err, tags.ErrTooMany) {
+			w.WriteHeader(http.StatusBadRequest)
 		} else {
 			w.WriteHeader(http.StatusInternalServerError)
 		}
@@ -248,8 +366,12 @@ func (h *WatchlistHandler) update(w http.ResponseWriter, r *http.Request) {
 	type bodyT struct {
 		Title       *string   `validate:"omitempty,min=1,max=200"`
 		Description *string   `validate:"omitempty,max=1000"`
+		Slug        *string   `validate:"omitempty,min=1,max=80"`
 		Visibility  *string   `validate:"omitempty,oneof=public private unlisted"`
 		Tags        *[]string `validate:"omitempty"`
+		Rules       *models.SmartRules
+		// CommentsEnabled turns the discussion on the list on or off.
+		CommentsEnabled *bool `json:"comments_enabled"`
 	}
 	var b bodyT
 	if err := json.NewDecoder(r.Body).Decode(&b); err != nil {
@@ -268,12 +390,21 @@ func (h *WatchlistHandler) update(w http.ResponseWriter, r *http.Request) {
 		if b.Description != nil {
 			existing.Description = *b.Description
 		}
+		if b.Slug != nil {
+			existing.Slug = *b.Slug
+		}
 		if b.Visibility != nil {
 			existing.Visibility = *b.Visibility
 		}
 		if b.Tags != nil {
 			existing.Tags = *b.Tags
 		}
+		if b.Rules != nil {
+			existing.Rules = models.EncodeSmartRules(b.Rules)
+		}
+		if b.CommentsEnabled != nil {
+			existing.CommentsEnabled = *b.CommentsEnabled
+		}
 	})
 	if err != nil {
 		switch {
@@ -281,6 +412,10 @@ func (h *WatchlistHandler) update(w http.ResponseWriter, r *http.Request) {
 			w.WriteHeader(http.StatusUnauthorized)
 		case errors.Is(err, services.ErrForbidden):
 			w.WriteHeader(http.StatusForbidden)
+		case errors.Is(err, services.ErrInvalidSlug), errors.Is(err, services.ErrNotSmartList), errors.Is(err, tags.ErrTooMany):
+			w.WriteHeader(http.StatusBadRequest)
+		case errors.Is(err, services.ErrSlugTaken):
+			w.WriteHeader(http.StatusConflict)
 		case errors.Is(err, gorm.ErrRecordNotFound):
 			w.WriteHeader(http.StatusNotFound)
 		default:
@@ -305,6 +440,8 @@ func (h *WatchlistHandler) delete(w http.ResponseWriter, r *http.Request) {
 		switch {
 		case errors.Is(err, services.ErrUnauthorized):
 			w.WriteHeader(http.StatusUnauthorized)
+		case errors.Is(err, services.ErrForbidden):
+			w.WriteHeader(http.StatusForbidden)
 		case errors.Is(err, gorm.ErrRecordNotFound):
 			w.WriteHeader(http.StatusNotFound)
 		default:
@@ -326,8 +463,9 @@ func (h *WatchlistHandler) addItem(w http.ResponseWriter, r *http.Request) {
 	}
 	wlID := chi.URLParam(r, "id")
 	type bodyT struct {
-		TMDBID int64  `json:"tmdb_id" validate:"required,gt=0"`
-		Notes  string `json:"notes" validate:"max=1000"`
+		TMDBID    int64  `json:"tmdb_id" validate:"required,gt=0"`
+		MediaType string `json:"media_type" validate:"omitempty,oneof=movie tv"`
+		Notes     string `json:"notes" validate:"max=1000"`
 	}
 	var b bodyT
 	if err := json.NewDecoder(r.Body).Decode(&b); err != nil {
@@ -339,15 +477,20 @@ func (h *WatchlistHandler) addItem(w http.ResponseWriter, r *http.Request) {
 		_ = json.NewEncoder(w).Encode(errs)
 		return
 	}
-	item, err := h.Service.AddItem(r.Context(), uid, wlID, int(b.TMDBID), b.Notes)
+	item, err := h.Service.AddItem(r.Context(), uid, wlID, b.MediaType, int(b.TMDBID), b.Notes)
 	if err != nil {
 		switch {
 		case errors.Is(err, services.ErrUnauthorized):
 			w.WriteHeader(http.StatusUnauthorized)
 		case errors.Is(err, services.ErrForbidden):
 			w.WriteHeader(http.StatusForbidden)
-		case errors.Is(err, gorm.ErrRecordNotFound):
+		case errors.Is(err, services.ErrSmartListReadOnly):
+			w.WriteHeader(http.StatusConflict)
+		case errors.Is(err, gorm.ErrRecordNotFound), errors.Is(err, tmdb.ErrNotFound):
 			w.WriteHeader(http.StatusNotFound)
+		case errors.Is(err, tmdb.ErrRateLimited), errors.Is(err, tmdb.ErrUnavailable):
+			writeUpstreamError(w, err)
+			return
 		default:
 			w.WriteHeader(http.StatusInternalServerError)
 		}
@@ -370,6 +513,8 @@ func (h *WatchlistHandler) removeItem(w http.ResponseWriter, r *http.Request) {
 		switch {
 		case errors.Is(err, services.ErrUnauthorized):
 			w.WriteHeader(http.StatusUnauthorized)
+		case errors.Is(err, services.ErrSmartListReadOnly):
+			w.WriteHeader(http.StatusConflict)
 		case errors.Is(err, gorm.ErrRecordNotFound):
 			w.WriteHeader(http.StatusNotFound)
 		default:
@@ -383,6 +528,111 @@ func (h *WatchlistHandler) removeItem(w http.ResponseWriter, r *http.Request) {
 	w.WriteHeader(http.StatusNoContent)
 }
 
+// batchItems handles POST /v1/watchlists/{id}/items/batch
+// {"add":[{"tmdb_id":1,"media_type":"movie","notes":""}], "remove":["<itemId>"], "move":[{"item_id":"<itemId>","position":0}]}
+// Applies everything in one transaction and reports an outcome per entry.
+func (h *WatchlistHandler) batchItems(w http.ResponseWriter, r *http.Request) {
+	uid := auth.UserID(r.Context())
+	if uid == "" {
+		w.WriteHeader(http.StatusUnauthorized)
+		return
+	}
+	var b services.BatchItemsInput
+	if err := json.NewDecoder(r.Body).Decode(&b); err != nil {
+		w.WriteHeader(http.StatusBadRequest)
+		return
+	}
+	if errs := validate.Map(b); errs != nil {
+		w.WriteHeader(http.StatusBadRequest)
+		_ = json.NewEncoder(w).Encode(errs)
+		return
+	}
+	res, err := h.Service.BatchItems(r.Context(), uid, chi.URLParam(r, "id"), b)
+	if err != nil {
+		switch {
+		case errors.Is(err, services.ErrUnauthorized):
+			w.WriteHeader(http.StatusUnauthorized)
+		case errors.Is(err, services.ErrSmartListReadOnly):
+			w.WriteHeader(http.StatusConflict)
+		case errors.Is(err, gorm.ErrRecordNotFound):
+			w.WriteHeader(http.StatusNotFound)
+		default:
+			w.WriteHeader(http.StatusInternalServerError)
+		}
+		_ = json.NewEncoder(w).Encode(map[string]string{"error": err.Error()})
+		return
+	}
+	_ = json.NewEncoder(w).Encode(res)
+}
+
+// updateItem handles PATCH /v1/watchlists/{id}/items/{itemId}
+// Edits an item's note and/or moves it to a new position.
+func (h *WatchlistHandler) updateItem(w http.ResponseWriter, r *http.Request) {
+	uid := auth.UserID(r.Context())
+	if uid == "" {
+		w.WriteHeader(http.StatusUnauthorized)
+		return
+	}
+	type bodyT struct {
+		Note     *string `json:"note" validate:"omitempty,max=1000"`
+		Position *int    `json:"position" validate:"omitempty,gte=0"`
+	}
+	var b bodyT
+	if err := json.NewDecoder(r.Body).Decode(&b); err != nil {
+		w.WriteHeader(http.StatusBadRequest)
+		return
+	}
+	if errs := validate.Map(b); errs != nil {
+		w.WriteHeader(http.StatusBadRequest)
+		_ = json.NewEncoder(w).Encode(errs)
+		return
+	}
+	item, err := h.Service.UpdateItem(r.Context(), uid, chi.URLParam(r, "id"), chi.URLParam(r, "itemId"), b.Note, b.Position)
+	if err != nil {
+		switch {
+		case errors.Is(err, services.ErrUnauthorized):
+			w.WriteHeader(http.StatusUnauthorized)
+		case errors.Is(err, services.ErrSmartListReadOnly):
+			w.WriteHeader(http.StatusConflict)
+		case errors.Is(err, gorm.ErrRecordNotFound):
+			w.WriteHeader(http.StatusNotFound)
+		default:
+			w.WriteHeader(http.StatusInternalServerError)
+		}
+		_ = json.NewEncoder(w).Encode(map[string]string{"error": err.Error()})
+		return
+	}
+	_ = json.NewEncoder(w).Encode(item)
+}
+
+// refresh handles POST /v1/watchlists/{id}/refresh
+// Re-evaluates a smart watchlist's rules right away instead of waiting for it to go stale.
+func (h *WatchlistHandler) refresh(w http.ResponseWriter, r *http.Request) {
+	uid := auth.UserID(r.Context())
+	if uid == "" {
+		w.WriteHeader(http.StatusUnauthorized)
+		return
+	}
+	wl, err := h.Service.RefreshSmartList(r.Context(), uid, chi.URLParam(r, "id"))
+	if err != nil {
+		switch {
+		case errors.Is(err, services.ErrUnauthorized):
+			w.WriteHeader(http.StatusUnauthorized)
+		case errors.Is(err, services.ErrForbidden):
+			w.WriteHeader(http.StatusForbidden)
+		case errors.Is(err, services.ErrNotSmartList):
+			w.WriteHeader(http.StatusBadRequest)
+		case errors.Is(err, gorm.ErrRecordNotFound):
+			w.WriteHeader(http.StatusNotFound)
+		default:
+			w.WriteHeader(http.StatusInternalServerError)
+		}
+		_ = json.NewEncoder(w).Encode(map[string]string{"error": err.Error()})
+		return
+	}
+	_ = json.NewEncoder(w).Encode(wl)
+}
+
 func (h *WatchlistHandler) like(w http.ResponseWriter, r *http.Request) {
 	uid := auth.UserID(r.Context())
 	if uid == "" {
@@ -390,19 +640,24 @@ func (h *WatchlistHandler) like(w http.ResponseWriter, r *http.Request) {
 		return
 	}
 	wlID := chi.URLParam(r, "id")
-	if err := h.Service.Like(r.Context(), uid, wlID); err != nil {
+	created, err := h.Service.Like(r.Context(), uid, wlID)
+	if err != nil {
 		switch {
 		case errors.Is(err, services.ErrUnauthorized):
 			w.WriteHeader(http.StatusUnauthorized)
+		case errors.Is(err, services.ErrBlocked):
+			w.WriteHeader(http.StatusForbidden)
+		case errors.Is(err, gorm.ErrRecordNotFound):
+			w.WriteHeader(http.StatusNotFound)
 		default:
 			w.WriteHeader(http.StatusInternalServerError)
 		}
 		_ = json.NewEncoder(w).Encode(map[string]string{"error": err.Error()})
 		return
 	}
-	// Create notification
+	// Create notification, once per like
 	wl, err := h.Service.GetByID(r.Context(), wlID)
-	if err == nil && wl.OwnerID != uid {
+	if created && err == nil && wl.OwnerID != uid {
 		// Notify the owner
 		actorUUID, _ := uuid.Parse(uid)
 		ownerUUID, _ := uuid.Parse(wl.OwnerID)
@@ -479,8 +734,7 @@ func (h *WatchlistHandler) Feed(w http.ResponseWriter, r *http.Request) {
 	}
 	body, _, err := h.Service.FetchFeed(r.Context(), opts)
 	if err != nil {
-		w.WriteHeader(http.StatusBadGateway)
-		_ = json.NewEncoder(w).Encode(map[string]string{"error": err.Error()})
+		writeUpstreamError(w, err)
 		return
 	}
 	w.Header().Set("Cache-Control", "public, max-age=60")
@@ -0,0 +1,39 @@
+package httpserver
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"testing"
+)
+
+func TestRealIPTrustsOnlyConfiguredProxies(t *testing.T) {
+	trusted, err := ParseTrustedProxies([]string{"10.0.0.0/8", "192.168.1.1"})
+	if err != nil {
+		t.Fatal(err)
+	}
+	var got string
+	h := RealIP(trusted)(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) { got = r.RemoteAddr }))
+
+	for _, c := range []struct {
+		name, remote, xff, realIP, want string
+	}{
+		{"direct client spoofing", "203.0.113.7:5000", "1.2.3.4", "1.2.3.4", "203.0.113.7:5000"},
+		{"through proxy", "10.0.0.2:443", "198.51.100.9", "", "198.51.100.9"},
+		{"client-supplied hops ignored", "10.0.0.2:443", "1.2.3.4, 198.51.100.9, 10.0.0.3", "", "198.51.100.9"},
+		{"single trusted IP", "192.168.1.1:80", "", "198.51.100.10", "198.51.100.10"},
+		{"proxy without headers", "10.0.0.2:443", "", "", "10.0.0.2:443"},
+	} {
+		r := httptest.NewRequest(http.MethodGet, "/", nil)
+		r.RemoteAddr = c.remote
+		if c.xff != "" {
+			r.Header.Set("X-Forwarded-For", c.xff)
+		}
+		if c.realIP != "" {
+			r.Header.Set("X-Real-IP", c.realIP)
+		}
+		h.ServeHTTP(httptest.NewRecorder(), r)
+		if got != c.want {
+			t.Errorf("%s: RemoteAddr = %q, want %q", c.name, got, c.want)
+		}
+	}
+}
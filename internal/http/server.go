@@ -4,6 +4,7 @@ import (
 	"encoding/json"
 	"log"
 	"net/http"
+	"net/netip"
 	"sort"
 	time "time"
 
@@ -17,11 +18,12 @@ type Server struct {
 }
 
 // NewServer builds the base router and allows callers to mount versioned routes.
-func NewServer(mounters ...func(r chi.Router)) *Server {
+// Forwarding headers are only honored from trustedProxies.
+func NewServer(trustedProxies []netip.Prefix, mounters ...func(r chi.Router)) *Server {
 	r := chi.NewRouter()
 	// Core middlewares
 	r.Use(middleware.RequestID)
-	r.Use(middleware.RealIP)
+	r.Use(RealIP(trustedProxies))
 	r.Use(middleware.Logger)
 	r.Use(middleware.Recoverer)
 	r.Use(middleware.Timeout(60 * time.Second))
@@ -9,7 +9,7 @@ import (
 
 // Basic test to ensure NewServer mounts mounters and registers routes (with /v1 prefix)
 func TestNewServer_RegistersRoutes(t *testing.T) {
-	s := NewServer(func(r chi.Router) {
+	s := NewServer(nil, func(r chi.Router) {
 		r.Get("/foo", func(w http.ResponseWriter, r *http.Request) {})
 		r.Post("/bar", func(w http.ResponseWriter, r *http.Request) {})
 	})
@@ -0,0 +1,67 @@
+package imaging
+
+import (
+	"encoding/binary"
+	"image"
+	"image/color"
+	"testing"
+)
+
+// exifJPEG builds a minimal JPEG header carrying only an orientation tag.
+func exifJPEG(order binary.ByteOrder, orientation uint16) []byte {
+	tiff := make([]byte, 8+2+12+4)
+	if order == binary.LittleEndian {
+		copy(tiff, "II")
+	} else {
+		copy(tiff, "MM")
+	}
+	order.PutUint16(tiff[2:], 42)
+	order.PutUint32(tiff[4:], 8)
+	order.PutUint16(tiff[8:], 1)
+	order.PutUint16(tiff[10:], 0x0112)
+	order.PutUint16(tiff[12:], 3) // SHORT
+	order.PutUint32(tiff[14:], 1)
+	order.PutUint16(tiff[18:], orientation)
+
+	seg := append([]byte("Exif\x00\x00"), tiff...)
+	out := []byte{0xFF, 0xD8, 0xFF, 0xE1, 0, 0}
+	binary.BigEndian.PutUint16(out[4:], uint16(len(seg)+2))
+	out = append(out, seg...)
+	return append(out, 0xFF, 0xD9)
+}
+
+func TestJPEGOrientation(t *testing.T) {
+	if got := JPEGOrientation(exifJPEG(binary.BigEndian, 6)); got != 6 {
+		t.Errorf("big endian: got %d, want 6", got)
+	}
+	if got := JPEGOrientation(exifJPEG(binary.LittleEndian, 8)); got != 8 {
+		t.Errorf("little endian: got %d, want 8", got)
+	}
+	if got := JPEGOrientation([]byte{0xFF, 0xD8, 0xFF, 0xD9}); got != 1 {
+		t.Errorf("no exif: got %d, want 1", got)
+	}
+	if got := JPEGOrientation([]byte("not a jpeg")); got != 1 {
+		t.Errorf("garbage: got %d, want 1", got)
+	}
+}
+
+func TestOrientRotatesClockwise(t *testing.T) {
+	red, blue := color.RGBA{255, 0, 0, 255}, color.RGBA{0, 0, 255, 255}
+	src := image.NewRGBA(image.Rect(0, 0, 2, 1))
+	src.SetRGBA(0, 0, red)
+	src.SetRGBA(1, 0, blue)
+
+	got := Orient(src, 6)
+	if b := got.Bounds(); b.Dx() != 1 || b.Dy() != 2 {
+		t.Fatalf("bounds = %v, want 1x2", b)
+	}
+	if c := color.RGBAModel.Convert(got.At(0, 0)); c != red {
+		t.Errorf("top = %v, want red", c)
+	}
+	if c := color.RGBAModel.Convert(got.At(0, 1)); c != blue {
+		t.Errorf("bottom = %v, want blue", c)
+	}
+	if Orient(src, 1) != src {
+		t.Error("orientation 1 should return the source")
+	}
+}
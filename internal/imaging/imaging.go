@@ -0,0 +1,117 @@
+// Package imaging has the small amount of image processing the API needs:
+// resizing with center crop and poster collages.
+package imaging
+
+import (
+	"image"
+	"image/color"
+	"image/draw"
+	"image/jpeg"
+	"io"
+
+	// decoders for image.Decode
+	_ "image/gif"
+	_ "image/png"
+)
+
+// Fill scales src to cover a w×h box, preserving aspect ratio, and crops the
+// overflow around the center. Sampling is bilinear.
+func Fill(src image.Image, w, h int) *image.RGBA {
+	dst := image.NewRGBA(image.Rect(0, 0, w, h))
+	b := src.Bounds()
+	sw, sh := b.Dx(), b.Dy()
+	if sw == 0 || sh == 0 || w == 0 || h == 0 {
+		return dst
+	}
+	scale := max(float64(w)/float64(sw), float64(h)/float64(sh))
+	// offset of the crop window inside the scaled image
+	ox := (float64(sw)*scale - float64(w)) / 2
+	oy := (float64(sh)*scale - float64(h)) / 2
+	for y := 0; y < h; y++ {
+		sy := (float64(y)+oy+0.5)/scale - 0.5
+		for x := 0; x < w; x++ {
+			sx := (float64(x)+ox+0.5)/scale - 0.5
+			dst.SetRGBA(x, y, bilinear(src, b, sx, sy))
+		}
+	}
+	return dst
+}
+
+// Fit scales src down so it fits inside maxW×maxH, preserving aspect ratio.
+// Images that already fit are returned unscaled.
+func Fit(src image.Image, maxW, maxH int) image.Image {
+	b := src.Bounds()
+	if b.Dx() <= maxW && b.Dy() <= maxH {
+		return src
+	}
+	scale := min(float64(maxW)/float64(b.Dx()), float64(maxH)/float64(b.Dy()))
+	w := max(1, int(float64(b.Dx())*scale+0.5))
+	h := max(1, int(float64(b.Dy())*scale+0.5))
+	return Fill(src, w, h)
+}
+
+func bilinear(src image.Image, b image.Rectangle, x, y float64) color.RGBA {
+	x = clamp(x, 0, float64(b.Dx()-1))
+	y = clamp(y, 0, float64(b.Dy()-1))
+	x0, y0 := int(x), int(y)
+	x1, y1 := min(x0+1, b.Dx()-1), min(y0+1, b.Dy()-1)
+	fx, fy := x-float64(x0), y-float64(y0)
+
+	c00 := rgba(src.At(b.Min.X+x0, b.Min.Y+y0))
+	c10 := rgba(src.At(b.Min.X+x1, b.Min.Y+y0))
+	c01 := rgba(src.At(b.Min.X+x0, b.Min.Y+y1))
+	c11 := rgba(src.At(b.Min.X+x1, b.Min.Y+y1))
+
+	var out [4]uint8
+	for i := range out {
+		top := c00[i]*(1-fx) + c10[i]*fx
+		bottom := c01[i]*(1-fx) + c11[i]*fx
+		out[i] = uint8(top*(1-fy) + bottom*fy + 0.5)
+	}
+	return color.RGBA{out[0], out[1], out[2], out[3]}
+}
+
+func rgba(c color.Color) [4]float64 {
+	r, g, b, a := c.RGBA()
+	return [4]float64{float64(r >> 8), float64(g >> 8), float64(b >> 8), float64(a >> 8)}
+}
+
+func clamp(v, lo, hi float64) float64 {
+	return max(lo, min(v, hi))
+}
+
+// Grid picks the collage layout for n posters: 3×3 from nine up, 2×2 from
+// four up, otherwise a single poster.
+func Grid(n int) int {
+	switch {
+	case n >= 9:
+		return 3
+	case n >= 4:
+		return 2
+	default:
+		return 1
+	}
+}
+
+// Collage tiles the first Grid(len(posters))² posters into a w×h image, row
+// by row. It returns nil when there are no posters.
+func Collage(posters []image.Image, w, h int) *image.RGBA {
+	if len(posters) == 0 {
+		return nil
+	}
+	n := Grid(len(posters))
+	dst := image.NewRGBA(image.Rect(0, 0, w, h))
+	draw.Draw(dst, dst.Bounds(), image.Black, image.Point{}, draw.Src)
+	for i := 0; i < n*n; i++ {
+		col, row := i%n, i/n
+		cell := image.Rect(col*w/n, row*h/n, (col+1)*w/n, (row+1)*h/n)
+		tile := Fill(posters[i], cell.Dx(), cell.Dy())
+		draw.Draw(dst, cell, tile, image.Point{}, draw.Src)
+	}
+	return dst
+}
+
+// EncodeJPEG writes img as a JPEG at the quality used for generated images.
+func EncodeJPEG(w io.Writer, img image.Image) error {
+	return jpeg.Encode(w, img, &jpeg.Options{Quality: 85})
+}
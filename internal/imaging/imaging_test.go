@@ -0,0 +1,66 @@
+package imaging
+
+import (
+	"image"
+	"image/color"
+	"image/draw"
+	"testing"
+)
+
+func solid(w, h int, c color.Color) image.Image {
+	img := image.NewRGBA(image.Rect(0, 0, w, h))
+	draw.Draw(img, img.Bounds(), &image.Uniform{c}, image.Point{}, draw.Src)
+	return img
+}
+
+func TestFillCropsToSize(t *testing.T) {
+	// left half red, right half blue; filling a square keeps the center
+	src := image.NewRGBA(image.Rect(0, 0, 400, 100))
+	draw.Draw(src, image.Rect(0, 0, 200, 100), &image.Uniform{color.RGBA{255, 0, 0, 255}}, image.Point{}, draw.Src)
+	draw.Draw(src, image.Rect(200, 0, 400, 100), &image.Uniform{color.RGBA{0, 0, 255, 255}}, image.Point{}, draw.Src)
+
+	got := Fill(src, 50, 50)
+	if got.Bounds().Dx() != 50 || got.Bounds().Dy() != 50 {
+		t.Fatalf("Fill bounds = %v, want 50x50", got.Bounds())
+	}
+	if c := got.RGBAAt(5, 25); c.R != 255 || c.B != 0 {
+		t.Errorf("left pixel = %v, want red", c)
+	}
+	if c := got.RGBAAt(45, 25); c.B != 255 || c.R != 0 {
+		t.Errorf("right pixel = %v, want blue", c)
+	}
+}
+
+func TestFitOnlyShrinks(t *testing.T) {
+	small := solid(100, 50, color.White)
+	if Fit(small, 200, 200) != small {
+		t.Error("Fit should return images that already fit unchanged")
+	}
+	got := Fit(solid(1000, 500, color.White), 200, 200).Bounds()
+	if got.Dx() != 200 || got.Dy() != 100 {
+		t.Errorf("Fit bounds = %v, want 200x100", got)
+	}
+}
+
+func TestCollageGrid(t *testing.T) {
+	colors := []color.RGBA{
+		{255, 0, 0, 255}, {0, 255, 0, 255}, {0, 0, 255, 255}, {255, 255, 0, 255}, {0, 255, 255, 255},
+	}
+	var posters []image.Image
+	for _, c := range colors {
+		posters = append(posters, solid(20, 30, c))
+	}
+	img := Collage(posters, 200, 300)
+	// five posters -> 2x2, fifth is dropped
+	cells := map[image.Point]color.RGBA{
+		{50, 75}: colors[0], {150, 75}: colors[1], {50, 225}: colors[2], {150, 225}: colors[3],
+	}
+	for p, want := range cells {
+		if got := img.RGBAAt(p.X, p.Y); got != want {
+			t.Errorf("pixel %v = %v, want %v", p, got, want)
+		}
+	}
+	if Collage(nil, 10, 10) != nil {
+		t.Error("Collage(nil) should be nil")
+	}
+}
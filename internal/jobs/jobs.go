@@ -0,0 +1,38 @@
+// Package jobs runs periodic background work inside the API process.
+package jobs
+
+import (
+	"context"
+	"log"
+	"time"
+)
+
+// Every runs fn immediately and then once per interval until ctx is done.
+// Errors are logged under name; a failing run doesn't stop later ones.
+func Every(ctx context.Context, interval time.Duration, name string, fn func(ctx context.Context) error) {
+	go func() {
+		ticker := time.NewTicker(interval)
+		defer ticker.Stop()
+		for {
+			start := time.Now()
+			if err := fn(ctx); err != nil && ctx.Err() == nil {
+				log.Printf("job %s failed after %s: %v", name, time.Since(start).Round(time.Millisecond), err)
+			}
+			select {
+			case <-ctx.Done():
+				return
+			case <-ticker.C:
+			}
+		}
+	}()
+}
+
+// Once runs fn in the background a single time, logging a failure under name.
+func Once(ctx context.Context, name string, fn func(ctx context.Context) error) {
+	go func() {
+		start := time.Now()
+		if err := fn(ctx); err != nil && ctx.Err() == nil {
+			log.Printf("job %s failed after %s: %v", name, time.Since(start).Round(time.Millisecond), err)
+		}
+	}()
+}
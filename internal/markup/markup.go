@@ -0,0 +1,256 @@
+// Package markup renders the small markdown subset allowed in user text:
+// **bold**, *italic* or _italic_, `code`, [links](https://...) and
+// ||spoiler|| spans. All output is HTML-escaped; only http(s) links survive.
+package markup
+
+import (
+	"html"
+	"regexp"
+	"strings"
+	"unicode/utf8"
+)
+
+// maxDepth bounds nesting so hostile input can't recurse forever; deeper
+// markers are left as text.
+const maxDepth = 8
+
+type mode int
+
+const (
+	modeHTML mode = iota
+	modePlain
+	modeRedacted
+)
+
+// SpoilerPlaceholder replaces hidden spoiler spans in previews.
+const SpoilerPlaceholder = "[spoiler]"
+
+var paragraphBreak = regexp.MustCompile(`\n\s*\n`)
+
+// HTML renders src as safe HTML: paragraphs for blank-line separated blocks,
+// <br> for single newlines, spoilers as <span class="spoiler">.
+func HTML(src string) string {
+	var b strings.Builder
+	for _, para := range paragraphs(src) {
+		b.WriteString("<p>")
+		for i, line := range strings.Split(para, "\n") {
+			if i > 0 {
+				b.WriteString("<br>")
+			}
+			inline(&b, line, modeHTML, 0)
+		}
+		b.WriteString("</p>")
+	}
+	return b.String()
+}
+
+// PlainText strips the markup. With redact set, spoiler spans are replaced
+// by SpoilerPlaceholder.
+func PlainText(src string, redact bool) string {
+	m := modePlain
+	if redact {
+		m = modeRedacted
+	}
+	paras := paragraphs(src)
+	var b strings.Builder
+	for i, para := range paras {
+		if i > 0 {
+			b.WriteString("\n\n")
+		}
+		for j, line := range strings.Split(para, "\n") {
+			if j > 0 {
+				b.WriteByte('\n')
+			}
+			inline(&b, line, m, 0)
+		}
+	}
+	return b.String()
+}
+
+// Length is the number of characters a reader sees, markup excluded.
+func Length(src string) int {
+	return utf8.RuneCountInString(PlainText(src, false))
+}
+
+// HasSpoilers reports whether src contains at least one spoiler span.
+func HasSpoilers(src string) bool {
+	return PlainText(src, true) != PlainText(src, false)
+}
+
+// Preview is the redacted plain text cut to at most n characters.
+func Preview(src string, n int) string {
+	return Excerpt(PlainText(src, true), n)
+}
+
+// Excerpt collapses whitespace in plain text and cuts it to at most n
+// characters, for callers that already have PlainText's output.
+func Excerpt(text string, n int) string {
+	text = strings.Join(strings.Fields(text), " ")
+	if utf8.RuneCountInString(text) <= n {
+		return text
+	}
+	r := []rune(text)
+	return strings.TrimSpace(string(r[:n-1])) + "…"
+}
+
+func paragraphs(src string) []string {
+	src = strings.ReplaceAll(src, "\r\n", "\n")
+	var out []string
+	for _, p := range paragraphBreak.Split(src, -1) {
+		if p = strings.TrimSpace(p); p != "" {
+			out = append(out, p)
+		}
+	}
+	return out
+}
+
+func inline(b *strings.Builder, s string, m mode, depth int) {
+	text := func(t string) {
+		if m == modeHTML {
+			b.WriteString(html.EscapeString(t))
+		} else {
+			b.WriteString(t)
+		}
+	}
+	start := 0 // start of the pending literal run
+	flush := func(i int) {
+		text(s[start:i])
+	}
+	for i := 0; i < len(s); {
+		c := s[i]
+		if c == '\\' && i+1 < len(s) && strings.IndexByte("\\`*_|[]", s[i+1]) >= 0 {
+			flush(i)
+			text(s[i+1 : i+2])
+			i += 2
+			start = i
+			continue
+		}
+		if depth >= maxDepth {
+			i++
+			continue
+		}
+		switch {
+		case c == '`':
+			if j := strings.IndexByte(s[i+1:], '`'); j > 0 {
+				flush(i)
+				code := s[i+1 : i+1+j]
+				if m == modeHTML {
+					b.WriteString("<code>" + html.EscapeString(code) + "</code>")
+				} else {
+					b.WriteString(code)
+				}
+				i += j + 2
+				start = i
+				continue
+			}
+		case strings.HasPrefix(s[i:], "||"):
+			if j := strings.Index(s[i+2:], "||"); j > 0 {
+				flush(i)
+				inner := s[i+2 : i+2+j]
+				switch m {
+				case modeHTML:
+					b.WriteString(`<span class="spoiler">`)
+					inline(b, inner, m, depth+1)
+					b.WriteString("</span>")
+				case modePlain:
+					inline(b, inner, m, depth+1)
+				case modeRedacted:
+					b.WriteString(SpoilerPlaceholder)
+				}
+				i += j + 4
+				start = i
+				continue
+			}
+		case strings.HasPrefix(s[i:], "**"):
+			if j := strings.Index(s[i+2:], "**"); j > 0 {
+				flush(i)
+				wrap(b, "strong", s[i+2:i+2+j], m, depth)
+				i += j + 4
+				start = i
+				continue
+			}
+		case c == '*' || c == '_':
+			if j := emphasisEnd(s, i); j > 0 {
+				flush(i)
+				wrap(b, "em", s[i+1:j], m, depth)
+				i = j + 1
+				start = i
+				continue
+			}
+		case c == '[':
+			if label, url, n, ok := link(s[i:]); ok {
+				flush(i)
+				if m == modeHTML {
+					b.WriteString(`<a href="` + html.EscapeString(url) + `" rel="nofollow noopener ugc">`)
+					inline(b, label, m, depth+1)
+					b.WriteString("</a>")
+				} else {
+					inline(b, label, m, depth+1)
+				}
+				i += n
+				start = i
+				continue
+			}
+		}
+		i++
+	}
+	flush(len(s))
+}
+
+func wrap(b *strings.Builder, tag, inner string, m mode, depth int) {
+	if m == modeHTML {
+		b.WriteString("<" + tag + ">")
+	}
+	inline(b, inner, m, depth+1)
+	if m == modeHTML {
+		b.WriteString("</" + tag + ">")
+	}
+}
+
+// emphasisEnd finds the closing delimiter of *em* or _em_ opened at i, or
+// returns -1. Underscores inside words (snake_case) don't count.
+func emphasisEnd(s string, i int) int {
+	c := s[i]
+	if i+1 >= len(s) || s[i+1] == ' ' || s[i+1] == c {
+		return -1
+	}
+	if c == '_' && i > 0 && isWordByte(s[i-1]) {
+		return -1
+	}
+	for j := i + 2; j < len(s); j++ {
+		if s[j] != c || s[j-1] == ' ' {
+			continue
+		}
+		if c == '_' && j+1 < len(s) && isWordByte(s[j+1]) {
+			continue
+		}
+		return j
+	}
+	return -1
+}
+
+func isWordByte(c byte) bool {
+	return c >= 'a' && c <= 'z' || c >= 'A' && c <= 'Z' || c >= '0' && c <= '9' || c >= 0x80
+}
+
+// link parses [label](url) at the start of s. Only http and https URLs are
+// accepted; anything else stays literal text.
+func link(s string) (label, url string, n int, ok bool) {
+	mid := strings.Index(s, "](")
+	if mid <= 1 {
+		return "", "", 0, false
+	}
+	end := strings.IndexByte(s[mid+2:], ')')
+	if end <= 0 {
+		return "", "", 0, false
+	}
+	label, url = s[1:mid], s[mid+2:mid+2+end]
+	if strings.ContainsAny(label, "[]") || strings.ContainsAny(url, " \t") {
+		return "", "", 0, false
+	}
+	lower := strings.ToLower(url)
+	if !strings.HasPrefix(lower, "http://") && !strings.HasPrefix(lower, "https://") {
+		return "", "", 0, false
+	}
+	return label, url, mid + 2 + end + 1, true
+}
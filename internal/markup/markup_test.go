@@ -0,0 +1,45 @@
+package markup
+
+import "testing"
+
+func TestHTML(t *testing.T) {
+	tests := []struct {
+		in, want string
+	}{
+		{"plain", "<p>plain</p>"},
+		{"<script>alert(1)</script>", "<p>&lt;script&gt;alert(1)&lt;/script&gt;</p>"},
+		{"**bold** and *it* and _it_", "<p><strong>bold</strong> and <em>it</em> and <em>it</em></p>"},
+		{"snake_case_name stays", "<p>snake_case_name stays</p>"},
+		{"the end: ||he dies||", `<p>the end: <span class="spoiler">he dies</span></p>`},
+		{"`**not bold**`", "<p><code>**not bold**</code></p>"},
+		{"[site](https://example.com/?a=1&b=\"x\")", `<p><a href="https://example.com/?a=1&amp;b=&#34;x&#34;" rel="nofollow noopener ugc">site</a></p>`},
+		{"[bad](javascript:alert(1))", "<p>[bad](javascript:alert(1))</p>"},
+		{"one\ntwo\n\nthree", "<p>one<br>two</p><p>three</p>"},
+		{`\*literal\*`, "<p>*literal*</p>"},
+		{"unclosed **bold", "<p>unclosed **bold</p>"},
+	}
+	for _, tt := range tests {
+		if got := HTML(tt.in); got != tt.want {
+			t.Errorf("HTML(%q)\n got %q\nwant %q", tt.in, got, tt.want)
+		}
+	}
+}
+
+func TestPlainTextAndSpoilers(t *testing.T) {
+	src := "Great **film**. ||Bruce Willis was dead|| all along."
+	if got, want := PlainText(src, false), "Great film. Bruce Willis was dead all along."; got != want {
+		t.Errorf("PlainText = %q, want %q", got, want)
+	}
+	if got, want := PlainText(src, true), "Great film. [spoiler] all along."; got != want {
+		t.Errorf("redacted = %q, want %q", got, want)
+	}
+	if !HasSpoilers(src) || HasSpoilers("no **spoilers** here") {
+		t.Error("HasSpoilers misreported")
+	}
+	if got := Length("**abc**"); got != 3 {
+		t.Errorf("Length = %d, want 3", got)
+	}
+	if got, want := Preview("a ||b|| c d e f", 12), "a [spoiler]…"; got != want {
+		t.Errorf("Preview = %q, want %q", got, want)
+	}
+}
@@ -0,0 +1,22 @@
+package models
+
+import (
+	"time"
+
+	"github.com/google/uuid"
+)
+
+// WatchlistComment is a comment on a watchlist. Replies set ParentID to a
+// top-level comment; threads are only one level deep.
+type WatchlistComment struct {
+	ID          uuid.UUID  `gorm:"type:uuid;default:gen_random_uuid();primaryKey" json:"id"`
+	WatchlistID uuid.UUID  `gorm:"type:uuid;not null;index" json:"watchlist_id"`
+	AuthorID    uuid.UUID  `gorm:"type:uuid;not null" json:"author_id"`
+	ParentID    *uuid.UUID `gorm:"type:uuid;index" json:"parent_id,omitempty"`
+	Body        string     `gorm:"type:text;not null" json:"body"`
+	EditedAt    *time.Time `json:"edited_at,omitempty"`
+	CreatedAt   time.Time  `gorm:"not null;default:now()" json:"created_at"`
+	UpdatedAt   time.Time  `gorm:"not null;default:now()" json:"updated_at"`
+}
+
+func (WatchlistComment) TableName() string { return "watchlist_comments" }
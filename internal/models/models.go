@@ -21,18 +21,45 @@ type Share struct {
 	ToUserID    uuid.UUID `gorm:"type:uuid;index" json:"to_user_id"`
 	WatchlistID uuid.UUID `gorm:"type:uuid;index" json:"watchlist_id"`
 	Message     string    `json:"message"`
+
+	From      User       `gorm:"foreignKey:FromUserID" json:"-"`
+	Watchlist *Watchlist `gorm:"foreignKey:WatchlistID" json:"-"`
 }
 
 type Notification struct {
 	ID        uuid.UUID `gorm:"type:uuid;default:gen_random_uuid();primaryKey"`
 	UserID    uuid.UUID `gorm:"type:uuid;not null;index"` // recipient
-	Type      string    `gorm:"type:text;not null;check:type IN ('like','follow')"`
+	Type      string    `gorm:"type:text;not null;check:type IN ('like','follow','save','comment','reply','mention','review_like','review_reply','follow_request','follow_accept','share','person_credit')"`
 	ActorID   uuid.UUID `gorm:"type:uuid;not null"`
 	EntityID  uuid.UUID `gorm:"type:uuid;not null"`
 	IsRead    bool      `gorm:"not null;default:false"`
 	CreatedAt time.Time `gorm:"not null;default:now()"`
 }
 
+// Notification types. For comment, reply and mention the entity is the
+// watchlist the comment is on; for review_like and review_reply it is the
+// review; for follow_request and follow_accept it is the other user; for
+// share it is the watchlist shared. person_credit has the followed Person
+// as actor and the movie they're newly credited on as entity.
+const (
+	NotificationLike    = "like"
+	NotificationFollow  = "follow"
+	NotificationSave    = "save"
+	NotificationComment = "comment"
+	NotificationReply   = "reply"
+	NotificationMention = "mention"
+
+	NotificationReviewLike  = "review_like"
+	NotificationReviewReply = "review_reply"
+
+	NotificationFollowRequest = "follow_request"
+	NotificationFollowAccept  = "follow_accept"
+
+	NotificationShare = "share"
+
+	NotificationPersonCredit = "person_credit"
+)
+
 type Activity struct {
 	ID        uuid.UUID `gorm:"type:uuid;default:gen_random_uuid();primaryKey"`
 	UserID    uuid.UUID `gorm:"type:uuid;not null;index"`
@@ -10,7 +10,7 @@ import (
 
 type Movie struct {
 	ID          uuid.UUID      `gorm:"type:uuid;default:gen_random_uuid();primaryKey"`
-	TMDBID      int            `gorm:"uniqueIndex;not null"`
+	TMDBID      int            `gorm:"uniqueIndex:idx_movies_media_tmdb,priority:2;not null"`
 	Title       string         `gorm:"type:text;not null"`
 	Year        int            `gorm:"index"`
 	PosterURL   string         `gorm:"type:text"`
@@ -22,4 +22,62 @@ type Movie struct {
 	CreatedAt   time.Time      `gorm:"not null;default:now()"`
 	UpdatedAt   time.Time      `gorm:"not null;default:now()"`
 	DeletedAt   gorm.DeletedAt `gorm:"index" json:"-"`
+
+	// MediaType is MediaMovie or MediaTV; series share the table so lists
+	// can hold both.
+	MediaType string `gorm:"type:text;not null;default:'movie';uniqueIndex:idx_movies_media_tmdb,priority:1"`
+}
+
+// Media types of a Movie row and of the watchlist items pointing at it.
+const (
+	MediaMovie = "movie"
+	MediaTV    = "tv"
+)
+
+// Episode is one episode of a series, cached from TMDB a season at a time.
+type Episode struct {
+	ID            uuid.UUID  `gorm:"type:uuid;default:gen_random_uuid();primaryKey" json:"id"`
+	SeriesID      uuid.UUID  `gorm:"type:uuid;not null" json:"series_id"`
+	SeasonNumber  int        `gorm:"not null" json:"season_number"`
+	EpisodeNumber int        `gorm:"not null" json:"episode_number"`
+	TMDBID        int        `gorm:"not null" json:"tmdb_id"`
+	Name          string     `gorm:"not null;default:''" json:"name"`
+	Overview      string     `gorm:"not null;default:''" json:"overview"`
+	AirDate       *time.Time `gorm:"type:date" json:"air_date"`
+	Runtime       *int       `json:"runtime"`
+	StillPath     string     `gorm:"not null;default:''" json:"still_path"`
+	UpdatedAt     time.Time  `gorm:"not null;default:now()" json:"updated_at"`
+	// Watched is filled per request for the signed-in viewer.
+	Watched bool `gorm:"-" json:"watched"`
+}
+
+func (Episode) TableName() string { return "tv_episodes" }
+
+// EpisodeWatch marks an episode watched by a user.
+type EpisodeWatch struct {
+	UserID    uuid.UUID `gorm:"type:uuid;primaryKey"`
+	EpisodeID uuid.UUID `gorm:"type:uuid;primaryKey"`
+	WatchedAt time.Time `gorm:"not null;default:now()"`
+}
+
+func (EpisodeWatch) TableName() string { return "episode_watches" }
+
+// MovieRatingStats aggregates Scenee reviews of a movie. Histogram[i] counts
+// ratings of i+1.
+type MovieRatingStats struct {
+	MovieID   uuid.UUID `gorm:"type:uuid;primaryKey"`
+	Count     int       `gorm:"column:rating_count;not null;default:0"`
+	Sum       int64     `gorm:"column:rating_sum;not null;default:0"`
+	Histogram []int     `gorm:"type:jsonb;serializer:json;not null"`
+	UpdatedAt time.Time `gorm:"not null;default:now()"`
+}
+
+func (MovieRatingStats) TableName() string { return "movie_rating_stats" }
+
+// Average is the mean rating, or 0 without ratings.
+func (s *MovieRatingStats) Average() float64 {
+	if s.Count == 0 {
+		return 0
+	}
+	return float64(s.Sum) / float64(s.Count)
 }
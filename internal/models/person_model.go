@@ -0,0 +1,37 @@
+package models
+
+import (
+	"time"
+
+	"github.com/google/uuid"
+	"gorm.io/datatypes"
+)
+
+// Person is an actor or crew member cached from TMDB. Credits holds their
+// combined movie and TV credits as JSON (see domain.Credit).
+type Person struct {
+	ID                 uuid.UUID      `gorm:"type:uuid;default:gen_random_uuid();primaryKey"`
+	TMDBID             int            `gorm:"uniqueIndex;not null"`
+	Name               string         `gorm:"type:text;not null"`
+	Biography          string         `gorm:"type:text;not null;default:''"`
+	KnownForDepartment string         `gorm:"type:text;not null;default:''"`
+	ProfilePath        string         `gorm:"type:text;not null;default:''"`
+	Birthday           *time.Time     `gorm:"type:date"`
+	Deathday           *time.Time     `gorm:"type:date"`
+	PlaceOfBirth       string         `gorm:"type:text;not null;default:''"`
+	Credits            datatypes.JSON `gorm:"type:jsonb;not null;default:'[]'"`
+	FetchedAt          time.Time      `gorm:"not null;default:now()"`
+	CreatedAt          time.Time      `gorm:"not null;default:now()"`
+	UpdatedAt          time.Time      `gorm:"not null;default:now()"`
+}
+
+func (Person) TableName() string { return "people" }
+
+// PersonFollow subscribes a user to a person's new credits.
+type PersonFollow struct {
+	UserID    uuid.UUID `gorm:"type:uuid;primaryKey"`
+	PersonID  uuid.UUID `gorm:"type:uuid;primaryKey;index"`
+	CreatedAt time.Time `gorm:"not null;default:now()"`
+}
+
+func (PersonFollow) TableName() string { return "person_follows" }
@@ -8,15 +8,59 @@ import (
 )
 
 type Review struct {
-	ID        uuid.UUID      `gorm:"type:uuid;default:gen_random_uuid();primaryKey" json:"id"`
-	UserID    uuid.UUID      `gorm:"type:uuid;not null;index" json:"user_id"`
-	MovieID   uuid.UUID      `gorm:"type:uuid;not null;index" json:"movie_id"`
-	Rating    int            `gorm:"not null;check:rating >= 1 AND rating <= 10" json:"rating"`
-	Review    string         `gorm:"type:text" json:"review"`
-	CreatedAt time.Time      `json:"created_at"`
-	UpdatedAt time.Time      `json:"updated_at"`
-	DeletedAt gorm.DeletedAt `gorm:"index" json:"-"`
-	User      User           `gorm:"foreignKey:UserID" json:"user"`
+	ID         uuid.UUID      `gorm:"type:uuid;default:gen_random_uuid();primaryKey" json:"id"`
+	UserID     uuid.UUID      `gorm:"type:uuid;not null;index" json:"user_id"`
+	MovieID    uuid.UUID      `gorm:"type:uuid;not null;index" json:"movie_id"`
+	Rating     int            `gorm:"not null;check:rating >= 1 AND rating <= 10" json:"rating"`
+	Review     string         `gorm:"type:text" json:"review"`
+	LikeCount  int            `gorm:"not null;default:0" json:"like_count"`
+	ReplyCount int            `gorm:"not null;default:0" json:"reply_count"`
+	CreatedAt  time.Time      `json:"created_at"`
+	UpdatedAt  time.Time      `json:"updated_at"`
+	DeletedAt  gorm.DeletedAt `gorm:"index" json:"-"`
+	User       User           `gorm:"foreignKey:UserID" json:"user"`
+
+	// Spoiler marks the whole review as a spoiler; inline ||spans|| mark parts.
+	Spoiler         bool     `gorm:"not null;default:false" json:"spoiler"`
+	ContentWarnings []string `gorm:"type:jsonb;serializer:json;not null;default:'[]'" json:"content_warnings"`
+	// LikedByMe is filled per request for the signed-in viewer.
+	LikedByMe *bool `gorm:"-" json:"liked_by_me,omitempty"`
+	// Movie is only preloaded where a listing spans movies.
+	Movie *Movie `gorm:"foreignKey:MovieID" json:"-"`
+
+	// Rendered forms of Review, filled by the review service when it builds
+	// a response. Clients show Preview (spoilers redacted) until the reader
+	// asks for ReviewHTML.
+	ReviewHTML  string `gorm:"-" json:"review_html"`
+	Preview     string `gorm:"-" json:"preview"`
+	HasSpoilers bool   `gorm:"-" json:"has_spoilers"`
 }
 
 func (Review) TableName() string { return "reviews" }
+
+// ContentWarnings reviewers can attach to a review.
+var ContentWarnings = []string{
+	"violence", "gore", "sexual_content", "self_harm", "suicide",
+	"substance_use", "abuse", "animal_harm", "flashing_lights",
+}
+
+type ReviewLike struct {
+	UserID    uuid.UUID `gorm:"type:uuid;primaryKey"`
+	ReviewID  uuid.UUID `gorm:"type:uuid;primaryKey;index"`
+	CreatedAt time.Time `gorm:"not null;default:now()"`
+}
+
+func (ReviewLike) TableName() string { return "review_likes" }
+
+// ReviewReply is a reply in the flat thread under a review.
+type ReviewReply struct {
+	ID        uuid.UUID `gorm:"type:uuid;default:gen_random_uuid();primaryKey" json:"id"`
+	ReviewID  uuid.UUID `gorm:"type:uuid;not null;index" json:"review_id"`
+	UserID    uuid.UUID `gorm:"type:uuid;not null" json:"user_id"`
+	Body      string    `gorm:"type:text;not null" json:"body"`
+	CreatedAt time.Time `gorm:"not null;default:now()" json:"created_at"`
+	UpdatedAt time.Time `gorm:"not null;default:now()" json:"updated_at"`
+	User      User      `gorm:"foreignKey:UserID" json:"user"`
+}
+
+func (ReviewReply) TableName() string { return "review_replies" }
@@ -0,0 +1,51 @@
+package models
+
+import (
+	"encoding/json"
+
+	"github.com/google/uuid"
+	"gorm.io/datatypes"
+)
+
+const (
+	ManualWatchlist = "manual"
+	SmartWatchlist  = "smart"
+)
+
+// SmartRules define which movies belong to a smart watchlist. Zero values mean
+// "no constraint"; user-state rules (watched, rated, in another list) are
+// evaluated against the list owner.
+type SmartRules struct {
+	Genres         []string   `json:"genres,omitempty" validate:"max=10,dive,min=1,max=50"` // any of
+	ExcludeGenres  []string   `json:"exclude_genres,omitempty" validate:"max=10,dive,min=1,max=50"`
+	YearFrom       int        `json:"year_from,omitempty" validate:"omitempty,gte=1888,lte=9999"`
+	YearTo         int        `json:"year_to,omitempty" validate:"omitempty,gte=1888,lte=9999,gtefield=YearFrom"`
+	RuntimeMin     int        `json:"runtime_min,omitempty" validate:"omitempty,gte=1,lte=1000"`
+	RuntimeMax     int        `json:"runtime_max,omitempty" validate:"omitempty,gte=1,lte=1000,gtefield=RuntimeMin"`
+	Watched        *bool      `json:"watched,omitempty"`
+	Rated          *bool      `json:"rated,omitempty"`
+	MinRating      int        `json:"min_rating,omitempty" validate:"omitempty,gte=1,lte=10"`
+	InWatchlist    *uuid.UUID `json:"in_watchlist,omitempty"`
+	NotInWatchlist *uuid.UUID `json:"not_in_watchlist,omitempty"`
+	Sort           string     `json:"sort,omitempty" validate:"omitempty,oneof=year_desc year_asc title runtime_asc runtime_desc recently_added"`
+	Limit          int        `json:"limit,omitempty" validate:"omitempty,gte=1,lte=500"`
+}
+
+func EncodeSmartRules(r *SmartRules) datatypes.JSON {
+	if r == nil {
+		return nil
+	}
+	b, _ := json.Marshal(r)
+	return datatypes.JSON(b)
+}
+
+func DecodeSmartRules(j datatypes.JSON) *SmartRules {
+	if len(j) == 0 {
+		return nil
+	}
+	var out SmartRules
+	if err := json.Unmarshal(j, &out); err != nil {
+		return nil
+	}
+	return &out
+}
@@ -20,17 +20,79 @@ func (l *Like) BeforeCreate(tx *gorm.DB) error {
 }
 
 type Save struct {
-	UserID      uuid.UUID `gorm:"type:uuid;not null"`
-	WatchlistID uuid.UUID `gorm:"type:uuid;not null;index"`
-	CreatedAt   time.Time `gorm:"not null;default:now()"`
+	UserID       uuid.UUID  `gorm:"type:uuid;primaryKey"`
+	WatchlistID  uuid.UUID  `gorm:"type:uuid;primaryKey;index"`
+	CollectionID *uuid.UUID `gorm:"type:uuid;index"`
+	CreatedAt    time.Time  `gorm:"not null;default:now()"`
 }
 
 func (Save) TableName() string { return "saves" }
 
+// SaveCollection is a user's folder of saved watchlists.
+type SaveCollection struct {
+	ID        uuid.UUID `gorm:"type:uuid;default:gen_random_uuid();primaryKey" json:"id"`
+	UserID    uuid.UUID `gorm:"type:uuid;not null;uniqueIndex:idx_save_collections_user_name" json:"-"`
+	Name      string    `gorm:"type:citext;not null;uniqueIndex:idx_save_collections_user_name" json:"name"`
+	CreatedAt time.Time `gorm:"not null;default:now()" json:"created_at"`
+	UpdatedAt time.Time `gorm:"not null;default:now()" json:"updated_at"`
+	// SaveCount is filled when listing collections.
+	SaveCount int `gorm:"->;-:migration" json:"save_count"`
+}
+
+func (SaveCollection) TableName() string { return "save_collections" }
+
+// WatchlistView dedupes views: a viewer counts once per watchlist per bucket.
+// ViewerKey is the user ID or a hash of the client address and user agent.
+type WatchlistView struct {
+	WatchlistID uuid.UUID `gorm:"type:uuid;primaryKey"`
+	ViewerKey   string    `gorm:"type:text;primaryKey"`
+	Bucket      time.Time `gorm:"primaryKey"`
+	CreatedAt   time.Time `gorm:"not null;default:now()"`
+}
+
+func (WatchlistView) TableName() string { return "watchlist_views" }
+
 type Follow struct {
-	FollowerID uuid.UUID `gorm:"type:uuid;not null;index"`
-	FolloweeID uuid.UUID `gorm:"type:uuid;not null;index"`
+	FollowerID uuid.UUID `gorm:"type:uuid;primaryKey"`
+	FolloweeID uuid.UUID `gorm:"type:uuid;primaryKey;index"`
 	CreatedAt  time.Time `gorm:"not null;default:now()"`
 }
 
 func (Follow) TableName() string { return "follows" }
+
+// FollowRequest is a pending follow of a private account.
+type FollowRequest struct {
+	RequesterID uuid.UUID `gorm:"type:uuid;primaryKey" json:"-"`
+	TargetID    uuid.UUID `gorm:"type:uuid;primaryKey" json:"-"`
+	CreatedAt   time.Time `gorm:"not null;default:now()" json:"created_at"`
+	Requester   User      `gorm:"foreignKey:RequesterID" json:"-"`
+}
+
+func (FollowRequest) TableName() string { return "follow_requests" }
+
+// Block hides BlockerID and BlockedID from each other.
+type Block struct {
+	BlockerID uuid.UUID `gorm:"type:uuid;primaryKey"`
+	BlockedID uuid.UUID `gorm:"type:uuid;primaryKey;index"`
+	CreatedAt time.Time `gorm:"not null;default:now()"`
+}
+
+func (Block) TableName() string { return "user_blocks" }
+
+// Mute hides MutedID from MuterID's feed and notifications only.
+type Mute struct {
+	MuterID   uuid.UUID `gorm:"type:uuid;primaryKey"`
+	MutedID   uuid.UUID `gorm:"type:uuid;primaryKey"`
+	CreatedAt time.Time `gorm:"not null;default:now()"`
+}
+
+func (Mute) TableName() string { return "user_mutes" }
+
+// SuggestionDismissal keeps DismissedID out of UserID's follow suggestions.
+type SuggestionDismissal struct {
+	UserID      uuid.UUID `gorm:"type:uuid;primaryKey"`
+	DismissedID uuid.UUID `gorm:"type:uuid;primaryKey"`
+	CreatedAt   time.Time `gorm:"not null;default:now()"`
+}
+
+func (SuggestionDismissal) TableName() string { return "suggestion_dismissals" }
@@ -17,5 +17,9 @@ type User struct {
 	Username  string         `gorm:"uniqueIndex" json:"username"`
 	Password  string         `gorm:"not null" json:"-"`
 	AvatarUrl string         `json:"avatar_url"`
+	AvatarKey string         `gorm:"not null;default:''" json:"-"`
 	Role      string         `gorm:"type:text;not null;default:'user';check:role IN ('user','admin')" json:"role"`
+
+	// IsPrivate turns follows into requests the user approves.
+	IsPrivate bool `gorm:"not null;default:false" json:"is_private"`
 }
@@ -0,0 +1,101 @@
+package models
+
+import (
+	"encoding/json"
+	"time"
+
+	"github.com/google/uuid"
+	"gorm.io/datatypes"
+)
+
+// Watchlist revision actions.
+const (
+	RevisionCreate     = "create"
+	RevisionUpdate     = "update"
+	RevisionDelete     = "delete"
+	RevisionRestore    = "restore"
+	RevisionAddItem    = "add_item"
+	RevisionRemoveItem = "remove_item"
+	RevisionMoveItem   = "move_item"
+	RevisionEditNote   = "edit_note"
+	// RevisionRevert holds a ListSnapshot of the whole list either side.
+	RevisionRevert = "revert"
+)
+
+// WatchlistRevision is an append-only record of a change to a watchlist or one
+// of its items. Before/After hold a WatchlistSnapshot or ItemSnapshot. Seq
+// orders revisions, including those written in the same transaction, and is
+// the history's page cursor.
+type WatchlistRevision struct {
+	ID          uuid.UUID      `gorm:"type:uuid;default:gen_random_uuid();primaryKey" json:"id"`
+	Seq         int64          `gorm:"->" json:"seq"`
+	WatchlistID uuid.UUID      `gorm:"type:uuid;not null;index" json:"watchlist_id"`
+	ActorID     uuid.UUID      `gorm:"type:uuid;not null" json:"actor_id"`
+	Action      string         `gorm:"type:text;not null" json:"action"`
+	ItemID      *uuid.UUID     `gorm:"type:uuid" json:"item_id,omitempty"`
+	Before      datatypes.JSON `gorm:"type:jsonb" json:"before,omitempty"`
+	After       datatypes.JSON `gorm:"type:jsonb" json:"after,omitempty"`
+	CreatedAt   time.Time      `gorm:"not null;default:now();index" json:"created_at"`
+}
+
+func (WatchlistRevision) TableName() string { return "watchlist_revisions" }
+
+// WatchlistSnapshot captures the editable metadata of a watchlist.
+type WatchlistSnapshot struct {
+	Title       string         `json:"title"`
+	Description string         `json:"description"`
+	Slug        string         `json:"slug"`
+	Visibility  string         `json:"visibility"`
+	Tags        []string       `json:"tags"`
+	Rules       datatypes.JSON `json:"rules,omitempty"`
+}
+
+func SnapshotWatchlist(w *Watchlist) WatchlistSnapshot {
+	return WatchlistSnapshot{
+		Title:       w.Title,
+		Description: w.Description,
+		Slug:        w.Slug,
+		Visibility:  w.Visibility,
+		Tags:        append([]string(nil), w.Tags...),
+		Rules:       w.Rules,
+	}
+}
+
+// Apply copies the snapshot back onto w.
+func (s WatchlistSnapshot) Apply(w *Watchlist) {
+	w.Title = s.Title
+	w.Description = s.Description
+	w.Slug = s.Slug
+	w.Visibility = s.Visibility
+	w.Tags = s.Tags
+	w.Rules = s.Rules
+}
+
+// ItemSnapshot captures a watchlist item.
+type ItemSnapshot struct {
+	ID       uuid.UUID `json:"id"`
+	MovieID  uuid.UUID `json:"movie_id"`
+	Note     string    `json:"note"`
+	Position int       `json:"position"`
+
+	MediaType string `json:"media_type,omitempty"`
+}
+
+func SnapshotItem(it *WatchlistItem) ItemSnapshot {
+	return ItemSnapshot{ID: it.ID, MovieID: it.MovieID, Note: it.Note, Position: it.Position, MediaType: it.MediaType}
+}
+
+// ListSnapshot captures a whole list: its metadata and items in order.
+type ListSnapshot struct {
+	Watchlist WatchlistSnapshot `json:"watchlist"`
+	Items     []ItemSnapshot    `json:"items"`
+}
+
+// EncodeSnapshot marshals a snapshot for WatchlistRevision.Before/After.
+func EncodeSnapshot(v any) datatypes.JSON {
+	if v == nil {
+		return nil
+	}
+	b, _ := json.Marshal(v)
+	return datatypes.JSON(b)
+}
@@ -4,6 +4,7 @@ import (
 	"time"
 
 	"github.com/google/uuid"
+	"gorm.io/datatypes"
 	"gorm.io/gorm"
 )
 
@@ -17,18 +18,49 @@ type Watchlist struct {
 	Owner   User   `gorm:"foreignKey:OwnerID"`
 	Slug    string `gorm:"type:citext;uniqueIndex;not null" json:"slug"`
 
-	Title       string   `gorm:"not null" json:"title"`
-	Description string   `json:"description"`
-	CoverUrl    string   `json:"cover_url"`
+	Title       string `gorm:"not null" json:"title"`
+	Description string `json:"description"`
+	CoverUrl    string `json:"cover_url"`
+	// CoverKey is the blob store key behind CoverUrl. CoverCustom marks an
+	// uploaded cover that generated collages leave alone.
+	CoverKey    string   `gorm:"not null;default:''" json:"-"`
+	CoverCustom bool     `gorm:"not null;default:false" json:"cover_custom"`
 	LikeCount   int      `gorm:"default:0" json:"like_count"`
 	SaveCount   int      `gorm:"default:0" json:"save_count"`
 	ItemCount   int      `gorm:"default:0" json:"item_count"`
 	ViewCount   int      `gorm:"default:0" json:"view_count"`
 	Visibility  string   `gorm:"type:text;not null;check:visibility IN ('public','private','unlisted');default:'private'" json:"visibility"`
-	SavedBy     []string `gorm:"type:jsonb;default:'[]'" json:"-"`
-	Tags        []string `gorm:"type:jsonb;default:'[]'" json:"tags"`
+	Tags        []string `gorm:"type:jsonb;serializer:json;default:'[]'" json:"tags"`
+	// CommentsEnabled lets the owner close the discussion on a list.
+	CommentsEnabled bool `gorm:"not null;default:true" json:"comments_enabled"`
+	// ForkedFromID is the watchlist this one was copied from.
+	ForkedFromID *uuid.UUID `gorm:"type:uuid;index" json:"forked_from_id,omitempty"`
+
+	// Smart lists compute their items from Rules; RefreshedAt marks the last
+	// materialization.
+	Kind        string         `gorm:"type:text;not null;check:kind IN ('manual','smart');default:'manual'" json:"kind"`
+	Rules       datatypes.JSON `gorm:"type:jsonb" json:"rules,omitempty"`
+	RefreshedAt *time.Time     `json:"refreshed_at,omitempty"`
+
+	Items []WatchlistItem `gorm:"foreignKey:WatchlistID" json:"items,omitempty"`
+	// WatchedCount is the viewer's progress through the list, filled per request.
+	WatchedCount *int `gorm:"-" json:"watched_count,omitempty"`
 }
 
+// WatchlistRanking is a precomputed trending position. Dimension is "all",
+// "genre" or "tag", with Value holding the genre or tag.
+type WatchlistRanking struct {
+	TimeWindow  string    `gorm:"primaryKey"`
+	Dimension   string    `gorm:"primaryKey"`
+	Value       string    `gorm:"primaryKey"`
+	WatchlistID uuid.UUID `gorm:"type:uuid;primaryKey"`
+	Score       float64   `gorm:"not null"`
+	Rank        int       `gorm:"not null"`
+	ComputedAt  time.Time `gorm:"not null;default:now()"`
+}
+
+func (WatchlistRanking) TableName() string { return "watchlist_rankings" }
+
 type WatchlistItem struct {
 	ID          uuid.UUID `gorm:"type:uuid;default:gen_random_uuid();primaryKey"`
 	WatchlistID uuid.UUID `gorm:"type:uuid;not null;index"`
@@ -36,4 +68,18 @@ type WatchlistItem struct {
 	Note        string    `gorm:"type:text"`
 	Position    int       `gorm:"not null;index"`
 	AddedAt     time.Time `gorm:"not null;default:now()"`
+	Watched     bool      `gorm:"-"` // by the viewer, filled per request
+
+	// MediaType says whether MovieID is a movie or a series.
+	MediaType string `gorm:"type:text;not null;default:'movie'"`
 }
+
+// WatchlistSlugRedirect remembers slugs a watchlist used to have so old links
+// keep resolving after a rename.
+type WatchlistSlugRedirect struct {
+	Slug        string    `gorm:"type:citext;primaryKey"`
+	WatchlistID uuid.UUID `gorm:"type:uuid;not null;index"`
+	CreatedAt   time.Time `gorm:"not null;default:now()"`
+}
+
+func (WatchlistSlugRedirect) TableName() string { return "watchlist_slug_redirects" }
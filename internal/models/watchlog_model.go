@@ -0,0 +1,24 @@
+package models
+
+import (
+	"time"
+
+	"github.com/google/uuid"
+)
+
+// WatchLog is a single diary entry: a user watched a movie on a given day.
+type WatchLog struct {
+	ID        uuid.UUID  `gorm:"type:uuid;default:gen_random_uuid();primaryKey" json:"id"`
+	UserID    uuid.UUID  `gorm:"type:uuid;not null;index" json:"user_id"`
+	MovieID   uuid.UUID  `gorm:"type:uuid;not null;index" json:"movie_id"`
+	WatchedOn time.Time  `gorm:"type:date;not null;index" json:"watched_on"`
+	Rewatch   bool       `gorm:"not null;default:false" json:"rewatch"`
+	ReviewID  *uuid.UUID `gorm:"type:uuid" json:"review_id,omitempty"`
+	Note      string     `gorm:"type:text" json:"note"`
+	CreatedAt time.Time  `gorm:"not null;default:now()" json:"created_at"`
+
+	Movie  Movie   `gorm:"foreignKey:MovieID" json:"-"`
+	Review *Review `gorm:"foreignKey:ReviewID" json:"-"`
+}
+
+func (WatchLog) TableName() string { return "watch_logs" }
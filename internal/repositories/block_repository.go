@@ -0,0 +1,109 @@
+package repositories
+
+import (
+	"context"
+
+	"github.com/google/uuid"
+	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
+
+	"github.com/Dubjay18/scenee/internal/models"
+)
+
+type BlockRepository interface {
+	// Block records the block and removes follows in both directions.
+	Block(ctx context.Context, blockerID, blockedID string) error
+	Unblock(ctx context.Context, blockerID, blockedID string) error
+	Mute(ctx context.Context, muterID, mutedID string) error
+	Unmute(ctx context.Context, muterID, mutedID string) error
+
+	// EitherBlocked reports whether a has blocked b or b has blocked a.
+	EitherBlocked(ctx context.Context, a, b string) (bool, error)
+	// HiddenIDs returns everyone userID has blocked or been blocked by.
+	HiddenIDs(ctx context.Context, userID string) ([]uuid.UUID, error)
+	MutedIDs(ctx context.Context, userID string) ([]uuid.UUID, error)
+
+	ListBlocked(ctx context.Context, userID string) ([]models.User, error)
+	ListMuted(ctx context.Context, userID string) ([]models.User, error)
+}
+
+type GormBlockRepository struct {
+	db *gorm.DB
+}
+
+func NewBlockRepository(db *gorm.DB) *GormBlockRepository {
+	return &GormBlockRepository{db: db}
+}
+
+func (r *GormBlockRepository) Block(ctx context.Context, blockerID, blockedID string) error {
+	return r.db.WithContext(ctx).Transaction(func(tx *gorm.DB) error {
+		block := models.Block{BlockerID: parseUUID(blockerID), BlockedID: parseUUID(blockedID)}
+		if err := tx.Clauses(clause.OnConflict{DoNothing: true}).Create(&block).Error; err != nil {
+			return err
+		}
+		if err := tx.Where("(follower_id = ? AND followee_id = ?) OR (follower_id = ? AND followee_id = ?)",
+			blockerID, blockedID, blockedID, blockerID).
+			Delete(&models.Follow{}).Error; err != nil {
+			return err
+		}
+		return tx.Where("(requester_id = ? AND target_id = ?) OR (requester_id = ? AND target_id = ?)",
+			blockerID, blockedID, blockedID, blockerID).
+			Delete(&models.FollowRequest{}).Error
+	})
+}
+
+func (r *GormBlockRepository) Unblock(ctx context.Context, blockerID, blockedID string) error {
+	return r.db.WithContext(ctx).Where("blocker_id = ? AND blocked_id = ?", blockerID, blockedID).Delete(&models.Block{}).Error
+}
+
+func (r *GormBlockRepository) Mute(ctx context.Context, muterID, mutedID string) error {
+	mute := models.Mute{MuterID: parseUUID(muterID), MutedID: parseUUID(mutedID)}
+	return r.db.WithContext(ctx).Clauses(clause.OnConflict{DoNothing: true}).Create(&mute).Error
+}
+
+func (r *GormBlockRepository) Unmute(ctx context.Context, muterID, mutedID string) error {
+	return r.db.WithContext(ctx).Where("muter_id = ? AND muted_id = ?", muterID, mutedID).Delete(&models.Mute{}).Error
+}
+
+func (r *GormBlockRepository) EitherBlocked(ctx context.Context, a, b string) (bool, error) {
+	var count int64
+	err := r.db.WithContext(ctx).Model(&models.Block{}).
+		Where("(blocker_id = ? AND blocked_id = ?) OR (blocker_id = ? AND blocked_id = ?)", a, b, b, a).
+		Count(&count).Error
+	return count > 0, err
+}
+
+func (r *GormBlockRepository) HiddenIDs(ctx context.Context, userID string) ([]uuid.UUID, error) {
+	ids := []uuid.UUID{}
+	err := r.db.WithContext(ctx).Raw(`
+		SELECT blocked_id FROM user_blocks WHERE blocker_id = @id
+		UNION
+		SELECT blocker_id FROM user_blocks WHERE blocked_id = @id`,
+		map[string]any{"id": userID}).
+		Scan(&ids).Error
+	return ids, err
+}
+
+func (r *GormBlockRepository) MutedIDs(ctx context.Context, userID string) ([]uuid.UUID, error) {
+	ids := []uuid.UUID{}
+	err := r.db.WithContext(ctx).Model(&models.Mute{}).Where("muter_id = ?", userID).Pluck("muted_id", &ids).Error
+	return ids, err
+}
+
+func (r *GormBlockRepository) ListBlocked(ctx context.Context, userID string) ([]models.User, error) {
+	users := []models.User{}
+	err := r.db.WithContext(ctx).Joins("JOIN user_blocks ON user_blocks.blocked_id = users.id").
+		Where("user_blocks.blocker_id = ?", userID).
+		Order("user_blocks.created_at DESC").
+		Find(&users).Error
+	return users, err
+}
+
+func (r *GormBlockRepository) ListMuted(ctx context.Context, userID string) ([]models.User, error) {
+	users := []models.User{}
+	err := r.db.WithContext(ctx).Joins("JOIN user_mutes ON user_mutes.muted_id = users.id").
+		Where("user_mutes.muter_id = ?", userID).
+		Order("user_mutes.created_at DESC").
+		Find(&users).Error
+	return users, err
+}
@@ -0,0 +1,142 @@
+package repositories
+
+import (
+	"context"
+	"time"
+
+	"github.com/google/uuid"
+	"gorm.io/gorm"
+
+	"github.com/Dubjay18/scenee/internal/models"
+)
+
+// CommentView is a comment with what clients need to render it: the
+// author's public profile and, for top-level comments, the reply count.
+type CommentView struct {
+	models.WatchlistComment
+	AuthorUsername  string `json:"author_username"`
+	AuthorAvatarURL string `json:"author_avatar_url"`
+	ReplyCount      int    `json:"reply_count"`
+}
+
+type CommentRepository interface {
+	Create(ctx context.Context, c *models.WatchlistComment) error
+	Get(ctx context.Context, watchlistID, id string) (*models.WatchlistComment, error)
+	GetView(ctx context.Context, id uuid.UUID) (*CommentView, error)
+	// The listings below skip comments written by the excluded users.
+	ListTopLevel(ctx context.Context, watchlistID string, exclude []uuid.UUID, limit, offset int) ([]CommentView, int64, error)
+	// FirstReplies returns up to n of the oldest replies of each parent.
+	FirstReplies(ctx context.Context, parentIDs, exclude []uuid.UUID, n int) ([]CommentView, error)
+	ListReplies(ctx context.Context, parentID string, exclude []uuid.UUID, limit, offset int) ([]CommentView, int64, error)
+	UpdateBody(ctx context.Context, id uuid.UUID, body string, editedAt time.Time) error
+	// Delete removes a comment and, for a top-level one, its replies.
+	Delete(ctx context.Context, id uuid.UUID) error
+}
+
+type GormCommentRepository struct {
+	db *gorm.DB
+}
+
+func NewCommentRepository(db *gorm.DB) *GormCommentRepository {
+	return &GormCommentRepository{db: db}
+}
+
+const (
+	commentViewColumns = `watchlist_comments.*, users.username AS author_username, users.avatar_url AS author_avatar_url`
+	commentReplyCount  = `, (SELECT count(*) FROM watchlist_comments r WHERE r.parent_id = watchlist_comments.id) AS reply_count`
+)
+
+func (r *GormCommentRepository) views(ctx context.Context, exclude []uuid.UUID) *gorm.DB {
+	q := r.db.WithContext(ctx).Model(&models.WatchlistComment{}).
+		Joins("JOIN users ON users.id = watchlist_comments.author_id")
+	if len(exclude) > 0 {
+		q = q.Where("watchlist_comments.author_id NOT IN ?", exclude)
+	}
+	return q
+}
+
+func (r *GormCommentRepository) Create(ctx context.Context, c *models.WatchlistComment) error {
+	return r.db.WithContext(ctx).Create(c).Error
+}
+
+func (r *GormCommentRepository) Get(ctx context.Context, watchlistID, id string) (*models.WatchlistComment, error) {
+	var c models.WatchlistComment
+	if err := r.db.WithContext(ctx).Where("id = ? AND watchlist_id = ?", id, watchlistID).First(&c).Error; err != nil {
+		return nil, err
+	}
+	return &c, nil
+}
+
+func (r *GormCommentRepository) GetView(ctx context.Context, id uuid.UUID) (*CommentView, error) {
+	var v CommentView
+	err := r.views(ctx, nil).
+		Select(commentViewColumns+commentReplyCount).
+		Where("watchlist_comments.id = ?", id).
+		Take(&v).Error
+	if err != nil {
+		return nil, err
+	}
+	return &v, nil
+}
+
+// ListTopLevel pages through a list's top-level comments, oldest first so a
+// conversation reads top to bottom.
+func (r *GormCommentRepository) ListTopLevel(ctx context.Context, watchlistID string, exclude []uuid.UUID, limit, offset int) ([]CommentView, int64, error) {
+	q := r.views(ctx, exclude).Where("watchlist_comments.watchlist_id = ? AND watchlist_comments.parent_id IS NULL", watchlistID)
+	var total int64
+	if err := q.Count(&total).Error; err != nil {
+		return nil, 0, err
+	}
+	var out []CommentView
+	err := q.Select(commentViewColumns + commentReplyCount).
+		Order("watchlist_comments.created_at ASC, watchlist_comments.id ASC").
+		Limit(limit).Offset(offset).
+		Find(&out).Error
+	return out, total, err
+}
+
+func (r *GormCommentRepository) FirstReplies(ctx context.Context, parentIDs, exclude []uuid.UUID, n int) ([]CommentView, error) {
+	var out []CommentView
+	if len(parentIDs) == 0 {
+		return out, nil
+	}
+	// NOT IN over an empty list would match nothing, so exclude with a
+	// sentinel instead.
+	if len(exclude) == 0 {
+		exclude = []uuid.UUID{uuid.Nil}
+	}
+	err := r.db.WithContext(ctx).Raw(`
+		SELECT * FROM (
+			SELECT `+commentViewColumns+`,
+			       row_number() OVER (PARTITION BY watchlist_comments.parent_id ORDER BY watchlist_comments.created_at, watchlist_comments.id) AS rn
+			FROM watchlist_comments
+			JOIN users ON users.id = watchlist_comments.author_id
+			WHERE watchlist_comments.parent_id IN ? AND watchlist_comments.author_id NOT IN ?
+		) replies
+		WHERE rn <= ?
+		ORDER BY created_at, id`, parentIDs, exclude, n).Scan(&out).Error
+	return out, err
+}
+
+func (r *GormCommentRepository) ListReplies(ctx context.Context, parentID string, exclude []uuid.UUID, limit, offset int) ([]CommentView, int64, error) {
+	q := r.views(ctx, exclude).Where("watchlist_comments.parent_id = ?", parentID)
+	var total int64
+	if err := q.Count(&total).Error; err != nil {
+		return nil, 0, err
+	}
+	var out []CommentView
+	err := q.Select(commentViewColumns).
+		Order("watchlist_comments.created_at ASC, watchlist_comments.id ASC").
+		Limit(limit).Offset(offset).
+		Find(&out).Error
+	return out, total, err
+}
+
+func (r *GormCommentRepository) UpdateBody(ctx context.Context, id uuid.UUID, body string, editedAt time.Time) error {
+	return r.db.WithContext(ctx).Model(&models.WatchlistComment{}).Where("id = ?", id).
+		Updates(map[string]any{"body": body, "edited_at": editedAt}).Error
+}
+
+func (r *GormCommentRepository) Delete(ctx context.Context, id uuid.UUID) error {
+	return r.db.WithContext(ctx).Delete(&models.WatchlistComment{}, "id = ?", id).Error
+}
@@ -0,0 +1,82 @@
+package repositories
+
+import (
+	"context"
+	"time"
+
+	"github.com/google/uuid"
+	"gorm.io/gorm"
+)
+
+// CounterDrift is a watchlist whose denormalized counters disagree with the
+// rows they summarize.
+type CounterDrift struct {
+	WatchlistID uuid.UUID `json:"watchlist_id"`
+	LikeCount   int64     `json:"like_count"`
+	Likes       int64     `json:"likes"`
+	SaveCount   int64     `json:"save_count"`
+	Saves       int64     `json:"saves"`
+	ItemCount   int64     `json:"item_count"`
+	Items       int64     `json:"items"`
+	ViewCount   int64     `json:"view_count"`
+	Views       int64     `json:"views"`
+}
+
+type CounterRepository interface {
+	// FindDrift lists watchlists viewed or updated since since whose
+	// counters are out of sync, at most limit.
+	FindDrift(ctx context.Context, since time.Time, limit int) ([]CounterDrift, error)
+	// Reconcile recomputes the counters of the given watchlists from source tables.
+	Reconcile(ctx context.Context, ids []uuid.UUID) error
+}
+
+type GormCounterRepository struct {
+	db *gorm.DB
+}
+
+func NewCounterRepository(db *gorm.DB) *GormCounterRepository {
+	return &GormCounterRepository{db: db}
+}
+
+// view_count predates watchlist_views, so only a count below the deduped
+// view rows is drift; older views have no rows to recompute from. Only lists
+// with recent views or edits are checked rather than every watchlist.
+const driftQuery = `
+WITH recent AS (
+    SELECT watchlist_id AS id FROM watchlist_views WHERE created_at >= @since
+    UNION
+    SELECT id FROM watchlists WHERE updated_at >= @since
+)
+SELECT w.id AS watchlist_id,
+       w.like_count, l.n AS likes,
+       w.save_count, s.n AS saves,
+       w.item_count, i.n AS items,
+       w.view_count, v.n AS views
+FROM recent
+JOIN watchlists w ON w.id = recent.id
+CROSS JOIN LATERAL (SELECT COUNT(*) AS n FROM likes WHERE watchlist_id = w.id) l
+CROSS JOIN LATERAL (SELECT COUNT(*) AS n FROM saves WHERE watchlist_id = w.id) s
+CROSS JOIN LATERAL (SELECT COUNT(*) AS n FROM watchlist_items WHERE watchlist_id = w.id) i
+CROSS JOIN LATERAL (SELECT COUNT(*) AS n FROM watchlist_views WHERE watchlist_id = w.id) v
+WHERE w.like_count <> l.n OR w.save_count <> s.n OR w.item_count <> i.n OR w.view_count < v.n
+ORDER BY w.id
+LIMIT @limit`
+
+func (r *GormCounterRepository) FindDrift(ctx context.Context, since time.Time, limit int) ([]CounterDrift, error) {
+	var out []CounterDrift
+	err := r.db.WithContext(ctx).Raw(driftQuery, map[string]any{"since": since, "limit": limit}).Scan(&out).Error
+	return out, err
+}
+
+func (r *GormCounterRepository) Reconcile(ctx context.Context, ids []uuid.UUID) error {
+	if len(ids) == 0 {
+		return nil
+	}
+	return r.db.WithContext(ctx).Exec(`
+UPDATE watchlists w SET
+    like_count = (SELECT COUNT(*) FROM likes WHERE watchlist_id = w.id),
+    save_count = (SELECT COUNT(*) FROM saves WHERE watchlist_id = w.id),
+    item_count = (SELECT COUNT(*) FROM watchlist_items WHERE watchlist_id = w.id),
+    view_count = GREATEST(w.view_count, (SELECT COUNT(*) FROM watchlist_views WHERE watchlist_id = w.id))
+WHERE w.id IN ?`, ids).Error
+}
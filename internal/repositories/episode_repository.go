@@ -0,0 +1,142 @@
+package repositories
+
+import (
+	"context"
+	"time"
+
+	"github.com/google/uuid"
+	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
+
+	"github.com/Dubjay18/scenee/internal/models"
+)
+
+// SeasonProgress is how many episodes of one season a user has watched.
+type SeasonProgress struct {
+	SeasonNumber  int
+	Watched       int
+	LastWatchedAt time.Time
+}
+
+type EpisodeRepository interface {
+	// UpsertSeason caches a season's episodes, updating ones already stored.
+	UpsertSeason(ctx context.Context, episodes []models.Episode) error
+	// Season returns the cached episodes of a season in episode order.
+	Season(ctx context.Context, seriesID uuid.UUID, season int) ([]models.Episode, error)
+	Get(ctx context.Context, seriesID uuid.UUID, season, episode int) (*models.Episode, error)
+	WatchedIDs(ctx context.Context, userID string, episodeIDs []uuid.UUID) (map[uuid.UUID]bool, error)
+	MarkWatched(ctx context.Context, userID string, episodeIDs []uuid.UUID) error
+	UnmarkWatched(ctx context.Context, userID string, episodeIDs []uuid.UUID) error
+	// Progress counts userID's watched episodes of a series per season.
+	Progress(ctx context.Context, userID string, seriesID uuid.UUID) ([]SeasonProgress, error)
+	// NextUnwatched is the earliest aired episode of a series, specials
+	// aside, that userID hasn't watched.
+	NextUnwatched(ctx context.Context, userID string, seriesID uuid.UUID, now time.Time) (*models.Episode, error)
+}
+
+type GormEpisodeRepository struct {
+	db *gorm.DB
+}
+
+func NewEpisodeRepository(db *gorm.DB) *GormEpisodeRepository {
+	return &GormEpisodeRepository{db: db}
+}
+
+func (r *GormEpisodeRepository) UpsertSeason(ctx context.Context, episodes []models.Episode) error {
+	if len(episodes) == 0 {
+		return nil
+	}
+	now := time.Now()
+	for i := range episodes {
+		episodes[i].UpdatedAt = now
+	}
+	return r.db.WithContext(ctx).
+		Clauses(clause.OnConflict{
+			Columns:   []clause.Column{{Name: "series_id"}, {Name: "season_number"}, {Name: "episode_number"}},
+			DoUpdates: clause.AssignmentColumns([]string{"tmdb_id", "name", "overview", "air_date", "runtime", "still_path", "updated_at"}),
+		}).
+		Create(&episodes).Error
+}
+
+func (r *GormEpisodeRepository) Season(ctx context.Context, seriesID uuid.UUID, season int) ([]models.Episode, error) {
+	var eps []models.Episode
+	err := r.db.WithContext(ctx).
+		Where("series_id = ? AND season_number = ?", seriesID, season).
+		Order("episode_number").
+		Find(&eps).Error
+	return eps, err
+}
+
+func (r *GormEpisodeRepository) Get(ctx context.Context, seriesID uuid.UUID, season, episode int) (*models.Episode, error) {
+	var ep models.Episode
+	if err := r.db.WithContext(ctx).
+		Where("series_id = ? AND season_number = ? AND episode_number = ?", seriesID, season, episode).
+		First(&ep).Error; err != nil {
+		return nil, err
+	}
+	return &ep, nil
+}
+
+func (r *GormEpisodeRepository) WatchedIDs(ctx context.Context, userID string, episodeIDs []uuid.UUID) (map[uuid.UUID]bool, error) {
+	out := make(map[uuid.UUID]bool, len(episodeIDs))
+	if len(episodeIDs) == 0 {
+		return out, nil
+	}
+	var ids []uuid.UUID
+	if err := r.db.WithContext(ctx).Model(&models.EpisodeWatch{}).
+		Where("user_id = ? AND episode_id IN ?", userID, episodeIDs).
+		Pluck("episode_id", &ids).Error; err != nil {
+		return nil, err
+	}
+	for _, id := range ids {
+		out[id] = true
+	}
+	return out, nil
+}
+
+func (r *GormEpisodeRepository) MarkWatched(ctx context.Context, userID string, episodeIDs []uuid.UUID) error {
+	if len(episodeIDs) == 0 {
+		return nil
+	}
+	uid := parseUUID(userID)
+	now := time.Now()
+	watches := make([]models.EpisodeWatch, 0, len(episodeIDs))
+	for _, id := range episodeIDs {
+		watches = append(watches, models.EpisodeWatch{UserID: uid, EpisodeID: id, WatchedAt: now})
+	}
+	return r.db.WithContext(ctx).Clauses(clause.OnConflict{DoNothing: true}).Create(&watches).Error
+}
+
+func (r *GormEpisodeRepository) UnmarkWatched(ctx context.Context, userID string, episodeIDs []uuid.UUID) error {
+	if len(episodeIDs) == 0 {
+		return nil
+	}
+	return r.db.WithContext(ctx).
+		Where("user_id = ? AND episode_id IN ?", userID, episodeIDs).
+		Delete(&models.EpisodeWatch{}).Error
+}
+
+func (r *GormEpisodeRepository) Progress(ctx context.Context, userID string, seriesID uuid.UUID) ([]SeasonProgress, error) {
+	var rows []SeasonProgress
+	err := r.db.WithContext(ctx).Table("episode_watches w").
+		Select("e.season_number, COUNT(*) AS watched, MAX(w.watched_at) AS last_watched_at").
+		Joins("JOIN tv_episodes e ON e.id = w.episode_id").
+		Where("w.user_id = ? AND e.series_id = ?", userID, seriesID).
+		Group("e.season_number").
+		Order("e.season_number").
+		Scan(&rows).Error
+	return rows, err
+}
+
+func (r *GormEpisodeRepository) NextUnwatched(ctx context.Context, userID string, seriesID uuid.UUID, now time.Time) (*models.Episode, error) {
+	var ep models.Episode
+	err := r.db.WithContext(ctx).
+		Where("series_id = ? AND season_number > 0 AND air_date <= ?", seriesID, now).
+		Where("NOT EXISTS (SELECT 1 FROM episode_watches w WHERE w.episode_id = tv_episodes.id AND w.user_id = ?)", userID).
+		Order("season_number, episode_number").
+		First(&ep).Error
+	if err != nil {
+		return nil, err
+	}
+	return &ep, nil
+}
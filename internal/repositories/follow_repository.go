@@ -2,19 +2,65 @@ package repositories
 
 import (
 	"context"
+	"time"
 
 	"github.com/google/uuid"
 	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
 
 	"github.com/Dubjay18/scenee/internal/models"
 )
 
+// FollowCursor is where a page of follows ends: the follow's time, with the
+// listed user's ID breaking ties.
+type FollowCursor struct {
+	At time.Time
+	ID uuid.UUID
+}
+
+// FollowQuery pages through one side of UserID's follows, newest first,
+// starting after After and skipping the excluded users.
+type FollowQuery struct {
+	UserID  string
+	After   *FollowCursor
+	Exclude []uuid.UUID
+	Limit   int
+}
+
+// FollowEdge is a listed user with the time of the follow that lists them.
+type FollowEdge struct {
+	models.User
+	FollowedAt time.Time
+}
+
 type FollowRepository interface {
+	// Follow is a no-op when already following.
 	Follow(ctx context.Context, followerID, followeeID string) error
 	Unfollow(ctx context.Context, followerID, followeeID string) error
 	IsFollowing(ctx context.Context, followerID, followeeID string) (bool, error)
-	GetFollowers(ctx context.Context, userID string) ([]models.User, error)
-	GetFollowing(ctx context.Context, userID string) ([]models.User, error)
+	FollowingIDs(ctx context.Context, userID string) ([]uuid.UUID, error)
+
+	// The listings below return a page and the total across all pages.
+	Followers(ctx context.Context, q FollowQuery) ([]FollowEdge, int64, error)
+	Following(ctx context.Context, q FollowQuery) ([]FollowEdge, int64, error)
+	// MutualFollowers lists users following both q.UserID and otherID.
+	MutualFollowers(ctx context.Context, q FollowQuery, otherID string) ([]FollowEdge, int64, error)
+	// FollowersFollowedBy lists q.UserID's followers that viewerID follows.
+	FollowersFollowedBy(ctx context.Context, q FollowQuery, viewerID string) ([]FollowEdge, int64, error)
+
+	// Relationships reports which of ids viewerID follows and which follow
+	// viewerID.
+	Relationships(ctx context.Context, viewerID string, ids []uuid.UUID) (following, followedBy map[uuid.UUID]bool, err error)
+
+	// Requests to follow private accounts.
+	Request(ctx context.Context, requesterID, targetID string) error
+	HasRequested(ctx context.Context, requesterID, targetID string) (bool, error)
+	// DeleteRequest reports whether there was a request to delete.
+	DeleteRequest(ctx context.Context, requesterID, targetID string) (bool, error)
+	// ApproveRequest turns a request into a follow; gorm.ErrRecordNotFound
+	// if there is none.
+	ApproveRequest(ctx context.Context, requesterID, targetID string) error
+	ListRequests(ctx context.Context, targetID string, exclude []uuid.UUID) ([]models.FollowRequest, error)
 }
 
 type GormFollowRepository struct {
@@ -27,7 +73,7 @@ func NewFollowRepository(db *gorm.DB) *GormFollowRepository {
 
 func (r *GormFollowRepository) Follow(ctx context.Context, followerID, followeeID string) error {
 	follow := models.Follow{FollowerID: parseUUID(followerID), FolloweeID: parseUUID(followeeID)}
-	return r.db.WithContext(ctx).Create(&follow).Error
+	return r.db.WithContext(ctx).Clauses(clause.OnConflict{DoNothing: true}).Create(&follow).Error
 }
 
 func (r *GormFollowRepository) Unfollow(ctx context.Context, followerID, followeeID string) error {
@@ -40,16 +86,127 @@ func (r *GormFollowRepository) IsFollowing(ctx context.Context, followerID, foll
 	return count > 0, err
 }
 
-func (r *GormFollowRepository) GetFollowers(ctx context.Context, userID string) ([]models.User, error) {
-	var users []models.User
-	err := r.db.WithContext(ctx).Joins("JOIN follows ON follows.follower_id = users.id").Where("follows.followee_id = ?", userID).Find(&users).Error
-	return users, err
+// Listings join the follows they list as f.
+const (
+	joinFollowers = "JOIN follows f ON f.follower_id = users.id AND f.followee_id = ?"
+	joinFollowing = "JOIN follows f ON f.followee_id = users.id AND f.follower_id = ?"
+)
+
+func (r *GormFollowRepository) Followers(ctx context.Context, q FollowQuery) ([]FollowEdge, int64, error) {
+	return r.edges(r.listing(ctx, q, joinFollowers), q)
+}
+
+func (r *GormFollowRepository) Following(ctx context.Context, q FollowQuery) ([]FollowEdge, int64, error) {
+	return r.edges(r.listing(ctx, q, joinFollowing), q)
+}
+
+func (r *GormFollowRepository) MutualFollowers(ctx context.Context, q FollowQuery, otherID string) ([]FollowEdge, int64, error) {
+	db := r.listing(ctx, q, joinFollowers).
+		Where("EXISTS (SELECT 1 FROM follows o WHERE o.follower_id = users.id AND o.followee_id = ?)", otherID)
+	return r.edges(db, q)
+}
+
+func (r *GormFollowRepository) FollowersFollowedBy(ctx context.Context, q FollowQuery, viewerID string) ([]FollowEdge, int64, error) {
+	db := r.listing(ctx, q, joinFollowers).
+		Where("EXISTS (SELECT 1 FROM follows v WHERE v.follower_id = ? AND v.followee_id = users.id)", viewerID)
+	return r.edges(db, q)
+}
+
+func (r *GormFollowRepository) listing(ctx context.Context, q FollowQuery, join string) *gorm.DB {
+	db := r.db.WithContext(ctx).Table("users").Joins(join, q.UserID).Where("users.deleted_at IS NULL")
+	if len(q.Exclude) > 0 {
+		db = db.Where("users.id NOT IN ?", q.Exclude)
+	}
+	return db
+}
+
+// edges counts the whole listing and returns the page of it after q.After.
+func (r *GormFollowRepository) edges(base *gorm.DB, q FollowQuery) ([]FollowEdge, int64, error) {
+	var total int64
+	if err := base.Session(&gorm.Session{}).Count(&total).Error; err != nil {
+		return nil, 0, err
+	}
+	page := base.Session(&gorm.Session{})
+	if q.After != nil {
+		page = page.Where("(f.created_at, users.id) < (?, ?)", q.After.At, q.After.ID)
+	}
+	edges := []FollowEdge{}
+	err := page.Select("users.*, f.created_at AS followed_at").
+		Order("f.created_at DESC, users.id DESC").
+		Limit(q.Limit).
+		Scan(&edges).Error
+	return edges, total, err
+}
+
+func (r *GormFollowRepository) Relationships(ctx context.Context, viewerID string, ids []uuid.UUID) (following, followedBy map[uuid.UUID]bool, err error) {
+	following = make(map[uuid.UUID]bool, len(ids))
+	followedBy = make(map[uuid.UUID]bool, len(ids))
+	if len(ids) == 0 {
+		return following, followedBy, nil
+	}
+	var out, in []uuid.UUID
+	if err := r.db.WithContext(ctx).Model(&models.Follow{}).Where("follower_id = ? AND followee_id IN ?", viewerID, ids).Pluck("followee_id", &out).Error; err != nil {
+		return nil, nil, err
+	}
+	if err := r.db.WithContext(ctx).Model(&models.Follow{}).Where("followee_id = ? AND follower_id IN ?", viewerID, ids).Pluck("follower_id", &in).Error; err != nil {
+		return nil, nil, err
+	}
+	for _, id := range out {
+		following[id] = true
+	}
+	for _, id := range in {
+		followedBy[id] = true
+	}
+	return following, followedBy, nil
+}
+
+// FollowingIDs returns the IDs of everyone userID follows.
+func (r *GormFollowRepository) FollowingIDs(ctx context.Context, userID string) ([]uuid.UUID, error) {
+	ids := []uuid.UUID{}
+	err := r.db.WithContext(ctx).Model(&models.Follow{}).Where("follower_id = ?", userID).Pluck("followee_id", &ids).Error
+	return ids, err
+}
+
+func (r *GormFollowRepository) Request(ctx context.Context, requesterID, targetID string) error {
+	req := models.FollowRequest{RequesterID: parseUUID(requesterID), TargetID: parseUUID(targetID)}
+	return r.db.WithContext(ctx).Omit("Requester").Clauses(clause.OnConflict{DoNothing: true}).Create(&req).Error
+}
+
+func (r *GormFollowRepository) HasRequested(ctx context.Context, requesterID, targetID string) (bool, error) {
+	var count int64
+	err := r.db.WithContext(ctx).Model(&models.FollowRequest{}).Where("requester_id = ? AND target_id = ?", requesterID, targetID).Count(&count).Error
+	return count > 0, err
+}
+
+func (r *GormFollowRepository) DeleteRequest(ctx context.Context, requesterID, targetID string) (bool, error) {
+	res := r.db.WithContext(ctx).Where("requester_id = ? AND target_id = ?", requesterID, targetID).Delete(&models.FollowRequest{})
+	return res.RowsAffected > 0, res.Error
+}
+
+func (r *GormFollowRepository) ApproveRequest(ctx context.Context, requesterID, targetID string) error {
+	return r.db.WithContext(ctx).Transaction(func(tx *gorm.DB) error {
+		res := tx.Where("requester_id = ? AND target_id = ?", requesterID, targetID).Delete(&models.FollowRequest{})
+		if res.Error != nil {
+			return res.Error
+		}
+		if res.RowsAffected == 0 {
+			return gorm.ErrRecordNotFound
+		}
+		follow := models.Follow{FollowerID: parseUUID(requesterID), FolloweeID: parseUUID(targetID)}
+		return tx.Clauses(clause.OnConflict{DoNothing: true}).Create(&follow).Error
+	})
 }
 
-func (r *GormFollowRepository) GetFollowing(ctx context.Context, userID string) ([]models.User, error) {
-	var users []models.User
-	err := r.db.WithContext(ctx).Joins("JOIN follows ON follows.followee_id = users.id").Where("follows.follower_id = ?", userID).Find(&users).Error
-	return users, err
+// ListRequests returns pending requests to follow targetID, newest first,
+// leaving out requesters in exclude.
+func (r *GormFollowRepository) ListRequests(ctx context.Context, targetID string, exclude []uuid.UUID) ([]models.FollowRequest, error) {
+	reqs := []models.FollowRequest{}
+	q := r.db.WithContext(ctx).Preload("Requester").Where("target_id = ?", targetID)
+	if len(exclude) > 0 {
+		q = q.Where("requester_id NOT IN ?", exclude)
+	}
+	err := q.Order("created_at DESC").Find(&reqs).Error
+	return reqs, err
 }
 
 func parseUUID(s string) uuid.UUID {
@@ -22,8 +22,9 @@ type MovieRepository interface {
 	// GetByID retrieves a movie by its UUID
 	GetByID(ctx context.Context, id string) (*models.Movie, error)
 
-	// GetByTMDBID retrieves a movie by its TMDB ID
-	GetByTMDBID(ctx context.Context, tmdbID int) (*models.Movie, error)
+	// GetByTMDBID retrieves a movie, or a series for models.MediaTV, by its
+	// TMDB ID
+	GetByTMDBID(ctx context.Context, mediaType string, tmdbID int) (*models.Movie, error)
 
 	// Upsert creates a new movie or updates if TMDB ID already exists
 	Upsert(ctx context.Context, movie *models.Movie) error
@@ -74,10 +75,10 @@ func (r *GormMovieRepository) GetByID(ctx context.Context, id string) (*models.M
 	return &movie, nil
 }
 
-func (r *GormMovieRepository) GetByTMDBID(ctx context.Context, tmdbID int) (*models.Movie, error) {
+func (r *GormMovieRepository) GetByTMDBID(ctx context.Context, mediaType string, tmdbID int) (*models.Movie, error) {
 	var movie models.Movie
 	if err := r.db.WithContext(ctx).
-		Where("tmdb_id = ?", tmdbID).
+		Where("media_type = ? AND tmdb_id = ?", mediaType, tmdbID).
 		First(&movie).Error; err != nil {
 		return nil, err
 	}
@@ -87,7 +88,7 @@ func (r *GormMovieRepository) GetByTMDBID(ctx context.Context, tmdbID int) (*mod
 func (r *GormMovieRepository) Upsert(ctx context.Context, movie *models.Movie) error {
 	return r.db.WithContext(ctx).
 		Clauses(clause.OnConflict{
-			Columns:   []clause.Column{{Name: "tmdb_id"}},
+			Columns:   []clause.Column{{Name: "media_type"}, {Name: "tmdb_id"}},
 			DoUpdates: clause.AssignmentColumns([]string{"title", "year", "poster_url", "backdrop_url", "genres", "runtime", "metadata", "updated_at"}),
 		}).
 		Create(movie).Error
@@ -109,7 +110,7 @@ func (r *GormMovieRepository) Search(ctx context.Context, query string, limit in
 	var movies []models.Movie
 	searchPattern := "%" + query + "%"
 	if err := r.db.WithContext(ctx).
-		Where("title ILIKE ?", searchPattern).
+		Where("title ILIKE ? AND media_type = ?", searchPattern, models.MediaMovie).
 		Order("year DESC").
 		Limit(limit).
 		Find(&movies).Error; err != nil {
@@ -0,0 +1,108 @@
+package repositories
+
+import (
+	"context"
+	"time"
+
+	"github.com/google/uuid"
+	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
+
+	"github.com/Dubjay18/scenee/internal/models"
+)
+
+type PersonRepository interface {
+	GetByTMDBID(ctx context.Context, tmdbID int) (*models.Person, error)
+	// Upsert creates a person or refreshes the one with the same TMDB ID,
+	// setting p.ID either way.
+	Upsert(ctx context.Context, p *models.Person) error
+	Follow(ctx context.Context, userID string, personID uuid.UUID) error
+	Unfollow(ctx context.Context, userID string, personID uuid.UUID) error
+	IsFollowing(ctx context.Context, userID string, personID uuid.UUID) (bool, error)
+	FollowerIDs(ctx context.Context, personID uuid.UUID) ([]uuid.UUID, error)
+	// Followed pages through the people userID follows, latest follow first.
+	Followed(ctx context.Context, userID string, limit, offset int) ([]models.Person, int64, error)
+	// DueForCheck returns up to limit followed people last fetched before
+	// before, least recently fetched first.
+	DueForCheck(ctx context.Context, before time.Time, limit int) ([]models.Person, error)
+}
+
+type GormPersonRepository struct {
+	db *gorm.DB
+}
+
+func NewPersonRepository(db *gorm.DB) *GormPersonRepository {
+	return &GormPersonRepository{db: db}
+}
+
+func (r *GormPersonRepository) GetByTMDBID(ctx context.Context, tmdbID int) (*models.Person, error) {
+	var p models.Person
+	if err := r.db.WithContext(ctx).Where("tmdb_id = ?", tmdbID).First(&p).Error; err != nil {
+		return nil, err
+	}
+	return &p, nil
+}
+
+func (r *GormPersonRepository) Upsert(ctx context.Context, p *models.Person) error {
+	p.UpdatedAt = time.Now()
+	return r.db.WithContext(ctx).
+		Clauses(clause.OnConflict{
+			Columns:   []clause.Column{{Name: "tmdb_id"}},
+			DoUpdates: clause.AssignmentColumns([]string{"name", "biography", "known_for_department", "profile_path", "birthday", "deathday", "place_of_birth", "credits", "fetched_at", "updated_at"}),
+		}).
+		Create(p).Error
+}
+
+func (r *GormPersonRepository) Follow(ctx context.Context, userID string, personID uuid.UUID) error {
+	f := models.PersonFollow{UserID: parseUUID(userID), PersonID: personID, CreatedAt: time.Now()}
+	return r.db.WithContext(ctx).Clauses(clause.OnConflict{DoNothing: true}).Create(&f).Error
+}
+
+func (r *GormPersonRepository) Unfollow(ctx context.Context, userID string, personID uuid.UUID) error {
+	return r.db.WithContext(ctx).
+		Where("user_id = ? AND person_id = ?", userID, personID).
+		Delete(&models.PersonFollow{}).Error
+}
+
+func (r *GormPersonRepository) IsFollowing(ctx context.Context, userID string, personID uuid.UUID) (bool, error) {
+	var count int64
+	err := r.db.WithContext(ctx).Model(&models.PersonFollow{}).
+		Where("user_id = ? AND person_id = ?", userID, personID).
+		Count(&count).Error
+	return count > 0, err
+}
+
+func (r *GormPersonRepository) FollowerIDs(ctx context.Context, personID uuid.UUID) ([]uuid.UUID, error) {
+	var ids []uuid.UUID
+	err := r.db.WithContext(ctx).Model(&models.PersonFollow{}).
+		Where("person_id = ?", personID).
+		Pluck("user_id", &ids).Error
+	return ids, err
+}
+
+func (r *GormPersonRepository) Followed(ctx context.Context, userID string, limit, offset int) ([]models.Person, int64, error) {
+	base := r.db.WithContext(ctx).Model(&models.Person{}).
+		Joins("JOIN person_follows pf ON pf.person_id = people.id").
+		Where("pf.user_id = ?", userID)
+	var total int64
+	if err := base.Session(&gorm.Session{}).Count(&total).Error; err != nil {
+		return nil, 0, err
+	}
+	var people []models.Person
+	err := base.Session(&gorm.Session{}).
+		Order("pf.created_at DESC").
+		Limit(limit).Offset(offset).
+		Find(&people).Error
+	return people, total, err
+}
+
+func (r *GormPersonRepository) DueForCheck(ctx context.Context, before time.Time, limit int) ([]models.Person, error) {
+	var people []models.Person
+	err := r.db.WithContext(ctx).
+		Where("fetched_at < ?", before).
+		Where("EXISTS (SELECT 1 FROM person_follows pf WHERE pf.person_id = people.id)").
+		Order("fetched_at").
+		Limit(limit).
+		Find(&people).Error
+	return people, err
+}
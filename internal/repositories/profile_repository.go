@@ -0,0 +1,84 @@
+package repositories
+
+import (
+	"context"
+	"time"
+
+	"gorm.io/gorm"
+
+	"github.com/Dubjay18/scenee/internal/models"
+)
+
+// ProfileCounts are the public counters shown on a user's profile.
+type ProfileCounts struct {
+	Followers  int64 `json:"followers"`
+	Following  int64 `json:"following"`
+	Watchlists int64 `json:"watchlists"`
+	Reviews    int64 `json:"reviews"`
+}
+
+// WatchStats summarises a user's diary. Films counts distinct movies, Logs
+// every entry including rewatches.
+type WatchStats struct {
+	Films    int64 `json:"films"`
+	Logs     int64 `json:"logs"`
+	ThisYear int64 `json:"this_year"`
+	Minutes  int64 `json:"minutes"`
+}
+
+type ProfileRepository interface {
+	Counts(ctx context.Context, userID string) (*ProfileCounts, error)
+	WatchStats(ctx context.Context, userID string, now time.Time) (*WatchStats, error)
+	// RecentReviews returns the user's latest reviews with their movies.
+	RecentReviews(ctx context.Context, userID string, limit int) ([]models.Review, error)
+}
+
+type GormProfileRepository struct {
+	db *gorm.DB
+}
+
+func NewProfileRepository(db *gorm.DB) *GormProfileRepository {
+	return &GormProfileRepository{db: db}
+}
+
+func (r *GormProfileRepository) Counts(ctx context.Context, userID string) (*ProfileCounts, error) {
+	var c ProfileCounts
+	err := r.db.WithContext(ctx).Raw(`
+		SELECT
+			(SELECT count(*) FROM follows WHERE followee_id = @id) AS followers,
+			(SELECT count(*) FROM follows WHERE follower_id = @id) AS following,
+			(SELECT count(*) FROM watchlists WHERE owner_id = @id AND visibility = @public AND deleted_at IS NULL) AS watchlists,
+			(SELECT count(*) FROM reviews WHERE user_id = @id AND deleted_at IS NULL) AS reviews`,
+		map[string]any{"id": userID, "public": models.PublicVisibility}).
+		Scan(&c).Error
+	if err != nil {
+		return nil, err
+	}
+	return &c, nil
+}
+
+func (r *GormProfileRepository) WatchStats(ctx context.Context, userID string, now time.Time) (*WatchStats, error) {
+	var s WatchStats
+	yearStart := time.Date(now.Year(), 1, 1, 0, 0, 0, 0, time.UTC)
+	err := r.db.WithContext(ctx).Model(&models.WatchLog{}).
+		Select(`count(DISTINCT watch_logs.movie_id) AS films,
+			count(*) AS logs,
+			count(*) FILTER (WHERE watch_logs.watched_on >= ?) AS this_year,
+			coalesce(sum(movies.runtime), 0) AS minutes`, yearStart).
+		Joins("LEFT JOIN movies ON movies.id = watch_logs.movie_id").
+		Where("watch_logs.user_id = ?", userID).
+		Scan(&s).Error
+	if err != nil {
+		return nil, err
+	}
+	return &s, nil
+}
+
+func (r *GormProfileRepository) RecentReviews(ctx context.Context, userID string, limit int) ([]models.Review, error) {
+	reviews := []models.Review{}
+	err := r.db.WithContext(ctx).Preload("Movie").
+		Where("user_id = ?", userID).
+		Order("created_at DESC").Limit(limit).
+		Find(&reviews).Error
+	return reviews, err
+}
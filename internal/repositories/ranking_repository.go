@@ -0,0 +1,119 @@
+package repositories
+
+import (
+	"context"
+	"fmt"
+	"math"
+	"time"
+
+	"gorm.io/gorm"
+
+	"github.com/Dubjay18/scenee/internal/models"
+)
+
+// RankingParams configures one trending window. Each like, save, view and
+// fork within Lookback contributes its weight decayed by exp(-λ·age), with λ
+// derived from HalfLife.
+type RankingParams struct {
+	Window   string
+	HalfLife time.Duration
+	Lookback time.Duration
+
+	LikeWeight float64
+	SaveWeight float64
+	ViewWeight float64
+	ForkWeight float64
+
+	// PerScope caps how many lists are kept per dimension/value.
+	PerScope int
+}
+
+type RankingRepository interface {
+	// Recompute replaces the rankings for p.Window.
+	Recompute(ctx context.Context, p RankingParams) error
+	// Top returns ranked watchlists for a window; dimension "all" ignores value.
+	Top(ctx context.Context, window, dimension, value string, limit int) ([]models.Watchlist, error)
+}
+
+type GormRankingRepository struct {
+	db *gorm.DB
+}
+
+func NewRankingRepository(db *gorm.DB) *GormRankingRepository {
+	return &GormRankingRepository{db: db}
+}
+
+// A list belongs to a genre when at least a third of its items carry it.
+const rankingInsert = `
+INSERT INTO watchlist_rankings (time_window, dimension, value, watchlist_id, score, rank, computed_at)
+SELECT ?, dimension, value, watchlist_id, score, rnk, now()
+FROM (
+    SELECT dimension, value, watchlist_id, score,
+           row_number() OVER (PARTITION BY dimension, value ORDER BY score DESC, watchlist_id) AS rnk
+    FROM (
+        SELECT 'all' AS dimension, '' AS value, watchlist_id, score FROM ranking_scores
+        UNION ALL
+        SELECT 'genre', lg.genre, rs.watchlist_id, rs.score
+        FROM ranking_scores rs
+        JOIN (
+            SELECT wi.watchlist_id, lower(g) AS genre
+            FROM watchlist_items wi
+            JOIN movies m ON m.id = wi.movie_id
+            CROSS JOIN LATERAL jsonb_array_elements_text(COALESCE(m.genres, '[]'::jsonb)) g
+            WHERE wi.watchlist_id IN (SELECT watchlist_id FROM ranking_scores)
+            GROUP BY wi.watchlist_id, lower(g)
+            HAVING COUNT(*) * 3 >= (SELECT COUNT(*) FROM watchlist_items x WHERE x.watchlist_id = wi.watchlist_id)
+        ) lg ON lg.watchlist_id = rs.watchlist_id
+        UNION ALL
+        SELECT 'tag', tags.t, rs.watchlist_id, rs.score
+        FROM ranking_scores rs
+        JOIN watchlists w ON w.id = rs.watchlist_id
+        CROSS JOIN LATERAL (SELECT DISTINCT lower(x) AS t FROM jsonb_array_elements_text(COALESCE(w.tags, '[]'::jsonb)) x) tags
+    ) scoped
+) ranked
+WHERE rnk <= ?`
+
+func (r *GormRankingRepository) Recompute(ctx context.Context, p RankingParams) error {
+	lambda := math.Ln2 / p.HalfLife.Seconds()
+	since := time.Now().Add(-p.Lookback)
+	decay := fmt.Sprintf("exp(-%g * extract(epoch FROM now() - created_at))", lambda)
+	scores := `
+CREATE TEMP TABLE ranking_scores ON COMMIT DROP AS
+SELECT e.watchlist_id, SUM(e.s) AS score
+FROM (
+    SELECT watchlist_id, ? * ` + decay + ` AS s FROM likes WHERE created_at > ?
+    UNION ALL
+    SELECT watchlist_id, ? * ` + decay + ` FROM saves WHERE created_at > ?
+    UNION ALL
+    SELECT watchlist_id, ? * ` + decay + ` FROM watchlist_views WHERE created_at > ?
+    UNION ALL
+    SELECT forked_from_id, ? * ` + decay + ` FROM watchlists WHERE forked_from_id IS NOT NULL AND deleted_at IS NULL AND created_at > ?
+) e
+JOIN watchlists w ON w.id = e.watchlist_id AND w.deleted_at IS NULL AND w.visibility = ?
+GROUP BY e.watchlist_id`
+
+	return r.db.WithContext(ctx).Transaction(func(tx *gorm.DB) error {
+		if err := tx.Exec(scores,
+			p.LikeWeight, since, p.SaveWeight, since, p.ViewWeight, since, p.ForkWeight, since,
+			models.PublicVisibility).Error; err != nil {
+			return err
+		}
+		if err := tx.Where("time_window = ?", p.Window).Delete(&models.WatchlistRanking{}).Error; err != nil {
+			return err
+		}
+		return tx.Exec(rankingInsert, p.Window, p.PerScope).Error
+	})
+}
+
+func (r *GormRankingRepository) Top(ctx context.Context, window, dimension, value string, limit int) ([]models.Watchlist, error) {
+	var out []models.Watchlist
+	err := r.db.WithContext(ctx).Preload("Owner").
+		Joins("JOIN watchlist_rankings wr ON wr.watchlist_id = watchlists.id").
+		Where("wr.time_window = ? AND wr.dimension = ? AND wr.value = ?", window, dimension, value).
+		Where("watchlists.visibility = ?", models.PublicVisibility).
+		Where(publicOwner).
+		Order("wr.rank ASC").
+		Limit(limit).
+		Find(&out).Error
+	return out, err
+}
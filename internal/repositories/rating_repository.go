@@ -0,0 +1,74 @@
+package repositories
+
+import (
+	"context"
+	"encoding/json"
+
+	"github.com/google/uuid"
+	"gorm.io/gorm"
+
+	"github.com/Dubjay18/scenee/internal/models"
+)
+
+// RatedMovie is a movie with its community rating and Bayesian score.
+type RatedMovie struct {
+	models.Movie
+	RatingCount int
+	RatingSum   int64
+	Histogram   []int `gorm:"serializer:json"`
+	Score       float64
+}
+
+type RatingRepository interface {
+	Get(ctx context.Context, movieID uuid.UUID) (*models.MovieRatingStats, error)
+	// GlobalMean is the average of every rating on Scenee.
+	GlobalMean(ctx context.Context) (float64, error)
+	// TopRated ranks movies with at least minVotes ratings by the weighted
+	// rating (v·R + m·C) / (v + m), with m = minVotes and C = mean.
+	TopRated(ctx context.Context, minVotes int, mean float64, genre string, limit, offset int) ([]RatedMovie, int64, error)
+}
+
+type GormRatingRepository struct {
+	db *gorm.DB
+}
+
+func NewRatingRepository(db *gorm.DB) *GormRatingRepository {
+	return &GormRatingRepository{db: db}
+}
+
+func (r *GormRatingRepository) Get(ctx context.Context, movieID uuid.UUID) (*models.MovieRatingStats, error) {
+	var stats models.MovieRatingStats
+	if err := r.db.WithContext(ctx).First(&stats, "movie_id = ?", movieID).Error; err != nil {
+		return nil, err
+	}
+	return &stats, nil
+}
+
+func (r *GormRatingRepository) GlobalMean(ctx context.Context) (float64, error) {
+	var mean float64
+	err := r.db.WithContext(ctx).Model(&models.MovieRatingStats{}).
+		Select("COALESCE(sum(rating_sum)::float8 / NULLIF(sum(rating_count), 0), 0)").
+		Scan(&mean).Error
+	return mean, err
+}
+
+func (r *GormRatingRepository) TopRated(ctx context.Context, minVotes int, mean float64, genre string, limit, offset int) ([]RatedMovie, int64, error) {
+	q := r.db.WithContext(ctx).Model(&models.Movie{}).
+		Joins("JOIN movie_rating_stats s ON s.movie_id = movies.id").
+		Where("s.rating_count >= ?", minVotes)
+	if genre != "" {
+		g, _ := json.Marshal([]string{genre})
+		q = q.Where("movies.genres @> ?::jsonb", string(g))
+	}
+	var total int64
+	if err := q.Count(&total).Error; err != nil {
+		return nil, 0, err
+	}
+	var out []RatedMovie
+	err := q.Select(`movies.*, s.rating_count, s.rating_sum, s.histogram,
+			(s.rating_sum + ? * ?) / (s.rating_count + ?)::float8 AS score`, minVotes, mean, minVotes).
+		Order("score DESC, s.rating_count DESC, movies.id").
+		Limit(limit).Offset(offset).
+		Scan(&out).Error
+	return out, total, err
+}
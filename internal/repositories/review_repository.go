@@ -2,18 +2,52 @@ package repositories
 
 import (
 	"context"
+	"errors"
+	"fmt"
 
+	"github.com/google/uuid"
 	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
 
 	"github.com/Dubjay18/scenee/internal/models"
 )
 
+// ReviewFilter narrows and orders a movie's reviews. A non-nil AuthorIDs
+// restricts the listing to those authors (possibly none); ExcludeAuthorIDs
+// drops reviews by those authors.
+type ReviewFilter struct {
+	Sort             string
+	AuthorIDs        []uuid.UUID
+	ExcludeAuthorIDs []uuid.UUID
+	Limit            int
+	Offset           int
+}
+
+var reviewSorts = map[string]string{
+	"recent":  "reviews.created_at DESC",
+	"popular": "reviews.like_count DESC, reviews.reply_count DESC, reviews.created_at DESC",
+	"rating":  "reviews.rating DESC, reviews.like_count DESC, reviews.created_at DESC",
+}
+
 type ReviewRepository interface {
+	// Create returns gorm.ErrDuplicatedKey when the author has already
+	// reviewed the movie.
 	Create(ctx context.Context, review *models.Review) error
-	GetByMovieID(ctx context.Context, movieID string) ([]models.Review, error)
+	ListByMovie(ctx context.Context, movieID string, f ReviewFilter) ([]models.Review, int64, error)
+	GetByID(ctx context.Context, movieID, id string) (*models.Review, error)
 	GetByUserAndMovie(ctx context.Context, userID, movieID string) (*models.Review, error)
 	Update(ctx context.Context, review *models.Review) error
 	Delete(ctx context.Context, id, userID string) error
+
+	// Like reports whether the like is new; like_count moves with it.
+	Like(ctx context.Context, userID string, reviewID uuid.UUID) (bool, error)
+	Unlike(ctx context.Context, userID string, reviewID uuid.UUID) error
+	LikedBy(ctx context.Context, userID string, reviewIDs []uuid.UUID) (map[uuid.UUID]bool, error)
+
+	CreateReply(ctx context.Context, reply *models.ReviewReply) error
+	GetReply(ctx context.Context, reviewID, id string) (*models.ReviewReply, error)
+	ListReplies(ctx context.Context, reviewID string, exclude []uuid.UUID, limit, offset int) ([]models.ReviewReply, int64, error)
+	DeleteReply(ctx context.Context, reply *models.ReviewReply) error
 }
 
 type GormReviewRepository struct {
@@ -24,14 +58,55 @@ func NewReviewRepository(db *gorm.DB) *GormReviewRepository {
 	return &GormReviewRepository{db: db}
 }
 
+// Create stores the review and counts its rating in the movie's stats.
 func (r *GormReviewRepository) Create(ctx context.Context, review *models.Review) error {
-	return r.db.WithContext(ctx).Create(review).Error
+	return r.db.WithContext(ctx).Transaction(func(tx *gorm.DB) error {
+		res := tx.Omit("User").Clauses(clause.OnConflict{
+			Columns:     []clause.Column{{Name: "user_id"}, {Name: "movie_id"}},
+			TargetWhere: clause.Where{Exprs: []clause.Expression{clause.Expr{SQL: "deleted_at IS NULL"}}},
+			DoNothing:   true,
+		}).Create(review)
+		if res.Error != nil {
+			return res.Error
+		}
+		if res.RowsAffected == 0 {
+			return gorm.ErrDuplicatedKey
+		}
+		return adjustRatingStats(tx, review.MovieID, review.Rating, 1)
+	})
 }
 
-func (r *GormReviewRepository) GetByMovieID(ctx context.Context, movieID string) ([]models.Review, error) {
+// ListByMovie pages through a movie's reviews in the filter's order.
+func (r *GormReviewRepository) ListByMovie(ctx context.Context, movieID string, f ReviewFilter) ([]models.Review, int64, error) {
+	q := r.db.WithContext(ctx).Model(&models.Review{}).Where("reviews.movie_id = ?", movieID)
+	if f.AuthorIDs != nil {
+		if len(f.AuthorIDs) == 0 {
+			return []models.Review{}, 0, nil
+		}
+		q = q.Where("reviews.user_id IN ?", f.AuthorIDs)
+	}
+	if len(f.ExcludeAuthorIDs) > 0 {
+		q = q.Where("reviews.user_id NOT IN ?", f.ExcludeAuthorIDs)
+	}
+	var total int64
+	if err := q.Count(&total).Error; err != nil {
+		return nil, 0, err
+	}
+	order, ok := reviewSorts[f.Sort]
+	if !ok {
+		order = reviewSorts["recent"]
+	}
 	var reviews []models.Review
-	err := r.db.WithContext(ctx).Preload("User").Where("movie_id = ?", movieID).Order("created_at DESC").Find(&reviews).Error
-	return reviews, err
+	err := q.Preload("User").Order(order).Limit(f.Limit).Offset(f.Offset).Find(&reviews).Error
+	return reviews, total, err
+}
+
+func (r *GormReviewRepository) GetByID(ctx context.Context, movieID, id string) (*models.Review, error) {
+	var review models.Review
+	if err := r.db.WithContext(ctx).Where("id = ? AND movie_id = ?", id, movieID).First(&review).Error; err != nil {
+		return nil, err
+	}
+	return &review, nil
 }
 
 func (r *GormReviewRepository) GetByUserAndMovie(ctx context.Context, userID, movieID string) (*models.Review, error) {
@@ -43,10 +118,146 @@ func (r *GormReviewRepository) GetByUserAndMovie(ctx context.Context, userID, mo
 	return &review, nil
 }
 
+// Update writes the author-editable fields only, so concurrent likes and
+// replies keep their counters. A changed rating moves between histogram
+// buckets.
 func (r *GormReviewRepository) Update(ctx context.Context, review *models.Review) error {
-	return r.db.WithContext(ctx).Save(review).Error
+	return r.db.WithContext(ctx).Transaction(func(tx *gorm.DB) error {
+		var current models.Review
+		if err := tx.Clauses(clause.Locking{Strength: "UPDATE"}).Select("id", "movie_id", "rating").First(&current, "id = ?", review.ID).Error; err != nil {
+			return err
+		}
+		if err := tx.Model(review).Select("rating", "review", "spoiler", "content_warnings", "updated_at").Updates(review).Error; err != nil {
+			return err
+		}
+		if current.Rating == review.Rating {
+			return nil
+		}
+		if err := adjustRatingStats(tx, current.MovieID, current.Rating, -1); err != nil {
+			return err
+		}
+		return adjustRatingStats(tx, current.MovieID, review.Rating, 1)
+	})
 }
 
 func (r *GormReviewRepository) Delete(ctx context.Context, id, userID string) error {
-	return r.db.WithContext(ctx).Where("id = ? AND user_id = ?", id, userID).Delete(&models.Review{}).Error
+	return r.db.WithContext(ctx).Transaction(func(tx *gorm.DB) error {
+		var current models.Review
+		err := tx.Clauses(clause.Locking{Strength: "UPDATE"}).Select("id", "movie_id", "rating").
+			Where("id = ? AND user_id = ?", id, userID).Take(&current).Error
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return nil
+		}
+		if err != nil {
+			return err
+		}
+		if err := tx.Delete(&models.Review{}, "id = ?", current.ID).Error; err != nil {
+			return err
+		}
+		return adjustRatingStats(tx, current.MovieID, current.Rating, -1)
+	})
+}
+
+// adjustRatingStats adds delta ratings of the given value to a movie's
+// aggregate, creating the row on first use.
+func adjustRatingStats(tx *gorm.DB, movieID uuid.UUID, rating, delta int) error {
+	if rating < 1 || rating > 10 {
+		return fmt.Errorf("rating %d out of range", rating)
+	}
+	if err := tx.Clauses(clause.OnConflict{DoNothing: true}).
+		Create(&models.MovieRatingStats{MovieID: movieID, Histogram: make([]int, 10)}).Error; err != nil {
+		return err
+	}
+	return tx.Exec(`
+		UPDATE movie_rating_stats SET
+			rating_count = GREATEST(rating_count + ?, 0),
+			rating_sum = GREATEST(rating_sum + ?, 0),
+			histogram = jsonb_set(histogram, ARRAY[?::text], to_jsonb(GREATEST((histogram->>(?::int))::int + ?, 0))),
+			updated_at = now()
+		WHERE movie_id = ?`,
+		delta, delta*rating, rating-1, rating-1, delta, movieID).Error
+}
+
+func (r *GormReviewRepository) Like(ctx context.Context, userID string, reviewID uuid.UUID) (bool, error) {
+	created := false
+	err := r.db.WithContext(ctx).Transaction(func(tx *gorm.DB) error {
+		res := tx.Clauses(clause.OnConflict{DoNothing: true}).Create(&models.ReviewLike{UserID: parseUUID(userID), ReviewID: reviewID})
+		if res.Error != nil {
+			return res.Error
+		}
+		if res.RowsAffected == 0 {
+			return nil
+		}
+		created = true
+		return tx.Model(&models.Review{}).Where("id = ?", reviewID).UpdateColumn("like_count", gorm.Expr("like_count + 1")).Error
+	})
+	return created, err
+}
+
+func (r *GormReviewRepository) Unlike(ctx context.Context, userID string, reviewID uuid.UUID) error {
+	return r.db.WithContext(ctx).Transaction(func(tx *gorm.DB) error {
+		res := tx.Where("user_id = ? AND review_id = ?", userID, reviewID).Delete(&models.ReviewLike{})
+		if res.Error != nil || res.RowsAffected == 0 {
+			return res.Error
+		}
+		return tx.Model(&models.Review{}).Where("id = ?", reviewID).UpdateColumn("like_count", gorm.Expr("GREATEST(like_count - 1, 0)")).Error
+	})
+}
+
+func (r *GormReviewRepository) LikedBy(ctx context.Context, userID string, reviewIDs []uuid.UUID) (map[uuid.UUID]bool, error) {
+	out := make(map[uuid.UUID]bool, len(reviewIDs))
+	if len(reviewIDs) == 0 {
+		return out, nil
+	}
+	var ids []uuid.UUID
+	err := r.db.WithContext(ctx).Model(&models.ReviewLike{}).
+		Where("user_id = ? AND review_id IN ?", userID, reviewIDs).
+		Pluck("review_id", &ids).Error
+	for _, id := range ids {
+		out[id] = true
+	}
+	return out, err
+}
+
+func (r *GormReviewRepository) CreateReply(ctx context.Context, reply *models.ReviewReply) error {
+	return r.db.WithContext(ctx).Transaction(func(tx *gorm.DB) error {
+		if err := tx.Omit("User").Create(reply).Error; err != nil {
+			return err
+		}
+		return tx.Model(&models.Review{}).Where("id = ?", reply.ReviewID).UpdateColumn("reply_count", gorm.Expr("reply_count + 1")).Error
+	})
+}
+
+func (r *GormReviewRepository) GetReply(ctx context.Context, reviewID, id string) (*models.ReviewReply, error) {
+	var reply models.ReviewReply
+	if err := r.db.WithContext(ctx).Preload("User").Where("id = ? AND review_id = ?", id, reviewID).First(&reply).Error; err != nil {
+		return nil, err
+	}
+	return &reply, nil
+}
+
+// ListReplies pages through a review's thread, oldest first, skipping
+// replies by the excluded users.
+func (r *GormReviewRepository) ListReplies(ctx context.Context, reviewID string, exclude []uuid.UUID, limit, offset int) ([]models.ReviewReply, int64, error) {
+	q := r.db.WithContext(ctx).Model(&models.ReviewReply{}).Where("review_id = ?", reviewID)
+	if len(exclude) > 0 {
+		q = q.Where("user_id NOT IN ?", exclude)
+	}
+	var total int64
+	if err := q.Count(&total).Error; err != nil {
+		return nil, 0, err
+	}
+	var replies []models.ReviewReply
+	err := q.Preload("User").Order("created_at ASC, id ASC").Limit(limit).Offset(offset).Find(&replies).Error
+	return replies, total, err
+}
+
+func (r *GormReviewRepository) DeleteReply(ctx context.Context, reply *models.ReviewReply) error {
+	return r.db.WithContext(ctx).Transaction(func(tx *gorm.DB) error {
+		res := tx.Delete(&models.ReviewReply{}, "id = ?", reply.ID)
+		if res.Error != nil || res.RowsAffected == 0 {
+			return res.Error
+		}
+		return tx.Model(&models.Review{}).Where("id = ?", reply.ReviewID).UpdateColumn("reply_count", gorm.Expr("GREATEST(reply_count - 1, 0)")).Error
+	})
 }
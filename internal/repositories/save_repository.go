@@ -0,0 +1,177 @@
+package repositories
+
+import (
+	"context"
+	"time"
+
+	"github.com/google/uuid"
+	"gorm.io/gorm"
+
+	"github.com/Dubjay18/scenee/internal/models"
+)
+
+// SavedWatchlist is a row of a user's library: the watchlist plus when and
+// where it was saved.
+type SavedWatchlist struct {
+	models.Watchlist
+	SavedAt      time.Time  `json:"saved_at"`
+	CollectionID *uuid.UUID `json:"collection_id"`
+}
+
+// SavedFilter narrows a library listing. Unfiled selects saves outside any
+// collection and takes precedence over CollectionID.
+type SavedFilter struct {
+	CollectionID *uuid.UUID
+	Unfiled      bool
+	Sort         string
+	Limit        int
+	Offset       int
+}
+
+var savedSorts = map[string]string{
+	"saved_desc": "saves.created_at DESC",
+	"saved_asc":  "saves.created_at ASC",
+	"title":      "lower(watchlists.title) ASC, saves.created_at DESC",
+	"updated":    "watchlists.updated_at DESC",
+	"popular":    "watchlists.save_count DESC, saves.created_at DESC",
+}
+
+type SaveRepository interface {
+	ListSaved(ctx context.Context, userID string, f SavedFilter) ([]SavedWatchlist, int64, error)
+	SetCollection(ctx context.Context, userID, watchlistID string, collectionID *uuid.UUID) error
+
+	ListCollections(ctx context.Context, userID string) ([]models.SaveCollection, error)
+	GetCollection(ctx context.Context, userID, id string) (*models.SaveCollection, error)
+	CreateCollection(ctx context.Context, c *models.SaveCollection) error
+	RenameCollection(ctx context.Context, userID, id, name string) error
+	DeleteCollection(ctx context.Context, userID, id string) error
+}
+
+type GormSaveRepository struct {
+	db *gorm.DB
+}
+
+func NewSaveRepository(db *gorm.DB) *GormSaveRepository {
+	return &GormSaveRepository{db: db}
+}
+
+// ListSaved pages through the watchlists userID has saved. Lists that were
+// deleted or that userID can no longer see drop out.
+func (r *GormSaveRepository) ListSaved(ctx context.Context, userID string, f SavedFilter) ([]SavedWatchlist, int64, error) {
+	q := r.db.WithContext(ctx).Model(&models.Save{}).
+		Joins("JOIN watchlists ON watchlists.id = saves.watchlist_id AND watchlists.deleted_at IS NULL").
+		Where("saves.user_id = ?", userID).
+		Where(listVisibleTo, userID, userID, userID, userID)
+	switch {
+	case f.Unfiled:
+		q = q.Where("saves.collection_id IS NULL")
+	case f.CollectionID != nil:
+		q = q.Where("saves.collection_id = ?", *f.CollectionID)
+	}
+	var total int64
+	if err := q.Count(&total).Error; err != nil {
+		return nil, 0, err
+	}
+	order, ok := savedSorts[f.Sort]
+	if !ok {
+		order = savedSorts["saved_desc"]
+	}
+	var rows []struct {
+		WatchlistID  uuid.UUID
+		SavedAt      time.Time
+		CollectionID *uuid.UUID
+	}
+	if err := q.Select("saves.watchlist_id, saves.created_at AS saved_at, saves.collection_id").
+		Order(order).Limit(f.Limit).Offset(f.Offset).Scan(&rows).Error; err != nil {
+		return nil, 0, err
+	}
+	if len(rows) == 0 {
+		return []SavedWatchlist{}, total, nil
+	}
+	ids := make([]uuid.UUID, 0, len(rows))
+	for _, row := range rows {
+		ids = append(ids, row.WatchlistID)
+	}
+	var lists []models.Watchlist
+	if err := r.db.WithContext(ctx).Preload("Owner").Where("id IN ?", ids).Find(&lists).Error; err != nil {
+		return nil, 0, err
+	}
+	byID := make(map[uuid.UUID]models.Watchlist, len(lists))
+	for _, wl := range lists {
+		byID[wl.ID] = wl
+	}
+	out := make([]SavedWatchlist, 0, len(rows))
+	for _, row := range rows {
+		wl, ok := byID[row.WatchlistID]
+		if !ok {
+			continue
+		}
+		out = append(out, SavedWatchlist{Watchlist: wl, SavedAt: row.SavedAt, CollectionID: row.CollectionID})
+	}
+	return out, total, nil
+}
+
+// SetCollection files a saved watchlist into collectionID, or takes it out of
+// any collection when nil.
+func (r *GormSaveRepository) SetCollection(ctx context.Context, userID, watchlistID string, collectionID *uuid.UUID) error {
+	res := r.db.WithContext(ctx).Model(&models.Save{}).
+		Where("user_id = ? AND watchlist_id = ?", userID, watchlistID).
+		Update("collection_id", collectionID)
+	if res.Error != nil {
+		return res.Error
+	}
+	if res.RowsAffected == 0 {
+		return gorm.ErrRecordNotFound
+	}
+	return nil
+}
+
+func (r *GormSaveRepository) ListCollections(ctx context.Context, userID string) ([]models.SaveCollection, error) {
+	var out []models.SaveCollection
+	err := r.db.WithContext(ctx).Model(&models.SaveCollection{}).
+		Select("save_collections.*, (SELECT COUNT(*) FROM saves s WHERE s.collection_id = save_collections.id) AS save_count").
+		Where("user_id = ?", userID).
+		Order("lower(name) ASC").
+		Find(&out).Error
+	return out, err
+}
+
+func (r *GormSaveRepository) GetCollection(ctx context.Context, userID, id string) (*models.SaveCollection, error) {
+	var c models.SaveCollection
+	if err := r.db.WithContext(ctx).Where("id = ? AND user_id = ?", id, userID).First(&c).Error; err != nil {
+		return nil, err
+	}
+	return &c, nil
+}
+
+func (r *GormSaveRepository) CreateCollection(ctx context.Context, c *models.SaveCollection) error {
+	return r.db.WithContext(ctx).Create(c).Error
+}
+
+func (r *GormSaveRepository) RenameCollection(ctx context.Context, userID, id, name string) error {
+	res := r.db.WithContext(ctx).Model(&models.SaveCollection{}).Where("id = ? AND user_id = ?", id, userID).Update("name", name)
+	if res.Error != nil {
+		return res.Error
+	}
+	if res.RowsAffected == 0 {
+		return gorm.ErrRecordNotFound
+	}
+	return nil
+}
+
+// DeleteCollection removes a collection; its saves stay in the library unfiled.
+func (r *GormSaveRepository) DeleteCollection(ctx context.Context, userID, id string) error {
+	return r.db.WithContext(ctx).Transaction(func(tx *gorm.DB) error {
+		if err := tx.Model(&models.Save{}).Where("user_id = ? AND collection_id = ?", userID, id).Update("collection_id", nil).Error; err != nil {
+			return err
+		}
+		res := tx.Where("id = ? AND user_id = ?", id, userID).Delete(&models.SaveCollection{})
+		if res.Error != nil {
+			return res.Error
+		}
+		if res.RowsAffected == 0 {
+			return gorm.ErrRecordNotFound
+		}
+		return nil
+	})
+}
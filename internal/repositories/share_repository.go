@@ -0,0 +1,71 @@
+package repositories
+
+import (
+	"context"
+
+	"github.com/google/uuid"
+	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
+
+	"github.com/Dubjay18/scenee/internal/models"
+)
+
+type ShareRepository interface {
+	// Create skips shares of a list the sender already sent its recipient.
+	Create(ctx context.Context, shares []models.Share) error
+	// SentTo returns which of to fromID has already sent the watchlist.
+	SentTo(ctx context.Context, fromID, watchlistID string, to []uuid.UUID) ([]uuid.UUID, error)
+	// ListReceived pages through shares sent to userID, newest first, with
+	// the sender and the watchlist and its owner loaded. Shares from the
+	// excluded users, of deleted lists and of lists userID can no longer
+	// see are left out.
+	ListReceived(ctx context.Context, userID string, exclude []uuid.UUID, limit, offset int) ([]models.Share, int64, error)
+}
+
+type GormShareRepository struct {
+	db *gorm.DB
+}
+
+func NewShareRepository(db *gorm.DB) *GormShareRepository {
+	return &GormShareRepository{db: db}
+}
+
+func (r *GormShareRepository) Create(ctx context.Context, shares []models.Share) error {
+	if len(shares) == 0 {
+		return nil
+	}
+	return r.db.WithContext(ctx).Omit(clause.Associations).
+		Clauses(clause.OnConflict{Columns: []clause.Column{{Name: "from_user_id"}, {Name: "to_user_id"}, {Name: "watchlist_id"}}, DoNothing: true}).
+		Create(&shares).Error
+}
+
+func (r *GormShareRepository) SentTo(ctx context.Context, fromID, watchlistID string, to []uuid.UUID) ([]uuid.UUID, error) {
+	ids := []uuid.UUID{}
+	if len(to) == 0 {
+		return ids, nil
+	}
+	err := r.db.WithContext(ctx).Model(&models.Share{}).
+		Where("from_user_id = ? AND watchlist_id = ? AND to_user_id IN ?", fromID, watchlistID, to).
+		Pluck("to_user_id", &ids).Error
+	return ids, err
+}
+
+func (r *GormShareRepository) ListReceived(ctx context.Context, userID string, exclude []uuid.UUID, limit, offset int) ([]models.Share, int64, error) {
+	q := r.db.WithContext(ctx).Model(&models.Share{}).
+		Joins("JOIN watchlists ON watchlists.id = shares.watchlist_id AND watchlists.deleted_at IS NULL").
+		Where("shares.to_user_id = ?", userID).
+		Where(listVisibleTo, userID, userID, userID, userID)
+	if len(exclude) > 0 {
+		q = q.Where("shares.from_user_id NOT IN ?", exclude)
+	}
+	var total int64
+	if err := q.Session(&gorm.Session{}).Count(&total).Error; err != nil {
+		return nil, 0, err
+	}
+	shares := []models.Share{}
+	err := q.Preload("From").Preload("Watchlist.Owner").
+		Order("shares.created_at DESC, shares.id").
+		Limit(limit).Offset(offset).
+		Find(&shares).Error
+	return shares, total, err
+}
@@ -0,0 +1,218 @@
+package repositories
+
+import (
+	"context"
+	"time"
+
+	"github.com/google/uuid"
+	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
+
+	"github.com/Dubjay18/scenee/internal/models"
+)
+
+// SuggestionQuery asks for candidates to suggest to UserID. Candidates are
+// never UserID, someone they follow or asked to follow, someone they
+// dismissed or one of Exclude.
+type SuggestionQuery struct {
+	UserID  string
+	Exclude []uuid.UUID
+	Limit   int
+}
+
+// MutualCandidate is followed by Mutuals of the people UserID follows.
+type MutualCandidate struct {
+	UserID  uuid.UUID
+	Mutuals int
+}
+
+// OverlapCandidate has SharedFilms in common with UserID across watchlists;
+// Jaccard is that over the size of both sets of titles combined. Titles are
+// compared by movie row, so a film and a series sharing a TMDB ID differ.
+type OverlapCandidate struct {
+	UserID      uuid.UUID
+	SharedFilms int
+	Jaccard     float64
+}
+
+// TasteCandidate rated CoRated of the movies UserID rated. Agreement runs
+// from 0 (opposite ends of the scale) to 1 (identical ratings);
+// LovedDirector is the director whose films they most often both rated 8
+// or more, if any.
+type TasteCandidate struct {
+	UserID        uuid.UUID
+	CoRated       int
+	Agreement     float64
+	LovedDirector string
+}
+
+// PopularCandidate is a public account with Followers followers that has
+// recently updated a public list.
+type PopularCandidate struct {
+	UserID    uuid.UUID
+	Followers int
+}
+
+type SuggestionRepository interface {
+	FriendsOfFriends(ctx context.Context, q SuggestionQuery) ([]MutualCandidate, error)
+	// WatchlistOverlap compares UserID's lists, up to their overlapSample
+	// most recently added titles, with other users' public lists, requiring
+	// at least minShared films in common.
+	WatchlistOverlap(ctx context.Context, q SuggestionQuery, minShared int) ([]OverlapCandidate, error)
+	// TasteMatches compares review ratings, requiring at least minCoRated
+	// movies rated by both.
+	TasteMatches(ctx context.Context, q SuggestionQuery, minCoRated int) ([]TasteCandidate, error)
+	// Popular finds active public accounts with the most followers, for
+	// users with too little activity for the other signals.
+	Popular(ctx context.Context, q SuggestionQuery, activeSince time.Time) ([]PopularCandidate, error)
+	Dismiss(ctx context.Context, userID, dismissedID string) error
+}
+
+type GormSuggestionRepository struct {
+	db *gorm.DB
+}
+
+func NewSuggestionRepository(db *gorm.DB) *GormSuggestionRepository {
+	return &GormSuggestionRepository{db: db}
+}
+
+// overlapSample caps how many of UserID's titles WatchlistOverlap compares,
+// so a huge library doesn't pull in most of everyone's public items.
+const overlapSample = 500
+
+// suggestable filters candidates c for @me. Private accounts are only found
+// through the follow graph, never through what they watch or rate.
+const suggestable = `
+    c.deleted_at IS NULL
+    AND c.id <> @me
+    AND c.id NOT IN @exclude
+    AND NOT EXISTS (SELECT 1 FROM follows x WHERE x.follower_id = @me AND x.followee_id = c.id)
+    AND NOT EXISTS (SELECT 1 FROM follow_requests x WHERE x.requester_id = @me AND x.target_id = c.id)
+    AND NOT EXISTS (SELECT 1 FROM suggestion_dismissals x WHERE x.user_id = @me AND x.dismissed_id = c.id)`
+
+// args binds the named parameters shared by the suggestion queries. The nil
+// UUID keeps NOT IN valid when nothing is excluded.
+func (q SuggestionQuery) args(extra map[string]any) map[string]any {
+	args := map[string]any{
+		"me":      q.UserID,
+		"exclude": append([]uuid.UUID{uuid.Nil}, q.Exclude...),
+		"limit":   q.Limit,
+	}
+	for k, v := range extra {
+		args[k] = v
+	}
+	return args
+}
+
+func (r *GormSuggestionRepository) FriendsOfFriends(ctx context.Context, q SuggestionQuery) ([]MutualCandidate, error) {
+	out := []MutualCandidate{}
+	err := r.db.WithContext(ctx).Raw(`
+SELECT c.id AS user_id, count(*) AS mutuals
+FROM follows f1
+JOIN follows f2 ON f2.follower_id = f1.followee_id
+JOIN users c ON c.id = f2.followee_id
+WHERE f1.follower_id = @me AND`+suggestable+`
+GROUP BY c.id
+ORDER BY mutuals DESC, c.id
+LIMIT @limit`, q.args(nil)).Scan(&out).Error
+	return out, err
+}
+
+func (r *GormSuggestionRepository) WatchlistOverlap(ctx context.Context, q SuggestionQuery, minShared int) ([]OverlapCandidate, error) {
+	out := []OverlapCandidate{}
+	// Only candidates' items that are among mine are scanned, through the
+	// movie_id index; their full list sizes are counted just for the few
+	// candidates that clear minShared.
+	err := r.db.WithContext(ctx).Raw(`
+WITH mine AS (
+    SELECT wi.movie_id
+    FROM watchlist_items wi
+    JOIN watchlists w ON w.id = wi.watchlist_id AND w.deleted_at IS NULL
+    WHERE w.owner_id = @me
+    GROUP BY wi.movie_id
+    ORDER BY max(wi.added_at) DESC
+    LIMIT @sample
+),
+shared AS (
+    SELECT c.id AS user_id, count(DISTINCT wi.movie_id) AS n
+    FROM mine
+    JOIN watchlist_items wi ON wi.movie_id = mine.movie_id
+    JOIN watchlists w ON w.id = wi.watchlist_id AND w.deleted_at IS NULL AND w.visibility = @public
+    JOIN users c ON c.id = w.owner_id AND NOT c.is_private
+    WHERE`+suggestable+`
+    GROUP BY c.id
+    HAVING count(DISTINCT wi.movie_id) >= @min
+),
+sizes AS (
+    SELECT w.owner_id AS user_id, count(DISTINCT wi.movie_id) AS n
+    FROM watchlist_items wi
+    JOIN watchlists w ON w.id = wi.watchlist_id AND w.deleted_at IS NULL AND w.visibility = @public
+    WHERE w.owner_id IN (SELECT user_id FROM shared)
+    GROUP BY w.owner_id
+)
+SELECT s.user_id, s.n AS shared_films, s.n::float8 / ((SELECT count(*) FROM mine) + z.n - s.n) AS jaccard
+FROM shared s JOIN sizes z USING (user_id)
+ORDER BY jaccard DESC, s.user_id
+LIMIT @limit`, q.args(map[string]any{"public": models.PublicVisibility, "min": minShared, "sample": overlapSample})).Scan(&out).Error
+	return out, err
+}
+
+func (r *GormSuggestionRepository) TasteMatches(ctx context.Context, q SuggestionQuery, minCoRated int) ([]TasteCandidate, error) {
+	out := []TasteCandidate{}
+	err := r.db.WithContext(ctx).Raw(`
+WITH mine AS (
+    SELECT movie_id, avg(rating) AS rating
+    FROM reviews
+    WHERE user_id = @me AND deleted_at IS NULL
+    GROUP BY movie_id
+),
+pairs AS (
+    SELECT c.id AS user_id, t.movie_id, mine.rating AS a, avg(t.rating) AS b
+    FROM reviews t
+    JOIN mine ON mine.movie_id = t.movie_id
+    JOIN users c ON c.id = t.user_id AND NOT c.is_private
+    WHERE t.deleted_at IS NULL AND`+suggestable+`
+    GROUP BY c.id, t.movie_id, mine.rating
+),
+matches AS (
+    SELECT user_id, count(*) AS co_rated, 1 - avg(abs(a - b)) / 9.0 AS agreement
+    FROM pairs
+    GROUP BY user_id
+    HAVING count(*) >= @min
+),
+loved AS (
+    SELECT DISTINCT ON (p.user_id) p.user_id, c->>'name' AS director
+    FROM pairs p
+    JOIN movies m ON m.id = p.movie_id
+    CROSS JOIN LATERAL jsonb_array_elements(COALESCE(m.metadata->'details'->'crew', '[]'::jsonb)) c
+    WHERE p.a >= 8 AND p.b >= 8 AND p.user_id IN (SELECT user_id FROM matches) AND c->>'job' = 'Director'
+    GROUP BY p.user_id, c->>'name'
+    HAVING count(DISTINCT p.movie_id) >= 2
+    ORDER BY p.user_id, count(DISTINCT p.movie_id) DESC, c->>'name'
+)
+SELECT mt.user_id, mt.co_rated, mt.agreement, COALESCE(l.director, '') AS loved_director
+FROM matches mt LEFT JOIN loved l USING (user_id)
+ORDER BY mt.agreement * least(mt.co_rated, 10) DESC, mt.user_id
+LIMIT @limit`, q.args(map[string]any{"min": minCoRated})).Scan(&out).Error
+	return out, err
+}
+
+func (r *GormSuggestionRepository) Popular(ctx context.Context, q SuggestionQuery, activeSince time.Time) ([]PopularCandidate, error) {
+	out := []PopularCandidate{}
+	err := r.db.WithContext(ctx).Raw(`
+SELECT c.id AS user_id, count(f.follower_id) AS followers
+FROM users c
+LEFT JOIN follows f ON f.followee_id = c.id
+WHERE NOT c.is_private
+    AND EXISTS (SELECT 1 FROM watchlists w WHERE w.owner_id = c.id AND w.deleted_at IS NULL AND w.visibility = @public AND w.updated_at >= @since)
+    AND`+suggestable+`
+GROUP BY c.id
+ORDER BY followers DESC, c.id
+LIMIT @limit`, q.args(map[string]any{"public": models.PublicVisibility, "since": activeSince})).Scan(&out).Error
+	return out, err
+}
+
+func (r *GormSuggestionRepository) Dismiss(ctx context.Context, userID, dismissedID string) error {
+	d := models.SuggestionDismissal{UserID: parseUUID(userID), DismissedID: parseUUID(dismissedID)}
+	return r.db.WithContext(ctx).Clauses(clause.OnConflict{DoNothing: true}).Create(&d).Error
+}
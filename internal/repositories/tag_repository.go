@@ -0,0 +1,112 @@
+package repositories
+
+import (
+	"context"
+	"encoding/json"
+	"strings"
+
+	"github.com/google/uuid"
+	"gorm.io/datatypes"
+	"gorm.io/gorm"
+
+	"github.com/Dubjay18/scenee/internal/models"
+	"github.com/Dubjay18/scenee/internal/tags"
+)
+
+// TagCount is a tag with the number of public watchlists using it.
+type TagCount struct {
+	Tag   string `json:"tag"`
+	Count int64  `json:"count"`
+}
+
+// StoredTags is a watchlist's tags as stored, which before normalization
+// was enforced may hold anything.
+type StoredTags struct {
+	ID   uuid.UUID
+	Tags datatypes.JSON
+}
+
+var tagSorts = map[string]string{
+	"popular": "watchlists.save_count DESC, watchlists.like_count DESC, watchlists.updated_at DESC",
+	"recent":  "watchlists.updated_at DESC",
+}
+
+type TagRepository interface {
+	// Search returns tags starting with prefix, most used first.
+	Search(ctx context.Context, prefix string, limit int) ([]TagCount, error)
+	Popular(ctx context.Context, limit int) ([]TagCount, error)
+	Watchlists(ctx context.Context, tag, sort string, limit, offset int) ([]models.Watchlist, int64, error)
+	// Unnormalized returns up to limit watchlists, deleted ones included,
+	// with too many tags or a tag not in normalized form.
+	Unnormalized(ctx context.Context, limit int) ([]StoredTags, error)
+	// SetTags replaces a watchlist's tags without touching updated_at.
+	SetTags(ctx context.Context, watchlistID uuid.UUID, list []string) error
+}
+
+type GormTagRepository struct {
+	db *gorm.DB
+}
+
+func NewTagRepository(db *gorm.DB) *GormTagRepository {
+	return &GormTagRepository{db: db}
+}
+
+func (r *GormTagRepository) publicTags(ctx context.Context) *gorm.DB {
+	return r.db.WithContext(ctx).Table("watchlists, jsonb_array_elements_text(watchlists.tags) AS t(tag)").
+		Select("t.tag, COUNT(*) AS count").
+		Where("watchlists.deleted_at IS NULL AND watchlists.visibility = ?", models.PublicVisibility).
+		Group("t.tag")
+}
+
+func (r *GormTagRepository) Search(ctx context.Context, prefix string, limit int) ([]TagCount, error) {
+	var out []TagCount
+	escaped := strings.NewReplacer(`\`, `\\`, "%", `\%`, "_", `\_`).Replace(prefix)
+	err := r.publicTags(ctx).Where("t.tag LIKE ?", escaped+"%").
+		Order("count DESC, t.tag ASC").Limit(limit).Scan(&out).Error
+	return out, err
+}
+
+func (r *GormTagRepository) Popular(ctx context.Context, limit int) ([]TagCount, error) {
+	var out []TagCount
+	err := r.publicTags(ctx).Order("count DESC, t.tag ASC").Limit(limit).Scan(&out).Error
+	return out, err
+}
+
+// Watchlists pages through public watchlists carrying tag. The containment
+// test is served by the GIN index on tags.
+func (r *GormTagRepository) Watchlists(ctx context.Context, tag, sort string, limit, offset int) ([]models.Watchlist, int64, error) {
+	needle, _ := json.Marshal([]string{tag})
+	q := r.db.WithContext(ctx).Model(&models.Watchlist{}).
+		Where("watchlists.visibility = ?", models.PublicVisibility).
+		Where(publicOwner).
+		Where("watchlists.tags @> ?::jsonb", string(needle))
+	var total int64
+	if err := q.Count(&total).Error; err != nil {
+		return nil, 0, err
+	}
+	order, ok := tagSorts[sort]
+	if !ok {
+		order = tagSorts["popular"]
+	}
+	var out []models.Watchlist
+	if err := q.Preload("Owner").Order(order).Limit(limit).Offset(offset).Find(&out).Error; err != nil {
+		return nil, 0, err
+	}
+	return out, total, nil
+}
+
+func (r *GormTagRepository) Unnormalized(ctx context.Context, limit int) ([]StoredTags, error) {
+	out := []StoredTags{}
+	err := r.db.WithContext(ctx).Table("watchlists").Select("id, tags").
+		Where("jsonb_typeof(tags) = 'array'").
+		Where(`jsonb_array_length(tags) > ? OR EXISTS (
+			SELECT 1 FROM jsonb_array_elements(tags) AS t(tag)
+			WHERE jsonb_typeof(t.tag) <> 'string' OR length(t.tag #>> '{}') > ? OR (t.tag #>> '{}') !~ '^[a-z0-9]+(-[a-z0-9]+)*$')`, tags.MaxTags, tags.MaxLength).
+		Order("id").Limit(limit).Scan(&out).Error
+	return out, err
+}
+
+func (r *GormTagRepository) SetTags(ctx context.Context, watchlistID uuid.UUID, list []string) error {
+	return r.db.WithContext(ctx).Unscoped().Model(&models.Watchlist{}).Where("id = ?", watchlistID).
+		UpdateColumn("tags", models.EncodeStringSlice(list)).Error
+}
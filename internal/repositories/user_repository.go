@@ -3,8 +3,11 @@ package repositories
 import (
 	"context"
 	"errors"
+	"strings"
 
+	"github.com/google/uuid"
 	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
 
 	"github.com/Dubjay18/scenee/internal/models"
 )
@@ -13,6 +16,12 @@ type UserRepository interface {
 	Upsert(ctx context.Context, user *models.User) error
 	GetByID(ctx context.Context, id string) (*models.User, error)
 	GetByEmail(ctx context.Context, email string) (*models.User, error)
+	GetByUsername(ctx context.Context, username string) (*models.User, error)
+	GetByUsernames(ctx context.Context, usernames []string) ([]models.User, error)
+	GetByIDs(ctx context.Context, ids []uuid.UUID) ([]models.User, error)
+	// Search matches usernames containing query, exact and prefix matches
+	// first, skipping the excluded users.
+	Search(ctx context.Context, query string, exclude []uuid.UUID, limit, offset int) ([]models.User, error)
 	Update(ctx context.Context, id string, updates map[string]interface{}) error
 	Delete(ctx context.Context, id string) error
 }
@@ -48,6 +57,57 @@ func (r *GormUserRepository) GetByEmail(ctx context.Context, email string) (*mod
 	return &user, nil
 }
 
+// GetByUsername looks a user up by username, ignoring case.
+func (r *GormUserRepository) GetByUsername(ctx context.Context, username string) (*models.User, error) {
+	var user models.User
+	if err := r.db.WithContext(ctx).Where("lower(username) = lower(?)", username).First(&user).Error; err != nil {
+		return nil, err
+	}
+	return &user, nil
+}
+
+// GetByUsernames looks users up by username, ignoring case.
+func (r *GormUserRepository) GetByUsernames(ctx context.Context, usernames []string) ([]models.User, error) {
+	var users []models.User
+	if len(usernames) == 0 {
+		return users, nil
+	}
+	lower := make([]string, len(usernames))
+	for i, u := range usernames {
+		lower[i] = strings.ToLower(u)
+	}
+	err := r.db.WithContext(ctx).Where("lower(username) IN ?", lower).Find(&users).Error
+	return users, err
+}
+
+func (r *GormUserRepository) GetByIDs(ctx context.Context, ids []uuid.UUID) ([]models.User, error) {
+	users := []models.User{}
+	if len(ids) == 0 {
+		return users, nil
+	}
+	err := r.db.WithContext(ctx).Where("id IN ?", ids).Find(&users).Error
+	return users, err
+}
+
+func (r *GormUserRepository) Search(ctx context.Context, query string, exclude []uuid.UUID, limit, offset int) ([]models.User, error) {
+	users := []models.User{}
+	q := r.db.WithContext(ctx).Where("username ILIKE ?", "%"+escapeLike(query)+"%")
+	if len(exclude) > 0 {
+		q = q.Where("id NOT IN ?", exclude)
+	}
+	err := q.Order(clause.Expr{SQL: "lower(username) = lower(?) DESC, username ILIKE ? DESC, length(username), username", Vars: []any{query, escapeLike(query) + "%"}}).
+		Limit(limit).Offset(offset).
+		Find(&users).Error
+	return users, err
+}
+
+// escapeLike escapes LIKE wildcards so s matches literally.
+func escapeLike(s string) string {
+	return likeEscaper.Replace(s)
+}
+
+var likeEscaper = strings.NewReplacer(`\`, `\\`, `%`, `\%`, `_`, `\_`)
+
 func (r *GormUserRepository) Update(ctx context.Context, id string, updates map[string]interface{}) error {
 	return r.db.WithContext(ctx).Model(&models.User{}).Where("id = ?", id).Updates(updates).Error
 }
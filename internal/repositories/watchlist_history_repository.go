@@ -0,0 +1,65 @@
+package repositories
+
+import (
+	"context"
+	"time"
+
+	"gorm.io/gorm"
+
+	"github.com/Dubjay18/scenee/internal/models"
+)
+
+type WatchlistHistoryRepository interface {
+	Record(ctx context.Context, rev *models.WatchlistRevision) error
+	List(ctx context.Context, watchlistID string, beforeSeq int64, limit int) ([]models.WatchlistRevision, error)
+	Get(ctx context.Context, watchlistID, id string) (*models.WatchlistRevision, error)
+	// After returns the revisions made since rev, newest first.
+	After(ctx context.Context, rev *models.WatchlistRevision) ([]models.WatchlistRevision, error)
+	// Purge deletes revisions made before cutoff and reports how many.
+	Purge(ctx context.Context, cutoff time.Time) (int64, error)
+}
+
+type GormWatchlistHistoryRepository struct {
+	db *gorm.DB
+}
+
+func NewWatchlistHistoryRepository(db *gorm.DB) *GormWatchlistHistoryRepository {
+	return &GormWatchlistHistoryRepository{db: db}
+}
+
+func (r *GormWatchlistHistoryRepository) Record(ctx context.Context, rev *models.WatchlistRevision) error {
+	return r.db.WithContext(ctx).Create(rev).Error
+}
+
+// List returns revisions before beforeSeq (zero means from the newest),
+// newest first. Paging on seq rather than created_at keeps revisions written
+// together from being split and skipped at a page boundary.
+func (r *GormWatchlistHistoryRepository) List(ctx context.Context, watchlistID string, beforeSeq int64, limit int) ([]models.WatchlistRevision, error) {
+	q := r.db.WithContext(ctx).Where("watchlist_id = ?", watchlistID)
+	if beforeSeq > 0 {
+		q = q.Where("seq < ?", beforeSeq)
+	}
+	var out []models.WatchlistRevision
+	err := q.Order("seq DESC").Limit(limit).Find(&out).Error
+	return out, err
+}
+
+func (r *GormWatchlistHistoryRepository) Get(ctx context.Context, watchlistID, id string) (*models.WatchlistRevision, error) {
+	var rev models.WatchlistRevision
+	if err := r.db.WithContext(ctx).Where("id = ? AND watchlist_id = ?", id, watchlistID).First(&rev).Error; err != nil {
+		return nil, err
+	}
+	return &rev, nil
+}
+
+func (r *GormWatchlistHistoryRepository) After(ctx context.Context, rev *models.WatchlistRevision) ([]models.WatchlistRevision, error) {
+	var out []models.WatchlistRevision
+	err := r.db.WithContext(ctx).Where("watchlist_id = ? AND seq > ?", rev.WatchlistID, rev.Seq).
+		Order("seq DESC").Find(&out).Error
+	return out, err
+}
+
+func (r *GormWatchlistHistoryRepository) Purge(ctx context.Context, cutoff time.Time) (int64, error) {
+	res := r.db.WithContext(ctx).Where("created_at < ?", cutoff).Delete(&models.WatchlistRevision{})
+	return res.RowsAffected, res.Error
+}
@@ -2,6 +2,10 @@ package repositories
 
 import (
 	"context"
+	"encoding/json"
+	"slices"
+	"strings"
+	"time"
 
 	"gorm.io/gorm"
 	"gorm.io/gorm/clause"
@@ -14,18 +18,80 @@ type WatchlistRepository interface {
 	Create(ctx context.Context, watchlist *models.Watchlist) error
 	Update(ctx context.Context, watchlist *models.Watchlist) error
 	Delete(ctx context.Context, id, owner string) error
-	Save(ctx context.Context, userID, watchlistID string) error
+	Save(ctx context.Context, userID, watchlistID string) (bool, error)
 	Unsave(ctx context.Context, userID, watchlistID string) error
 	GetByID(ctx context.Context, id string) (*models.Watchlist, error)
+	GetSummary(ctx context.Context, id string) (*models.Watchlist, error)
 	GetBySlug(ctx context.Context, slug string) (*models.Watchlist, error)
+	GetByOwnerUsernameAndSlug(ctx context.Context, username, slug string) (*models.Watchlist, error)
+	ResolveSlugRedirect(ctx context.Context, username, slug string) (*models.Watchlist, error)
+	SlugTaken(ctx context.Context, slug, exceptID string) (bool, error)
 	ListByOwner(ctx context.Context, owner string) ([]models.Watchlist, error)
 	ListPublicByOwner(ctx context.Context, owner string) ([]models.Watchlist, error)
+	// Search matches public lists by title, description or tag, most liked
+	// first, skipping lists owned by the excluded users and private accounts
+	// viewer doesn't follow.
+	Search(ctx context.Context, viewer, query string, excludeOwners []uuid.UUID, limit, offset int) ([]models.Watchlist, error)
+	// OwnerVisible reports whether viewer may see owner's non-private lists:
+	// always for public accounts, for private ones only when following.
+	OwnerVisible(ctx context.Context, owner, viewer string) (bool, error)
+	// SharedWith reports whether the list's owner shared it with userID.
+	SharedWith(ctx context.Context, watchlistID, userID string) (bool, error)
 	EnsureOwner(ctx context.Context, watchlistID, owner string) error
 	AddItem(ctx context.Context, item *models.WatchlistItem, owner string) error
 	RemoveItem(ctx context.Context, watchlistID, itemID, owner string) error
-	Like(ctx context.Context, userID, watchlistID string) error
+	GetItem(ctx context.Context, watchlistID, itemID string) (*models.WatchlistItem, error)
+	UpdateItemNote(ctx context.Context, watchlistID, itemID, note, owner string) error
+	MoveItem(ctx context.Context, watchlistID, itemID string, position int, owner string) (int, error)
+	RestoreItem(ctx context.Context, item *models.WatchlistItem, owner string) error
+	// ReplaceItems makes items, in order, the list's items: others are
+	// removed, those still there keep their ID and get the given note, and
+	// missing ones are re-created with their old ID.
+	ReplaceItems(ctx context.Context, watchlistID, owner string, items []models.WatchlistItem) error
+	// Tx runs fn in one transaction with repositories bound to it, so a
+	// change and the revisions recording it are saved together or not at all.
+	Tx(ctx context.Context, fn func(watchlists WatchlistRepository, history WatchlistHistoryRepository) error) error
+	ApplyItemBatch(ctx context.Context, watchlistID, owner string, batch ItemBatch) (*ItemBatchResult, error)
+	GetDeleted(ctx context.Context, id, owner string) (*models.Watchlist, error)
+	Restore(ctx context.Context, id, owner string) error
+	Like(ctx context.Context, userID, watchlistID string) (bool, error)
 	Unlike(ctx context.Context, userID, watchlistID string) error
-	Top(ctx context.Context, window string, limit int) ([]models.Watchlist, error)
+	RecordView(ctx context.Context, watchlistID uuid.UUID, viewerKey string, bucket time.Time) (bool, error)
+	PurgeViews(ctx context.Context, cutoff time.Time) (int64, error)
+	ResolveSmartRules(ctx context.Context, ownerID string, rules *models.SmartRules) ([]uuid.UUID, error)
+	ClaimRefresh(ctx context.Context, watchlistID uuid.UUID, seen *time.Time) (bool, error)
+	MaterializeItems(ctx context.Context, watchlistID uuid.UUID, movieIDs []uuid.UUID) error
+	CoverPosters(ctx context.Context, watchlistID string, n int) ([]string, error)
+	SetCover(ctx context.Context, watchlistID, url, key string, custom bool) (int64, error)
+	ResetCover(ctx context.Context, watchlistID string) error
+}
+
+// ItemMove moves one item to Position, counted after removals and additions.
+type ItemMove struct {
+	ItemID   uuid.UUID
+	Position int
+}
+
+// ItemBatch is a set of item edits applied together: removals first, then
+// additions appended in order, then moves in order.
+type ItemBatch struct {
+	Add    []models.WatchlistItem
+	Remove []uuid.UUID
+	Move   []ItemMove
+}
+
+// MovedItem is an item after a batch move, with the position it started at.
+type MovedItem struct {
+	Item models.WatchlistItem
+	From int
+}
+
+type ItemBatchResult struct {
+	Added     []models.WatchlistItem
+	Duplicate map[uuid.UUID]bool // movie IDs skipped because already listed
+	Removed   []models.WatchlistItem
+	Moved     []MovedItem
+	ItemCount int
 }
 
 type GormWatchlistRepository struct {
@@ -36,70 +102,78 @@ func NewWatchlistRepository(db *gorm.DB) *GormWatchlistRepository {
 	return &GormWatchlistRepository{db: db}
 }
 
-func (r *GormWatchlistRepository) Save(ctx context.Context, userID, watchlistID string) error {
-	// First, check if the user has already saved this watchlist
-	var watchlist models.Watchlist
-	if err := r.db.WithContext(ctx).Where("id = ?", watchlistID).First(&watchlist).Error; err != nil {
-		return err
+// Save records that userID saved the watchlist. It reports whether a new
+// save was created; saving twice is a no-op.
+func (r *GormWatchlistRepository) Save(ctx context.Context, userID, watchlistID string) (bool, error) {
+	uid, err := uuid.Parse(userID)
+	if err != nil {
+		return false, err
 	}
-
-	// Check if userID is already in SavedBy
-	for _, id := range watchlist.SavedBy {
-		if id == userID {
-			// Already saved, return without error
+	wid, err := uuid.Parse(watchlistID)
+	if err != nil {
+		return false, err
+	}
+	created := false
+	err = r.db.WithContext(ctx).Transaction(func(tx *gorm.DB) error {
+		res := tx.Clauses(clause.OnConflict{DoNothing: true}).Create(&models.Save{UserID: uid, WatchlistID: wid})
+		if res.Error != nil {
+			return res.Error
+		}
+		if res.RowsAffected == 0 {
 			return nil
 		}
-	}
-
-	// Add userID to SavedBy array and increment SaveCount
-	return r.db.WithContext(ctx).Model(&models.Watchlist{}).
-		Where("id = ?", watchlistID).
-		Updates(map[string]interface{}{
-			"saved_by":   gorm.Expr("saved_by || ?::jsonb", `"`+userID+`"`),
-			"save_count": gorm.Expr("save_count + 1"),
-		}).Error
+		created = true
+		return tx.Model(&models.Watchlist{}).Where("id = ?", wid).UpdateColumn("save_count", gorm.Expr("save_count + 1")).Error
+	})
+	return created, err
 }
 
+// Unsave removes a save. Unsaving something that isn't saved is a no-op.
 func (r *GormWatchlistRepository) Unsave(ctx context.Context, userID, watchlistID string) error {
-	// First, check if the watchlist exists and user has saved it
-	var watchlist models.Watchlist
-	if err := r.db.WithContext(ctx).Where("id = ?", watchlistID).First(&watchlist).Error; err != nil {
-		return err
-	}
-
-	// Check if userID is in SavedBy
-	found := false
-	for _, id := range watchlist.SavedBy {
-		if id == userID {
-			found = true
-			break
+	return r.db.WithContext(ctx).Transaction(func(tx *gorm.DB) error {
+		res := tx.Where("user_id = ? AND watchlist_id = ?", userID, watchlistID).Delete(&models.Save{})
+		if res.Error != nil || res.RowsAffected == 0 {
+			return res.Error
 		}
-	}
-
-	if !found {
-		// Not saved, return without error
-		return nil
-	}
-
-	// Remove userID from SavedBy array and decrement SaveCount
-	// Using jsonb_set to remove the element
-	return r.db.WithContext(ctx).Model(&models.Watchlist{}).
-		Where("id = ?", watchlistID).
-		Updates(map[string]interface{}{
-			"saved_by":   gorm.Expr("(SELECT jsonb_agg(elem) FROM jsonb_array_elements(saved_by) elem WHERE elem::text != ?)", `"`+userID+`"`),
-			"save_count": gorm.Expr("GREATEST(save_count - 1, 0)"),
-		}).Error
+		return tx.Model(&models.Watchlist{}).Where("id = ?", watchlistID).UpdateColumn("save_count", gorm.Expr("GREATEST(save_count - 1, 0)")).Error
+	})
 }
 
 func (r *GormWatchlistRepository) Create(ctx context.Context, watchlist *models.Watchlist) error {
 	return r.db.WithContext(ctx).Create(watchlist).Error
 }
 
+// Update persists editable metadata. When the slug changes, the previous slug
+// is kept as a redirect so shared links keep working.
 func (r *GormWatchlistRepository) Update(ctx context.Context, watchlist *models.Watchlist) error {
-	return r.db.WithContext(ctx).Model(&models.Watchlist{}).Where("id = ? AND owner_id = ?", watchlist.ID, watchlist.OwnerID).Updates(map[string]any{
-		"title":       watchlist.Title,
-		"description": watchlist.Description,
-	}).Error
+	return r.db.WithContext(ctx).Transaction(func(tx *gorm.DB) error {
+		var current models.Watchlist
+		if err := tx.Select("id", "slug").Where("id = ? AND owner_id = ?", watchlist.ID, watchlist.OwnerID).First(&current).Error; err != nil {
+			return err
+		}
+		if err := tx.Model(&models.Watchlist{}).Where("id = ?", watchlist.ID).Updates(map[string]any{
+			"title":            watchlist.Title,
+			"description":      watchlist.Description,
+			"slug":             watchlist.Slug,
+			"visibility":       watchlist.Visibility,
+			"tags":             models.EncodeStringSlice(watchlist.Tags),
+			"rules":            watchlist.Rules,
+			"comments_enabled": watchlist.CommentsEnabled,
+		}).Error; err != nil {
+			return err
+		}
+		if strings.EqualFold(current.Slug, watchlist.Slug) {
+			return nil
+		}
+		// Renaming back to an old slug reclaims it.
+		if err := tx.Where("slug = ? AND watchlist_id = ?", watchlist.Slug, watchlist.ID).Delete(&models.WatchlistSlugRedirect{}).Error; err != nil {
+			return err
+		}
+		return tx.Clauses(clause.OnConflict{
+			Columns:   []clause.Column{{Name: "slug"}},
+			DoUpdates: clause.AssignmentColumns([]string{"watchlist_id", "created_at"}),
+		}).Create(&models.WatchlistSlugRedirect{Slug: current.Slug, WatchlistID: watchlist.ID, CreatedAt: time.Now()}).Error
+	})
 }
 
 func (r *GormWatchlistRepository) Delete(ctx context.Context, id, owner string) error {
@@ -114,14 +188,80 @@ func (r *GormWatchlistRepository) GetByID(ctx context.Context, id string) (*mode
 	return &watchlist, nil
 }
 
+// GetSummary loads the watchlist row without its items.
+func (r *GormWatchlistRepository) GetSummary(ctx context.Context, id string) (*models.Watchlist, error) {
+	var watchlist models.Watchlist
+	if err := r.db.WithContext(ctx).First(&watchlist, "id = ?", id).Error; err != nil {
+		return nil, err
+	}
+	return &watchlist, nil
+}
+
 func (r *GormWatchlistRepository) GetBySlug(ctx context.Context, slug string) (*models.Watchlist, error) {
 	var watchlist models.Watchlist
-	if err := r.db.WithContext(ctx).Preload("Items", func(tx *gorm.DB) *gorm.DB { return tx.Order("position ASC") }).Where("slug = ? AND visibility = 'public'", slug).First(&watchlist).Error; err != nil {
+	if err := r.db.WithContext(ctx).Preload("Items", func(tx *gorm.DB) *gorm.DB { return tx.Order("position ASC") }).Where("slug = ? AND visibility IN ('public','unlisted')", slug).Where(publicOwner).First(&watchlist).Error; err != nil {
+		return nil, err
+	}
+	return &watchlist, nil
+}
+
+func (r *GormWatchlistRepository) GetByOwnerUsernameAndSlug(ctx context.Context, username, slug string) (*models.Watchlist, error) {
+	var watchlist models.Watchlist
+	if err := r.db.WithContext(ctx).Preload("Items", func(tx *gorm.DB) *gorm.DB { return tx.Order("position ASC") }).
+		Joins("JOIN users u ON u.id = watchlists.owner_id AND u.deleted_at IS NULL AND NOT u.is_private").
+		Where("lower(u.username) = lower(?) AND watchlists.slug = ? AND watchlists.visibility IN ('public','unlisted')", username, slug).
+		First(&watchlist).Error; err != nil {
+		return nil, err
+	}
+	return &watchlist, nil
+}
+
+// ResolveSlugRedirect returns the watchlist an old slug now points to, with its
+// owner loaded so callers can build vanity URLs. A non-empty username limits
+// it to that owner's lists.
+func (r *GormWatchlistRepository) ResolveSlugRedirect(ctx context.Context, username, slug string) (*models.Watchlist, error) {
+	var redirect models.WatchlistSlugRedirect
+	if err := r.db.WithContext(ctx).Where("slug = ?", slug).First(&redirect).Error; err != nil {
+		return nil, err
+	}
+	var watchlist models.Watchlist
+	q := r.db.WithContext(ctx).Preload("Owner").
+		Where("id = ? AND visibility IN ('public','unlisted')", redirect.WatchlistID).
+		Where(publicOwner)
+	if username != "" {
+		q = q.Where("owner_id IN (SELECT id FROM users WHERE lower(username) = lower(?))", username)
+	}
+	if err := q.First(&watchlist).Error; err != nil {
 		return nil, err
 	}
 	return &watchlist, nil
 }
 
+// SlugTaken reports whether slug is used by any watchlist other than exceptID,
+// either as its current slug or as a redirect. Soft-deleted lists still hold
+// their slug since the unique index covers them.
+func (r *GormWatchlistRepository) SlugTaken(ctx context.Context, slug, exceptID string) (bool, error) {
+	var count int64
+	q := r.db.WithContext(ctx).Unscoped().Model(&models.Watchlist{}).Where("slug = ?", slug)
+	if exceptID != "" {
+		q = q.Where("id <> ?", exceptID)
+	}
+	if err := q.Count(&count).Error; err != nil {
+		return false, err
+	}
+	if count > 0 {
+		return true, nil
+	}
+	q = r.db.WithContext(ctx).Model(&models.WatchlistSlugRedirect{}).Where("slug = ?", slug)
+	if exceptID != "" {
+		q = q.Where("watchlist_id <> ?", exceptID)
+	}
+	if err := q.Count(&count).Error; err != nil {
+		return false, err
+	}
+	return count > 0, nil
+}
+
 func (r *GormWatchlistRepository) ListByOwner(ctx context.Context, owner string) ([]models.Watchlist, error) {
 	var out []models.Watchlist
 	if err := r.db.WithContext(ctx).Where("owner_id = ?", owner).Order("updated_at DESC").Find(&out).Error; err != nil {
@@ -132,12 +272,62 @@ func (r *GormWatchlistRepository) ListByOwner(ctx context.Context, owner string)
 
 func (r *GormWatchlistRepository) ListPublicByOwner(ctx context.Context, owner string) ([]models.Watchlist, error) {
 	var out []models.Watchlist
-	if err := r.db.WithContext(ctx).Where("owner_id = ? AND is_public = TRUE", owner).Order("updated_at DESC").Find(&out).Error; err != nil {
+	if err := r.db.WithContext(ctx).Where("owner_id = ? AND visibility = ?", owner, models.PublicVisibility).Order("updated_at DESC").Find(&out).Error; err != nil {
 		return nil, err
 	}
 	return out, nil
 }
 
+func (r *GormWatchlistRepository) Search(ctx context.Context, viewer, query string, excludeOwners []uuid.UUID, limit, offset int) ([]models.Watchlist, error) {
+	out := []models.Watchlist{}
+	pattern := "%" + escapeLike(query) + "%"
+	needle, _ := json.Marshal([]string{strings.ToLower(strings.TrimSpace(query))})
+	q := r.db.WithContext(ctx).
+		Where("visibility = ?", models.PublicVisibility).
+		Where("title ILIKE ? OR description ILIKE ? OR tags @> ?::jsonb", pattern, pattern, string(needle)).
+		Where(ownerVisibleTo, viewer, viewer)
+	if len(excludeOwners) > 0 {
+		q = q.Where("owner_id NOT IN ?", excludeOwners)
+	}
+	err := q.Order("like_count DESC, updated_at DESC").Limit(limit).Offset(offset).Find(&out).Error
+	return out, err
+}
+
+// publicOwner keeps lists whose owner has a public account.
+const publicOwner = `watchlists.owner_id NOT IN (SELECT id FROM users WHERE is_private)`
+
+// ownerVisibleTo keeps lists whose owner has a public account, is the viewer
+// or is a private account the viewer follows. Bind the viewer's ID (or "")
+// twice.
+const ownerVisibleTo = `(` + publicOwner + ` OR watchlists.owner_id::text = ? OR watchlists.owner_id IN (SELECT followee_id FROM follows WHERE follower_id::text = ?))`
+
+// listVisibleTo is checkListVisible as a filter: the viewer's own lists,
+// non-private lists passing ownerVisibleTo, and lists the owner shared with
+// the viewer. Bind the viewer's ID (or "") four times.
+const listVisibleTo = `(watchlists.owner_id::text = ?
+    OR (watchlists.visibility <> 'private' AND ` + ownerVisibleTo + `)
+    OR EXISTS (SELECT 1 FROM shares WHERE shares.watchlist_id = watchlists.id AND shares.from_user_id = watchlists.owner_id AND shares.to_user_id::text = ?))`
+
+func (r *GormWatchlistRepository) OwnerVisible(ctx context.Context, owner, viewer string) (bool, error) {
+	var visible bool
+	err := r.db.WithContext(ctx).Raw(`
+		SELECT NOT is_private
+		    OR id::text = @viewer
+		    OR EXISTS (SELECT 1 FROM follows WHERE follower_id::text = @viewer AND followee_id = users.id)
+		FROM users WHERE id = @owner`,
+		map[string]any{"owner": owner, "viewer": viewer}).
+		Scan(&visible).Error
+	return visible, err
+}
+
+func (r *GormWatchlistRepository) SharedWith(ctx context.Context, watchlistID, userID string) (bool, error) {
+	var count int64
+	err := r.db.WithContext(ctx).Model(&models.Share{}).
+		Joins("JOIN watchlists ON watchlists.id = shares.watchlist_id AND watchlists.owner_id = shares.from_user_id").
+		Where("shares.watchlist_id = ? AND shares.to_user_id = ?", watchlistID, userID).Limit(1).Count(&count).Error
+	return count > 0, err
+}
+
 func (r *GormWatchlistRepository) EnsureOwner(ctx context.Context, watchlistID, owner string) error {
 	var count int64
 	if err := r.db.WithContext(ctx).Model(&models.Watchlist{}).Where("id = ? AND owner_id = ?", watchlistID, owner).Count(&count).Error; err != nil {
@@ -158,35 +348,514 @@ func (r *GormWatchlistRepository) AddItem(ctx context.Context, item *models.Watc
 		return err
 	}
 	item.Position = pos
-	return r.db.WithContext(ctx).Create(item).Error
+	return r.db.WithContext(ctx).Transaction(func(tx *gorm.DB) error {
+		if err := tx.Create(item).Error; err != nil {
+			return err
+		}
+		return syncItemCount(tx, item.WatchlistID)
+	})
 }
 
 func (r *GormWatchlistRepository) RemoveItem(ctx context.Context, watchlistID, itemID, owner string) error {
 	if err := r.EnsureOwner(ctx, watchlistID, owner); err != nil {
 		return err
 	}
-	return r.db.WithContext(ctx).Where("id = ? AND watchlist_id = ?", itemID, watchlistID).Delete(&models.WatchlistItem{}).Error
+	return r.db.WithContext(ctx).Transaction(func(tx *gorm.DB) error {
+		if err := tx.Where("id = ? AND watchlist_id = ?", itemID, watchlistID).Delete(&models.WatchlistItem{}).Error; err != nil {
+			return err
+		}
+		return syncItemCount(tx, watchlistID)
+	})
+}
+
+func (r *GormWatchlistRepository) GetItem(ctx context.Context, watchlistID, itemID string) (*models.WatchlistItem, error) {
+	var item models.WatchlistItem
+	if err := r.db.WithContext(ctx).Where("id = ? AND watchlist_id = ?", itemID, watchlistID).First(&item).Error; err != nil {
+		return nil, err
+	}
+	return &item, nil
+}
+
+func (r *GormWatchlistRepository) UpdateItemNote(ctx context.Context, watchlistID, itemID, note, owner string) error {
+	if err := r.EnsureOwner(ctx, watchlistID, owner); err != nil {
+		return err
+	}
+	res := r.db.WithContext(ctx).Model(&models.WatchlistItem{}).Where("id = ? AND watchlist_id = ?", itemID, watchlistID).Update("note", note)
+	if res.Error != nil {
+		return res.Error
+	}
+	if res.RowsAffected == 0 {
+		return gorm.ErrRecordNotFound
+	}
+	return nil
 }
 
-func (r *GormWatchlistRepository) Like(ctx context.Context, userID, watchlistID string) error {
-	return r.db.WithContext(ctx).Clauses(clause.OnConflict{Columns: []clause.Column{{Name: "user_id"}, {Name: "watchlist_id"}}, DoNothing: true}).Create(&models.Like{UserID: uuid.MustParse(userID), WatchlistID: uuid.MustParse(watchlistID)}).Error
+// MoveItem places an item at position, shifting the items in between, and
+// returns the position it ended up at (clamped to the list bounds).
+func (r *GormWatchlistRepository) MoveItem(ctx context.Context, watchlistID, itemID string, position int, owner string) (int, error) {
+	if err := r.EnsureOwner(ctx, watchlistID, owner); err != nil {
+		return 0, err
+	}
+	err := r.db.WithContext(ctx).Transaction(func(tx *gorm.DB) error {
+		var item models.WatchlistItem
+		if err := tx.Clauses(clause.Locking{Strength: "UPDATE"}).Where("id = ? AND watchlist_id = ?", itemID, watchlistID).First(&item).Error; err != nil {
+			return err
+		}
+		var maxPos int
+		if err := tx.Model(&models.WatchlistItem{}).Where("watchlist_id = ?", watchlistID).Select("COALESCE(MAX(position), 0)").Scan(&maxPos).Error; err != nil {
+			return err
+		}
+		position = max(0, min(position, maxPos))
+		from := item.Position
+		if position == from {
+			return nil
+		}
+		shift := tx.Model(&models.WatchlistItem{}).Where("watchlist_id = ? AND id <> ?", watchlistID, itemID)
+		var err error
+		if position < from {
+			err = shift.Where("position >= ? AND position < ?", position, from).Update("position", gorm.Expr("position + 1")).Error
+		} else {
+			err = shift.Where("position > ? AND position <= ?", from, position).Update("position", gorm.Expr("position - 1")).Error
+		}
+		if err != nil {
+			return err
+		}
+		return tx.Model(&item).Update("position", position).Error
+	})
+	return position, err
+}
+
+// RestoreItem re-inserts a previously removed item at its old position,
+// shifting later items down.
+func (r *GormWatchlistRepository) RestoreItem(ctx context.Context, item *models.WatchlistItem, owner string) error {
+	if err := r.EnsureOwner(ctx, item.WatchlistID.String(), owner); err != nil {
+		return err
+	}
+	return r.db.WithContext(ctx).Transaction(func(tx *gorm.DB) error {
+		if err := tx.Model(&models.WatchlistItem{}).Where("watchlist_id = ? AND position >= ?", item.WatchlistID, item.Position).Update("position", gorm.Expr("position + 1")).Error; err != nil {
+			return err
+		}
+		if err := tx.Create(item).Error; err != nil {
+			return err
+		}
+		return syncItemCount(tx, item.WatchlistID)
+	})
+}
+
+func (r *GormWatchlistRepository) ReplaceItems(ctx context.Context, watchlistID, owner string, items []models.WatchlistItem) error {
+	return r.db.WithContext(ctx).Transaction(func(tx *gorm.DB) error {
+		var wl models.Watchlist
+		if err := tx.Clauses(clause.Locking{Strength: "UPDATE"}).Select("id").Where("id = ? AND owner_id = ?", watchlistID, owner).First(&wl).Error; err != nil {
+			return err
+		}
+		keep := make([]uuid.UUID, 0, len(items))
+		for _, it := range items {
+			keep = append(keep, it.ID)
+		}
+		drop := tx.Where("watchlist_id = ?", wl.ID)
+		if len(keep) > 0 {
+			drop = drop.Where("id NOT IN ?", keep)
+		}
+		if err := drop.Delete(&models.WatchlistItem{}).Error; err != nil {
+			return err
+		}
+		// Positions are unconstrained, so rows can take their new ones in
+		// any order.
+		for i, it := range items {
+			it.WatchlistID, it.Position = wl.ID, i
+			if err := tx.Clauses(clause.OnConflict{
+				Columns:   []clause.Column{{Name: "id"}},
+				DoUpdates: clause.AssignmentColumns([]string{"note", "position"}),
+			}).Create(&it).Error; err != nil {
+				return err
+			}
+		}
+		return syncItemCount(tx, wl.ID)
+	})
+}
+
+func (r *GormWatchlistRepository) Tx(ctx context.Context, fn func(watchlists WatchlistRepository, history WatchlistHistoryRepository) error) error {
+	return r.db.WithContext(ctx).Transaction(func(tx *gorm.DB) error {
+		return fn(&GormWatchlistRepository{db: tx}, &GormWatchlistHistoryRepository{db: tx})
+	})
+}
+
+// ApplyItemBatch applies batch in one transaction with the watchlist row
+// locked, compacts positions and recomputes item_count. Removals and moves of
+// items not in the list are left out of the result.
+func (r *GormWatchlistRepository) ApplyItemBatch(ctx context.Context, watchlistID, owner string, batch ItemBatch) (*ItemBatchResult, error) {
+	res := &ItemBatchResult{Duplicate: map[uuid.UUID]bool{}}
+	err := r.db.WithContext(ctx).Transaction(func(tx *gorm.DB) error {
+		var wl models.Watchlist
+		if err := tx.Clauses(clause.Locking{Strength: "UPDATE"}).Select("id").Where("id = ? AND owner_id = ?", watchlistID, owner).First(&wl).Error; err != nil {
+			return err
+		}
+		var items []models.WatchlistItem
+		if err := tx.Where("watchlist_id = ?", wl.ID).Order("position ASC, added_at ASC").Find(&items).Error; err != nil {
+			return err
+		}
+		// Stored positions can have gaps or ties; report moves against the
+		// order the client saw, which is the compacted one.
+		original := make(map[uuid.UUID]int, len(items))
+		for i, it := range items {
+			original[it.ID] = i
+		}
+
+		if len(batch.Remove) > 0 {
+			drop := make(map[uuid.UUID]bool, len(batch.Remove))
+			for _, id := range batch.Remove {
+				drop[id] = true
+			}
+			kept := items[:0:0]
+			var ids []uuid.UUID
+			for _, it := range items {
+				if drop[it.ID] {
+					res.Removed = append(res.Removed, it)
+					ids = append(ids, it.ID)
+				} else {
+					kept = append(kept, it)
+				}
+			}
+			if len(ids) > 0 {
+				if err := tx.Where("id IN ?", ids).Delete(&models.WatchlistItem{}).Error; err != nil {
+					return err
+				}
+			}
+			items = kept
+		}
+
+		present := make(map[uuid.UUID]bool, len(items))
+		for _, it := range items {
+			present[it.MovieID] = true
+		}
+		var added []models.WatchlistItem
+		for _, it := range batch.Add {
+			if present[it.MovieID] {
+				res.Duplicate[it.MovieID] = true
+				continue
+			}
+			present[it.MovieID] = true
+			it.WatchlistID = wl.ID
+			it.Position = len(items)
+			items = append(items, it)
+			added = append(added, it)
+		}
+		if len(added) > 0 {
+			if err := tx.CreateInBatches(added, 200).Error; err != nil {
+				return err
+			}
+		}
+
+		for _, mv := range batch.Move {
+			from := slices.IndexFunc(items, func(it models.WatchlistItem) bool { return it.ID == mv.ItemID })
+			if from < 0 {
+				continue
+			}
+			to := max(0, min(mv.Position, len(items)-1))
+			it := items[from]
+			items = slices.Insert(slices.Delete(items, from, from+1), to, it)
+		}
+
+		for i := range items {
+			if items[i].Position == i {
+				continue
+			}
+			items[i].Position = i
+			if err := tx.Model(&models.WatchlistItem{}).Where("id = ?", items[i].ID).Update("position", i).Error; err != nil {
+				return err
+			}
+		}
+
+		moved := make(map[uuid.UUID]bool, len(batch.Move))
+		for _, mv := range batch.Move {
+			moved[mv.ItemID] = true
+		}
+		isAdded := make(map[uuid.UUID]bool, len(added))
+		for _, it := range added {
+			isAdded[it.ID] = true
+		}
+		for _, it := range items {
+			switch {
+			case isAdded[it.ID]:
+				res.Added = append(res.Added, it)
+			case moved[it.ID]:
+				res.Moved = append(res.Moved, MovedItem{Item: it, From: original[it.ID]})
+			}
+		}
+		res.ItemCount = len(items)
+		return tx.Model(&models.Watchlist{}).Where("id = ?", wl.ID).Update("item_count", len(items)).Error
+	})
+	if err != nil {
+		return nil, err
+	}
+	return res, nil
+}
+
+// syncItemCount recomputes a watchlist's item_count from its items.
+func syncItemCount(tx *gorm.DB, watchlistID any) error {
+	return tx.Model(&models.Watchlist{}).Where("id = ?", watchlistID).
+		Update("item_count", tx.Model(&models.WatchlistItem{}).Select("COUNT(*)").Where("watchlist_id = ?", watchlistID)).Error
+}
+
+// GetDeleted loads a soft-deleted watchlist belonging to owner.
+func (r *GormWatchlistRepository) GetDeleted(ctx context.Context, id, owner string) (*models.Watchlist, error) {
+	var watchlist models.Watchlist
+	if err := r.db.WithContext(ctx).Unscoped().Where("id = ? AND owner_id = ? AND deleted_at IS NOT NULL", id, owner).First(&watchlist).Error; err != nil {
+		return nil, err
+	}
+	return &watchlist, nil
+}
+
+func (r *GormWatchlistRepository) Restore(ctx context.Context, id, owner string) error {
+	res := r.db.WithContext(ctx).Unscoped().Model(&models.Watchlist{}).Where("id = ? AND owner_id = ? AND deleted_at IS NOT NULL", id, owner).Update("deleted_at", nil)
+	if res.Error != nil {
+		return res.Error
+	}
+	if res.RowsAffected == 0 {
+		return gorm.ErrRecordNotFound
+	}
+	return nil
+}
+
+// Like records a like and bumps like_count. It reports whether the like is
+// new; liking twice is a no-op.
+func (r *GormWatchlistRepository) Like(ctx context.Context, userID, watchlistID string) (bool, error) {
+	uid, err := uuid.Parse(userID)
+	if err != nil {
+		return false, err
+	}
+	wid, err := uuid.Parse(watchlistID)
+	if err != nil {
+		return false, err
+	}
+	created := false
+	err = r.db.WithContext(ctx).Transaction(func(tx *gorm.DB) error {
+		res := tx.Clauses(clause.OnConflict{Columns: []clause.Column{{Name: "user_id"}, {Name: "watchlist_id"}}, DoNothing: true}).Create(&models.Like{UserID: uid, WatchlistID: wid})
+		if res.Error != nil || res.RowsAffected == 0 {
+			return res.Error
+		}
+		created = true
+		return tx.Model(&models.Watchlist{}).Where("id = ?", wid).UpdateColumn("like_count", gorm.Expr("like_count + 1")).Error
+	})
+	return created, err
 }
 
 func (r *GormWatchlistRepository) Unlike(ctx context.Context, userID, watchlistID string) error {
-	return r.db.WithContext(ctx).Where("user_id = ? AND watchlist_id = ?", uuid.MustParse(userID), uuid.MustParse(watchlistID)).Delete(&models.Like{}).Error
+	return r.db.WithContext(ctx).Transaction(func(tx *gorm.DB) error {
+		res := tx.Where("user_id = ? AND watchlist_id = ?", userID, watchlistID).Delete(&models.Like{})
+		if res.Error != nil || res.RowsAffected == 0 {
+			return res.Error
+		}
+		return tx.Model(&models.Watchlist{}).Where("id = ?", watchlistID).UpdateColumn("like_count", gorm.Expr("GREATEST(like_count - 1, 0)")).Error
+	})
 }
 
-func (r *GormWatchlistRepository) Top(ctx context.Context, window string, limit int) ([]models.Watchlist, error) {
-	var out []models.Watchlist
-	q := r.db.WithContext(ctx).Table("watchlists w").Select("w.*").Joins("LEFT JOIN likes l ON l.watchlist_id = w.id").Where("w.is_public = TRUE")
-	switch window {
-	case "week":
-		q = q.Where("l.created_at >= NOW() - interval '7 days'")
-	case "month":
-		q = q.Where("l.created_at >= NOW() - interval '30 days'")
-	}
-	if err := q.Group("w.id").Order("COUNT(l.id) DESC, w.updated_at DESC").Limit(limit).Scan(&out).Error; err != nil {
+// RecordView counts a view unless viewerKey already viewed the list in the
+// same bucket. It reports whether the view was counted.
+func (r *GormWatchlistRepository) RecordView(ctx context.Context, watchlistID uuid.UUID, viewerKey string, bucket time.Time) (bool, error) {
+	counted := false
+	err := r.db.WithContext(ctx).Transaction(func(tx *gorm.DB) error {
+		res := tx.Clauses(clause.OnConflict{DoNothing: true}).Create(&models.WatchlistView{WatchlistID: watchlistID, ViewerKey: viewerKey, Bucket: bucket})
+		if res.Error != nil || res.RowsAffected == 0 {
+			return res.Error
+		}
+		counted = true
+		return tx.Model(&models.Watchlist{}).Where("id = ?", watchlistID).UpdateColumn("view_count", gorm.Expr("view_count + 1")).Error
+	})
+	return counted, err
+}
+
+// PurgeViews deletes view rows created before cutoff. view_count keeps the
+// views they counted; the rows only serve dedupe and drift checks.
+func (r *GormWatchlistRepository) PurgeViews(ctx context.Context, cutoff time.Time) (int64, error) {
+	res := r.db.WithContext(ctx).Where("created_at < ?", cutoff).Delete(&models.WatchlistView{})
+	return res.RowsAffected, res.Error
+}
+
+// smartSorts maps SmartRules.Sort to ORDER BY clauses over the movies table.
+var smartSorts = map[string]string{
+	"year_desc":      "movies.year DESC NULLS LAST, movies.title ASC",
+	"year_asc":       "movies.year ASC NULLS LAST, movies.title ASC",
+	"title":          "movies.title ASC",
+	"runtime_asc":    "movies.runtime ASC NULLS LAST, movies.title ASC",
+	"runtime_desc":   "movies.runtime DESC NULLS LAST, movies.title ASC",
+	"recently_added": "movies.created_at DESC",
+}
+
+const defaultSmartLimit = 100
+
+// ResolveSmartRules returns the IDs of cached movies matching rules, in list
+// order. User-state rules are evaluated for ownerID. Series are left out.
+func (r *GormWatchlistRepository) ResolveSmartRules(ctx context.Context, ownerID string, rules *models.SmartRules) ([]uuid.UUID, error) {
+	q := r.db.WithContext(ctx).Model(&models.Movie{}).Select("movies.id").Where("movies.media_type = ?", models.MediaMovie)
+	if rules == nil {
+		rules = &models.SmartRules{}
+	}
+	if len(rules.Genres) > 0 {
+		q = q.Where("EXISTS (SELECT 1 FROM jsonb_array_elements_text(COALESCE(movies.genres, '[]'::jsonb)) g WHERE lower(g) IN ?)", lowerAll(rules.Genres))
+	}
+	if len(rules.ExcludeGenres) > 0 {
+		q = q.Where("NOT EXISTS (SELECT 1 FROM jsonb_array_elements_text(COALESCE(movies.genres, '[]'::jsonb)) g WHERE lower(g) IN ?)", lowerAll(rules.ExcludeGenres))
+	}
+	if rules.YearFrom > 0 {
+		q = q.Where("movies.year >= ?", rules.YearFrom)
+	}
+	if rules.YearTo > 0 {
+		q = q.Where("movies.year <= ?", rules.YearTo)
+	}
+	if rules.RuntimeMin > 0 {
+		q = q.Where("movies.runtime >= ?", rules.RuntimeMin)
+	}
+	if rules.RuntimeMax > 0 {
+		// runtime 0 means TMDb didn't report one
+		q = q.Where("movies.runtime > 0 AND movies.runtime <= ?", rules.RuntimeMax)
+	}
+	if rules.Watched != nil {
+		cond := "EXISTS (SELECT 1 FROM watch_logs wl WHERE wl.user_id = ? AND wl.movie_id = movies.id)"
+		if !*rules.Watched {
+			cond = "NOT " + cond
+		}
+		q = q.Where(cond, ownerID)
+	}
+	if rules.Rated != nil {
+		cond := "EXISTS (SELECT 1 FROM reviews rv WHERE rv.user_id = ? AND rv.movie_id = movies.id AND rv.deleted_at IS NULL)"
+		if !*rules.Rated {
+			cond = "NOT " + cond
+		}
+		q = q.Where(cond, ownerID)
+	}
+	if rules.MinRating > 0 {
+		q = q.Where("EXISTS (SELECT 1 FROM reviews rv WHERE rv.user_id = ? AND rv.movie_id = movies.id AND rv.deleted_at IS NULL AND rv.rating >= ?)", ownerID, rules.MinRating)
+	}
+	if rules.InWatchlist != nil {
+		q = q.Where("EXISTS (SELECT 1 FROM watchlist_items wi WHERE wi.watchlist_id = ? AND wi.movie_id = movies.id)", *rules.InWatchlist)
+	}
+	if rules.NotInWatchlist != nil {
+		q = q.Where("NOT EXISTS (SELECT 1 FROM watchlist_items wi WHERE wi.watchlist_id = ? AND wi.movie_id = movies.id)", *rules.NotInWatchlist)
+	}
+	order, ok := smartSorts[rules.Sort]
+	if !ok {
+		order = smartSorts["year_desc"]
+	}
+	limit := rules.Limit
+	if limit <= 0 {
+		limit = defaultSmartLimit
+	}
+	var ids []uuid.UUID
+	if err := q.Order(order).Limit(limit).Pluck("movies.id", &ids).Error; err != nil {
 		return nil, err
 	}
-	return out, nil
+	return ids, nil
+}
+
+// ClaimRefresh stamps refreshed_at if it still holds seen (nil for never
+// refreshed), reporting whether it did. Of several readers finding a smart
+// list stale at once, only the one that wins the claim recomputes it.
+func (r *GormWatchlistRepository) ClaimRefresh(ctx context.Context, watchlistID uuid.UUID, seen *time.Time) (bool, error) {
+	q := r.db.WithContext(ctx).Model(&models.Watchlist{}).Where("id = ?", watchlistID)
+	if seen == nil {
+		q = q.Where("refreshed_at IS NULL")
+	} else {
+		q = q.Where("refreshed_at = ?", *seen)
+	}
+	// UpdateColumns: a refresh isn't an edit, so leave updated_at alone.
+	res := q.UpdateColumns(map[string]any{"refreshed_at": time.Now()})
+	return res.RowsAffected > 0, res.Error
+}
+
+// MaterializeItems makes a smart list's items match movieIDs (in order) and
+// stamps refreshed_at. Movies already on the list keep their row, so their
+// added_at and note survive a refresh.
+func (r *GormWatchlistRepository) MaterializeItems(ctx context.Context, watchlistID uuid.UUID, movieIDs []uuid.UUID) error {
+	return r.db.WithContext(ctx).Transaction(func(tx *gorm.DB) error {
+		var existing []models.WatchlistItem
+		if err := tx.Select("id", "movie_id", "position").Where("watchlist_id = ?", watchlistID).Find(&existing).Error; err != nil {
+			return err
+		}
+		want := make(map[uuid.UUID]int, len(movieIDs))
+		for i, id := range movieIDs {
+			if _, ok := want[id]; !ok {
+				want[id] = i
+			}
+		}
+		kept := make(map[uuid.UUID]bool, len(existing))
+		var removed []uuid.UUID
+		for _, it := range existing {
+			pos, ok := want[it.MovieID]
+			if !ok || kept[it.MovieID] {
+				removed = append(removed, it.ID)
+				continue
+			}
+			kept[it.MovieID] = true
+			if it.Position == pos {
+				continue
+			}
+			if err := tx.Model(&models.WatchlistItem{}).Where("id = ?", it.ID).Update("position", pos).Error; err != nil {
+				return err
+			}
+		}
+		if len(removed) > 0 {
+			if err := tx.Where("id IN ?", removed).Delete(&models.WatchlistItem{}).Error; err != nil {
+				return err
+			}
+		}
+
+		now := time.Now()
+		var added []models.WatchlistItem
+		for i, id := range movieIDs {
+			if kept[id] || want[id] != i {
+				continue
+			}
+			added = append(added, models.WatchlistItem{WatchlistID: watchlistID, MovieID: id, Position: i, AddedAt: now, MediaType: models.MediaMovie})
+		}
+		if len(added) > 0 {
+			if err := tx.CreateInBatches(added, 200).Error; err != nil {
+				return err
+			}
+		}
+		// UpdateColumns: a refresh isn't an edit, so leave updated_at alone.
+		return tx.Model(&models.Watchlist{}).Where("id = ?", watchlistID).UpdateColumns(map[string]any{
+			"item_count":   len(want),
+			"refreshed_at": now,
+		}).Error
+	})
+}
+
+func lowerAll(ss []string) []string {
+	out := make([]string, 0, len(ss))
+	for _, s := range ss {
+		out = append(out, strings.ToLower(strings.TrimSpace(s)))
+	}
+	return out
+}
+
+// CoverPosters returns the poster paths of the first n items that have one,
+// in list order.
+func (r *GormWatchlistRepository) CoverPosters(ctx context.Context, watchlistID string, n int) ([]string, error) {
+	var paths []string
+	err := r.db.WithContext(ctx).Model(&models.WatchlistItem{}).
+		Joins("JOIN movies ON movies.id = watchlist_items.movie_id").
+		Where("watchlist_items.watchlist_id = ? AND COALESCE(movies.poster_url, '') <> ''", watchlistID).
+		Order("watchlist_items.position ASC").
+		Limit(n).
+		Pluck("movies.poster_url", &paths).Error
+	return paths, err
+}
+
+// SetCover points the watchlist at a new cover and reports how many rows it
+// changed. A generated cover (custom false) never replaces an uploaded one.
+func (r *GormWatchlistRepository) SetCover(ctx context.Context, watchlistID, url, key string, custom bool) (int64, error) {
+	q := r.db.WithContext(ctx).Model(&models.Watchlist{}).Where("id = ?", watchlistID)
+	if !custom {
+		q = q.Where("cover_custom = FALSE")
+	}
+	// UpdateColumns: a new cover isn't an edit of the list.
+	res := q.UpdateColumns(map[string]any{"cover_url": url, "cover_key": key, "cover_custom": custom})
+	return res.RowsAffected, res.Error
+}
+
+// ResetCover drops the current cover, custom or not.
+func (r *GormWatchlistRepository) ResetCover(ctx context.Context, watchlistID string) error {
+	return r.db.WithContext(ctx).Model(&models.Watchlist{}).Where("id = ?", watchlistID).
+		UpdateColumns(map[string]any{"cover_url": "", "cover_key": "", "cover_custom": false}).Error
 }
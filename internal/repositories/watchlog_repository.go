@@ -0,0 +1,101 @@
+package repositories
+
+import (
+	"context"
+	"time"
+
+	"github.com/google/uuid"
+	"gorm.io/gorm"
+
+	"github.com/Dubjay18/scenee/internal/models"
+)
+
+type WatchLogRepository interface {
+	Create(ctx context.Context, log *models.WatchLog) error
+	Delete(ctx context.Context, id, userID string) error
+	HasWatched(ctx context.Context, userID string, movieID uuid.UUID) (bool, error)
+	ListByUser(ctx context.Context, userID string, from, to time.Time) ([]models.WatchLog, error)
+	WatchedMovieIDs(ctx context.Context, userID string, movieIDs []uuid.UUID) (map[uuid.UUID]bool, error)
+	WatchedCounts(ctx context.Context, userID string, watchlistIDs []uuid.UUID) (map[uuid.UUID]int, error)
+}
+
+type GormWatchLogRepository struct {
+	db *gorm.DB
+}
+
+func NewWatchLogRepository(db *gorm.DB) *GormWatchLogRepository {
+	return &GormWatchLogRepository{db: db}
+}
+
+func (r *GormWatchLogRepository) Create(ctx context.Context, log *models.WatchLog) error {
+	return r.db.WithContext(ctx).Create(log).Error
+}
+
+func (r *GormWatchLogRepository) Delete(ctx context.Context, id, userID string) error {
+	res := r.db.WithContext(ctx).Where("id = ? AND user_id = ?", id, userID).Delete(&models.WatchLog{})
+	if res.Error != nil {
+		return res.Error
+	}
+	if res.RowsAffected == 0 {
+		return gorm.ErrRecordNotFound
+	}
+	return nil
+}
+
+func (r *GormWatchLogRepository) HasWatched(ctx context.Context, userID string, movieID uuid.UUID) (bool, error) {
+	var count int64
+	err := r.db.WithContext(ctx).Model(&models.WatchLog{}).Where("user_id = ? AND movie_id = ?", userID, movieID).Count(&count).Error
+	return count > 0, err
+}
+
+// ListByUser returns diary entries with watched_on in [from, to), newest first.
+func (r *GormWatchLogRepository) ListByUser(ctx context.Context, userID string, from, to time.Time) ([]models.WatchLog, error) {
+	var logs []models.WatchLog
+	err := r.db.WithContext(ctx).Preload("Movie").Preload("Review").
+		Where("user_id = ? AND watched_on >= ? AND watched_on < ?", userID, from, to).
+		Order("watched_on DESC, created_at DESC").
+		Find(&logs).Error
+	return logs, err
+}
+
+func (r *GormWatchLogRepository) WatchedMovieIDs(ctx context.Context, userID string, movieIDs []uuid.UUID) (map[uuid.UUID]bool, error) {
+	out := make(map[uuid.UUID]bool, len(movieIDs))
+	if len(movieIDs) == 0 {
+		return out, nil
+	}
+	var ids []uuid.UUID
+	if err := r.db.WithContext(ctx).Model(&models.WatchLog{}).Distinct("movie_id").
+		Where("user_id = ? AND movie_id IN ?", userID, movieIDs).
+		Pluck("movie_id", &ids).Error; err != nil {
+		return nil, err
+	}
+	for _, id := range ids {
+		out[id] = true
+	}
+	return out, nil
+}
+
+// WatchedCounts returns, per watchlist, how many of its items the user has watched.
+func (r *GormWatchLogRepository) WatchedCounts(ctx context.Context, userID string, watchlistIDs []uuid.UUID) (map[uuid.UUID]int, error) {
+	out := make(map[uuid.UUID]int, len(watchlistIDs))
+	if len(watchlistIDs) == 0 {
+		return out, nil
+	}
+	var rows []struct {
+		WatchlistID uuid.UUID
+		Watched     int
+	}
+	err := r.db.WithContext(ctx).Model(&models.WatchlistItem{}).
+		Select("watchlist_items.watchlist_id, COUNT(*) AS watched").
+		Where("watchlist_items.watchlist_id IN ?", watchlistIDs).
+		Where("EXISTS (SELECT 1 FROM watch_logs wl WHERE wl.user_id = ? AND wl.movie_id = watchlist_items.movie_id)", userID).
+		Group("watchlist_items.watchlist_id").
+		Scan(&rows).Error
+	if err != nil {
+		return nil, err
+	}
+	for _, row := range rows {
+		out[row.WatchlistID] = row.Watched
+	}
+	return out, nil
+}
@@ -40,6 +40,10 @@ func (s *AuthService) Register(ctx context.Context, email, username, password st
 	if _, err := s.usvc.GetByEmail(ctx, email); err == nil {
 		return nil, ErrUserExists
 	}
+	// Usernames are unique ignoring case
+	if _, err := s.usvc.GetByUsername(ctx, username); err == nil {
+		return nil, ErrUserExists
+	}
 
 	// Hash password
 	hashed, err := bcrypt.GenerateFromPassword([]byte(password), bcrypt.DefaultCost)
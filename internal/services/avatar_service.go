@@ -0,0 +1,118 @@
+package services
+
+import (
+	"bytes"
+	"context"
+	"errors"
+	"fmt"
+	"io"
+	"log"
+
+	"github.com/google/uuid"
+
+	"github.com/Dubjay18/scenee/internal/repositories"
+	"github.com/Dubjay18/scenee/internal/storage"
+	"github.com/Dubjay18/scenee/internal/uploads"
+)
+
+// AvatarSizes are the square renditions stored for every uploaded avatar.
+// The user's avatar_url points at the largest one; the others live next to
+// it as {key}-{size}.jpg.
+var AvatarSizes = []uploads.Size{
+	{Name: "64", Width: 64, Height: 64},
+	{Name: "128", Width: 128, Height: 128},
+	{Name: "256", Width: 256, Height: 256},
+}
+
+// AvatarService stores uploaded profile pictures in the blob store.
+type AvatarService struct {
+	users repositories.UserRepository
+	store storage.BlobStore
+}
+
+func NewAvatarService(users repositories.UserRepository, store storage.BlobStore) *AvatarService {
+	return &AvatarService{users: users, store: store}
+}
+
+// Avatar is the result of an upload: the URL of every size.
+type Avatar struct {
+	URL   string            `json:"avatar_url"`
+	Sizes map[string]string `json:"sizes"`
+}
+
+// Upload validates the image, stores every size under a fresh key and
+// points the user at it. The previous upload, if any, is removed.
+func (s *AvatarService) Upload(ctx context.Context, userID string, r io.Reader) (*Avatar, error) {
+	if userID == "" {
+		return nil, ErrUnauthorized
+	}
+	user, err := s.users.GetByID(ctx, userID)
+	if err != nil {
+		return nil, err
+	}
+	img, err := uploads.DecodeImage(r, uploads.MaxImageBytes)
+	if err != nil {
+		return nil, err
+	}
+
+	key := fmt.Sprintf("avatars/%s-%s", userID, uuid.NewString()[:8])
+	out := &Avatar{Sizes: make(map[string]string, len(AvatarSizes))}
+	for _, size := range AvatarSizes {
+		data, err := uploads.Render(img, size)
+		if err != nil {
+			return nil, err
+		}
+		blob := avatarBlob(key, size)
+		if err := s.store.Put(ctx, blob, bytes.NewReader(data), "image/jpeg"); err != nil {
+			s.deleteBlobs(ctx, key)
+			return nil, err
+		}
+		out.Sizes[size.Name] = s.store.URL(blob)
+	}
+	out.URL = out.Sizes[AvatarSizes[len(AvatarSizes)-1].Name]
+
+	if err := s.users.Update(ctx, userID, map[string]interface{}{"avatar_url": out.URL, "avatar_key": key}); err != nil {
+		s.deleteBlobs(ctx, key)
+		return nil, err
+	}
+	s.deleteBlobs(ctx, user.AvatarKey)
+	return out, nil
+}
+
+// Remove clears the avatar and deletes an uploaded one.
+func (s *AvatarService) Remove(ctx context.Context, userID string) error {
+	return s.SetExternal(ctx, userID, "")
+}
+
+// SetExternal points the avatar at a URL the user supplied, dropping any
+// uploaded avatar.
+func (s *AvatarService) SetExternal(ctx context.Context, userID, url string) error {
+	if userID == "" {
+		return ErrUnauthorized
+	}
+	user, err := s.users.GetByID(ctx, userID)
+	if err != nil {
+		return err
+	}
+	if err := s.users.Update(ctx, userID, map[string]interface{}{"avatar_url": url, "avatar_key": ""}); err != nil {
+		return err
+	}
+	s.deleteBlobs(ctx, user.AvatarKey)
+	return nil
+}
+
+func (s *AvatarService) deleteBlobs(ctx context.Context, key string) {
+	if key == "" {
+		return
+	}
+	for _, size := range AvatarSizes {
+		blob := avatarBlob(key, size)
+		if err := s.store.Delete(ctx, blob); err != nil && !errors.Is(err, storage.ErrNotFound) {
+			log.Printf("Failed to delete blob %s: %v", blob, err)
+		}
+	}
+}
+
+func avatarBlob(key string, size uploads.Size) string {
+	return key + "-" + size.Name + ".jpg"
+}
@@ -0,0 +1,124 @@
+package services
+
+import (
+	"context"
+	"errors"
+
+	"github.com/google/uuid"
+	"gorm.io/gorm"
+
+	"github.com/Dubjay18/scenee/internal/repositories"
+)
+
+var (
+	// ErrBlocked is returned for interactions between users where either has
+	// blocked the other.
+	ErrBlocked   = errors.New("you can't interact with this user")
+	ErrBlockSelf = errors.New("cannot block or mute yourself")
+)
+
+// BlockService manages blocks and mutes. Other services enforce them through
+// the repository directly; see checkBlocked and hiddenFrom.
+type BlockService struct {
+	blocks repositories.BlockRepository
+	users  repositories.UserRepository
+}
+
+func NewBlockService(blocks repositories.BlockRepository, users repositories.UserRepository) *BlockService {
+	return &BlockService{blocks: blocks, users: users}
+}
+
+// target checks that targetID is someone else who exists.
+func (s *BlockService) target(ctx context.Context, userID, targetID string) error {
+	if userID == "" {
+		return ErrUnauthorized
+	}
+	if userID == targetID {
+		return ErrBlockSelf
+	}
+	if _, err := uuid.Parse(targetID); err != nil {
+		return gorm.ErrRecordNotFound
+	}
+	_, err := s.users.GetByID(ctx, targetID)
+	return err
+}
+
+// Block hides the two users from each other and removes any follows
+// between them.
+func (s *BlockService) Block(ctx context.Context, userID, targetID string) error {
+	if err := s.target(ctx, userID, targetID); err != nil {
+		return err
+	}
+	return s.blocks.Block(ctx, userID, targetID)
+}
+
+func (s *BlockService) Unblock(ctx context.Context, userID, targetID string) error {
+	if userID == "" {
+		return ErrUnauthorized
+	}
+	return s.blocks.Unblock(ctx, userID, targetID)
+}
+
+// Mute hides targetID from the user's feed and notifications; everything
+// else keeps working both ways.
+func (s *BlockService) Mute(ctx context.Context, userID, targetID string) error {
+	if err := s.target(ctx, userID, targetID); err != nil {
+		return err
+	}
+	return s.blocks.Mute(ctx, userID, targetID)
+}
+
+func (s *BlockService) Unmute(ctx context.Context, userID, targetID string) error {
+	if userID == "" {
+		return ErrUnauthorized
+	}
+	return s.blocks.Unmute(ctx, userID, targetID)
+}
+
+func (s *BlockService) Blocked(ctx context.Context, userID string) ([]PublicUser, error) {
+	users, err := s.blocks.ListBlocked(ctx, userID)
+	if err != nil {
+		return nil, err
+	}
+	return publicUsers(users), nil
+}
+
+func (s *BlockService) Muted(ctx context.Context, userID string) ([]PublicUser, error) {
+	users, err := s.blocks.ListMuted(ctx, userID)
+	if err != nil {
+		return nil, err
+	}
+	return publicUsers(users), nil
+}
+
+// checkBlocked returns ErrBlocked when either user has blocked the other.
+func checkBlocked(ctx context.Context, blocks repositories.BlockRepository, a, b string) error {
+	if a == "" || b == "" || a == b {
+		return nil
+	}
+	blocked, err := blocks.EitherBlocked(ctx, a, b)
+	if err != nil {
+		return err
+	}
+	if blocked {
+		return ErrBlocked
+	}
+	return nil
+}
+
+// hiddenFrom lists the users viewer must not see, nil for anonymous viewers.
+// With muted set, muted users are included too.
+func hiddenFrom(ctx context.Context, blocks repositories.BlockRepository, viewer string, muted bool) ([]uuid.UUID, error) {
+	if viewer == "" {
+		return nil, nil
+	}
+	ids, err := blocks.HiddenIDs(ctx, viewer)
+	if err != nil || !muted {
+		return ids, err
+	}
+	mutedIDs, err := blocks.MutedIDs(ctx, viewer)
+	if err != nil {
+		return nil, err
+	}
+	return append(ids, mutedIDs...), nil
+}
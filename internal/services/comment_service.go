@@ -0,0 +1,327 @@
+package services
+
+import (
+	"context"
+	"errors"
+	"log"
+	"regexp"
+	"strings"
+	"time"
+
+	"github.com/google/uuid"
+
+	"github.com/Dubjay18/scenee/internal/models"
+	"github.com/Dubjay18/scenee/internal/repositories"
+)
+
+var (
+	ErrCommentsDisabled = errors.New("comments are turned off for this watchlist")
+	ErrNestedReply      = errors.New("replies can only be made to top-level comments")
+	ErrEmptyComment     = errors.New("comment body is empty")
+)
+
+const (
+	defaultCommentLimit = 20
+	maxCommentLimit     = 100
+	// inlineReplies is how many replies ride along with each top-level comment.
+	inlineReplies = 3
+	// maxMentions bounds the notifications a single comment can send.
+	maxMentions = 10
+)
+
+// mentionPattern matches @username where the @ is not part of a word or an
+// email address.
+var mentionPattern = regexp.MustCompile(`(?:^|[^\w@.])@([A-Za-z0-9_.-]{3,50})`)
+
+// CommentService runs the discussion on watchlists: one level of replies,
+// @mentions and the owner's switch to turn comments off.
+type CommentService struct {
+	comments      repositories.CommentRepository
+	watchlists    repositories.WatchlistRepository
+	users         repositories.UserRepository
+	blocks        repositories.BlockRepository
+	notifications repositories.NotificationRepository
+}
+
+func NewCommentService(comments repositories.CommentRepository, watchlists repositories.WatchlistRepository, users repositories.UserRepository, blocks repositories.BlockRepository, notifications repositories.NotificationRepository) *CommentService {
+	return &CommentService{comments: comments, watchlists: watchlists, users: users, blocks: blocks, notifications: notifications}
+}
+
+// Comment is a top-level comment with its first few replies.
+type Comment struct {
+	repositories.CommentView
+	Replies []repositories.CommentView `json:"replies"`
+}
+
+type CommentPage struct {
+	Comments        []Comment `json:"comments"`
+	CommentsEnabled bool      `json:"comments_enabled"`
+	Page            int       `json:"page"`
+	Limit           int       `json:"limit"`
+	Total           int64     `json:"total"`
+}
+
+type ReplyPage struct {
+	Replies []repositories.CommentView `json:"replies"`
+	Page    int                        `json:"page"`
+	Limit   int                        `json:"limit"`
+	Total   int64                      `json:"total"`
+}
+
+// visibleList loads a watchlist the viewer is allowed to see, by the same
+// rules as the list itself: see checkListVisible.
+func (s *CommentService) visibleList(ctx context.Context, viewer, watchlistID string) (*models.Watchlist, error) {
+	wl, err := s.watchlists.GetSummary(ctx, watchlistID)
+	if err != nil {
+		return nil, err
+	}
+	if err := checkListVisible(ctx, s.watchlists, wl, viewer); err != nil {
+		return nil, err
+	}
+	return wl, nil
+}
+
+func commentPaging(page, limit int) (int, int) {
+	if page < 1 {
+		page = 1
+	}
+	if limit <= 0 {
+		limit = defaultCommentLimit
+	}
+	return page, min(limit, maxCommentLimit)
+}
+
+// List returns a page of top-level comments, each with its oldest replies.
+// Comments by users blocked either way are left out.
+func (s *CommentService) List(ctx context.Context, viewer, watchlistID string, page, limit int) (*CommentPage, error) {
+	wl, err := s.visibleList(ctx, viewer, watchlistID)
+	if err != nil {
+		return nil, err
+	}
+	hidden, err := hiddenFrom(ctx, s.blocks, viewer, false)
+	if err != nil {
+		return nil, err
+	}
+	page, limit = commentPaging(page, limit)
+	top, total, err := s.comments.ListTopLevel(ctx, watchlistID, hidden, limit, (page-1)*limit)
+	if err != nil {
+		return nil, err
+	}
+	ids := make([]uuid.UUID, 0, len(top))
+	for _, c := range top {
+		if c.ReplyCount > 0 {
+			ids = append(ids, c.ID)
+		}
+	}
+	replies, err := s.comments.FirstReplies(ctx, ids, hidden, inlineReplies)
+	if err != nil {
+		return nil, err
+	}
+	byParent := make(map[uuid.UUID][]repositories.CommentView, len(ids))
+	for _, r := range replies {
+		byParent[*r.ParentID] = append(byParent[*r.ParentID], r)
+	}
+	out := &CommentPage{Comments: make([]Comment, 0, len(top)), CommentsEnabled: wl.CommentsEnabled, Page: page, Limit: limit, Total: total}
+	for _, c := range top {
+		rs := byParent[c.ID]
+		if rs == nil {
+			rs = []repositories.CommentView{}
+		}
+		out.Comments = append(out.Comments, Comment{CommentView: c, Replies: rs})
+	}
+	return out, nil
+}
+
+// Replies pages through all replies of a top-level comment.
+func (s *CommentService) Replies(ctx context.Context, viewer, watchlistID, commentID string, page, limit int) (*ReplyPage, error) {
+	if _, err := s.visibleList(ctx, viewer, watchlistID); err != nil {
+		return nil, err
+	}
+	parent, err := s.comments.Get(ctx, watchlistID, commentID)
+	if err != nil {
+		return nil, err
+	}
+	if parent.ParentID != nil {
+		return nil, ErrNestedReply
+	}
+	hidden, err := hiddenFrom(ctx, s.blocks, viewer, false)
+	if err != nil {
+		return nil, err
+	}
+	page, limit = commentPaging(page, limit)
+	replies, total, err := s.comments.ListReplies(ctx, commentID, hidden, limit, (page-1)*limit)
+	if err != nil {
+		return nil, err
+	}
+	return &ReplyPage{Replies: replies, Page: page, Limit: limit, Total: total}, nil
+}
+
+// Create posts a comment, or a reply when parentID is set, and notifies the
+// list owner, the parent's author and anyone @mentioned. Users blocked
+// either way by the owner or the parent's author can't take part.
+func (s *CommentService) Create(ctx context.Context, author, watchlistID, parentID, body string) (*repositories.CommentView, error) {
+	if author == "" {
+		return nil, ErrUnauthorized
+	}
+	if body = strings.TrimSpace(body); body == "" {
+		return nil, ErrEmptyComment
+	}
+	wl, err := s.visibleList(ctx, author, watchlistID)
+	if err != nil {
+		return nil, err
+	}
+	if !wl.CommentsEnabled {
+		return nil, ErrCommentsDisabled
+	}
+	if err := checkBlocked(ctx, s.blocks, author, wl.OwnerID); err != nil {
+		return nil, err
+	}
+	comment := &models.WatchlistComment{
+		WatchlistID: wl.ID,
+		AuthorID:    uuid.MustParse(author),
+		Body:        body,
+	}
+	var parent *models.WatchlistComment
+	if parentID != "" {
+		if parent, err = s.comments.Get(ctx, watchlistID, parentID); err != nil {
+			return nil, err
+		}
+		if parent.ParentID != nil {
+			return nil, ErrNestedReply
+		}
+		if err := checkBlocked(ctx, s.blocks, author, parent.AuthorID.String()); err != nil {
+			return nil, err
+		}
+		comment.ParentID = &parent.ID
+	}
+	if err := s.comments.Create(ctx, comment); err != nil {
+		return nil, err
+	}
+
+	notified := map[uuid.UUID]bool{comment.AuthorID: true}
+	if parent != nil && !notified[parent.AuthorID] {
+		s.notify(ctx, parent.AuthorID, comment.AuthorID, models.NotificationReply, wl.ID)
+		notified[parent.AuthorID] = true
+	}
+	if owner := uuid.MustParse(wl.OwnerID); !notified[owner] {
+		s.notify(ctx, owner, comment.AuthorID, models.NotificationComment, wl.ID)
+		notified[owner] = true
+	}
+	s.notifyMentions(ctx, wl, comment.AuthorID, mentions(comment.Body), notified)
+	return s.comments.GetView(ctx, comment.ID)
+}
+
+// Edit changes the body of the author's own comment. Only people newly
+// mentioned by the edit are notified.
+func (s *CommentService) Edit(ctx context.Context, author, watchlistID, commentID, body string) (*repositories.CommentView, error) {
+	if author == "" {
+		return nil, ErrUnauthorized
+	}
+	if body = strings.TrimSpace(body); body == "" {
+		return nil, ErrEmptyComment
+	}
+	wl, err := s.visibleList(ctx, author, watchlistID)
+	if err != nil {
+		return nil, err
+	}
+	comment, err := s.comments.Get(ctx, watchlistID, commentID)
+	if err != nil {
+		return nil, err
+	}
+	if comment.AuthorID.String() != author {
+		return nil, ErrForbidden
+	}
+	if err := s.comments.UpdateBody(ctx, comment.ID, body, time.Now()); err != nil {
+		return nil, err
+	}
+	notified := map[uuid.UUID]bool{comment.AuthorID: true}
+	var added []string
+	before := mentions(comment.Body)
+	for _, m := range mentions(body) {
+		if !containsFold(before, m) {
+			added = append(added, m)
+		}
+	}
+	s.notifyMentions(ctx, wl, comment.AuthorID, added, notified)
+	return s.comments.GetView(ctx, comment.ID)
+}
+
+// Delete removes a comment (and its replies). Authors can delete their own
+// comments; the list owner can delete any comment on the list.
+func (s *CommentService) Delete(ctx context.Context, userID, watchlistID, commentID string) error {
+	if userID == "" {
+		return ErrUnauthorized
+	}
+	wl, err := s.visibleList(ctx, userID, watchlistID)
+	if err != nil {
+		return err
+	}
+	comment, err := s.comments.Get(ctx, watchlistID, commentID)
+	if err != nil {
+		return err
+	}
+	if comment.AuthorID.String() != userID && wl.OwnerID != userID {
+		return ErrForbidden
+	}
+	return s.comments.Delete(ctx, comment.ID)
+}
+
+// notifyMentions notifies mentioned users who can see the list, aren't
+// blocked either way by the actor and haven't been notified about this
+// comment already.
+func (s *CommentService) notifyMentions(ctx context.Context, wl *models.Watchlist, actor uuid.UUID, usernames []string, notified map[uuid.UUID]bool) {
+	if len(usernames) == 0 {
+		return
+	}
+	users, err := s.users.GetByUsernames(ctx, usernames)
+	if err != nil {
+		log.Printf("Failed to resolve mentions: %v", err)
+		return
+	}
+	for _, u := range users {
+		if notified[u.ID] {
+			continue
+		}
+		if err := checkListVisible(ctx, s.watchlists, wl, u.ID.String()); err != nil {
+			continue
+		}
+		if err := checkBlocked(ctx, s.blocks, actor.String(), u.ID.String()); err != nil {
+			continue
+		}
+		s.notify(ctx, u.ID, actor, models.NotificationMention, wl.ID)
+		notified[u.ID] = true
+	}
+}
+
+func (s *CommentService) notify(ctx context.Context, recipient, actor uuid.UUID, kind string, entity uuid.UUID) {
+	n := &models.Notification{UserID: recipient, Type: kind, ActorID: actor, EntityID: entity}
+	if err := s.notifications.Create(ctx, n); err != nil {
+		log.Printf("Failed to create %s notification: %v", kind, err)
+	}
+}
+
+// mentions extracts the distinct @usernames in body, in order, up to
+// maxMentions.
+func mentions(body string) []string {
+	var out []string
+	for _, m := range mentionPattern.FindAllStringSubmatch(body, -1) {
+		name := strings.TrimRight(m[1], ".-")
+		if len(name) < 3 || containsFold(out, name) {
+			continue
+		}
+		out = append(out, name)
+		if len(out) == maxMentions {
+			break
+		}
+	}
+	return out
+}
+
+func containsFold(list []string, s string) bool {
+	for _, v := range list {
+		if strings.EqualFold(v, s) {
+			return true
+		}
+	}
+	return false
+}
@@ -0,0 +1,69 @@
+package services
+
+import (
+	"context"
+	"log"
+	"time"
+
+	"github.com/google/uuid"
+
+	"github.com/Dubjay18/scenee/internal/repositories"
+)
+
+// reconcileBatch bounds how many drifted watchlists one pass looks at.
+const reconcileBatch = 500
+
+// driftWindow is how far back a watchlist's views or edits make it worth
+// checking; it comfortably spans the reconcile interval.
+const driftWindow = 24 * time.Hour
+
+// CounterService keeps the denormalized watchlist counters honest.
+type CounterService struct {
+	counters repositories.CounterRepository
+}
+
+func NewCounterService(counters repositories.CounterRepository) *CounterService {
+	return &CounterService{counters: counters}
+}
+
+type DriftReport struct {
+	Drifted []repositories.CounterDrift `json:"drifted"`
+	Fixed   bool                        `json:"fixed"`
+}
+
+// Drift reports counters that disagree with their source tables without
+// changing anything.
+func (s *CounterService) Drift(ctx context.Context) (*DriftReport, error) {
+	drift, err := s.counters.FindDrift(ctx, time.Now().Add(-driftWindow), reconcileBatch)
+	if err != nil {
+		return nil, err
+	}
+	return &DriftReport{Drifted: drift}, nil
+}
+
+// Reconcile finds drifted counters, recomputes them and reports what was off.
+func (s *CounterService) Reconcile(ctx context.Context) (*DriftReport, error) {
+	report, err := s.Drift(ctx)
+	if err != nil {
+		return nil, err
+	}
+	if len(report.Drifted) == 0 {
+		return report, nil
+	}
+	ids := make([]uuid.UUID, 0, len(report.Drifted))
+	for _, d := range report.Drifted {
+		ids = append(ids, d.WatchlistID)
+	}
+	if err := s.counters.Reconcile(ctx, ids); err != nil {
+		return nil, err
+	}
+	report.Fixed = true
+	log.Printf("reconciled counters on %d watchlists", len(ids))
+	return report, nil
+}
+
+// ReconcileJob adapts Reconcile for jobs.Every.
+func (s *CounterService) ReconcileJob(ctx context.Context) error {
+	_, err := s.Reconcile(ctx)
+	return err
+}
@@ -0,0 +1,243 @@
+package services
+
+import (
+	"bytes"
+	"context"
+	"crypto/sha256"
+	"errors"
+	"fmt"
+	"image"
+	"io"
+	"log"
+	"net/http"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/google/uuid"
+
+	"github.com/Dubjay18/scenee/internal/imaging"
+	"github.com/Dubjay18/scenee/internal/models"
+	"github.com/Dubjay18/scenee/internal/repositories"
+	"github.com/Dubjay18/scenee/internal/storage"
+	"github.com/Dubjay18/scenee/internal/tmdb"
+	"github.com/Dubjay18/scenee/internal/uploads"
+)
+
+// Covers are poster-shaped (2:3).
+const (
+	coverWidth  = 600
+	coverHeight = 900
+
+	// coverDebounce coalesces bursts of item edits into one regeneration.
+	coverDebounce  = 5 * time.Second
+	coverTimeout   = 30 * time.Second
+	coverPosterSz  = "w342"
+	maxCoverPoster = 9
+)
+
+// CoverService keeps watchlist covers up to date: a collage of the first
+// posters, unless the owner uploaded a cover of their own.
+type CoverService struct {
+	watchlists repositories.WatchlistRepository
+	store      storage.BlobStore
+	http       *http.Client
+
+	mu      sync.Mutex
+	pending map[uuid.UUID]*time.Timer
+}
+
+func NewCoverService(watchlists repositories.WatchlistRepository, store storage.BlobStore) *CoverService {
+	return &CoverService{
+		watchlists: watchlists,
+		store:      store,
+		http:       &http.Client{Timeout: 10 * time.Second},
+		pending:    make(map[uuid.UUID]*time.Timer),
+	}
+}
+
+// Touch schedules a cover regeneration for a list whose items changed.
+// Repeated touches within coverDebounce collapse into one run.
+func (s *CoverService) Touch(id uuid.UUID) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	if t, ok := s.pending[id]; ok {
+		t.Reset(coverDebounce)
+		return
+	}
+	s.pending[id] = time.AfterFunc(coverDebounce, func() {
+		s.mu.Lock()
+		delete(s.pending, id)
+		s.mu.Unlock()
+		ctx, cancel := context.WithTimeout(context.Background(), coverTimeout)
+		defer cancel()
+		if err := s.Regenerate(ctx, id.String()); err != nil {
+			log.Printf("Failed to regenerate cover for watchlist %s: %v", id, err)
+		}
+	})
+}
+
+// Regenerate rebuilds the collage cover. The blob key is derived from the
+// posters used, so an unchanged first page is a no-op.
+func (s *CoverService) Regenerate(ctx context.Context, id string) error {
+	wl, err := s.watchlists.GetSummary(ctx, id)
+	if err != nil {
+		return err
+	}
+	if wl.CoverCustom {
+		return nil
+	}
+	paths, err := s.watchlists.CoverPosters(ctx, id, maxCoverPoster)
+	if err != nil {
+		return err
+	}
+	if len(paths) == 0 {
+		if wl.CoverKey == "" {
+			return nil
+		}
+		n, err := s.watchlists.SetCover(ctx, id, "", "", false)
+		if err != nil {
+			return err
+		}
+		if n > 0 {
+			s.deleteBlob(ctx, wl.CoverKey)
+		}
+		return nil
+	}
+	n := imaging.Grid(len(paths))
+	paths = paths[:n*n]
+	sum := sha256.Sum256([]byte(strings.Join(paths, "|")))
+	key := fmt.Sprintf("covers/%s-%x.jpg", id, sum[:6])
+	if key == wl.CoverKey {
+		return nil
+	}
+
+	posters, err := s.fetchPosters(ctx, paths)
+	if err != nil {
+		return err
+	}
+	var buf bytes.Buffer
+	if err := imaging.EncodeJPEG(&buf, imaging.Collage(posters, coverWidth, coverHeight)); err != nil {
+		return err
+	}
+	return s.replace(ctx, wl, key, &buf, false)
+}
+
+// UploadCustom replaces the cover with an owner-supplied image, cropped to
+// the cover shape and re-encoded without metadata.
+func (s *CoverService) UploadCustom(ctx context.Context, owner, id string, r io.Reader) (*models.Watchlist, error) {
+	if owner == "" {
+		return nil, ErrUnauthorized
+	}
+	if err := s.watchlists.EnsureOwner(ctx, id, owner); err != nil {
+		return nil, err
+	}
+	src, err := uploads.DecodeImage(r, uploads.MaxImageBytes)
+	if err != nil {
+		return nil, err
+	}
+	data, err := uploads.Render(src, uploads.Size{Name: "cover", Width: coverWidth, Height: coverHeight})
+	if err != nil {
+		return nil, err
+	}
+	wl, err := s.watchlists.GetSummary(ctx, id)
+	if err != nil {
+		return nil, err
+	}
+	key := fmt.Sprintf("covers/%s-custom-%s.jpg", id, uuid.NewString()[:8])
+	if err := s.replace(ctx, wl, key, bytes.NewReader(data), true); err != nil {
+		return nil, err
+	}
+	return s.watchlists.GetSummary(ctx, id)
+}
+
+// RemoveCustom drops an uploaded cover and goes back to the generated collage.
+func (s *CoverService) RemoveCustom(ctx context.Context, owner, id string) (*models.Watchlist, error) {
+	if owner == "" {
+		return nil, ErrUnauthorized
+	}
+	if err := s.watchlists.EnsureOwner(ctx, id, owner); err != nil {
+		return nil, err
+	}
+	wl, err := s.watchlists.GetSummary(ctx, id)
+	if err != nil {
+		return nil, err
+	}
+	if wl.CoverCustom {
+		if err := s.watchlists.ResetCover(ctx, id); err != nil {
+			return nil, err
+		}
+		s.deleteBlob(ctx, wl.CoverKey)
+	}
+	if err := s.Regenerate(ctx, id); err != nil {
+		return nil, err
+	}
+	return s.watchlists.GetSummary(ctx, id)
+}
+
+// replace stores the new cover, points the list at it and removes the old
+// blob. If the list wasn't updated (an upload landed first, or the list is
+// gone) the new blob is dropped and the old one kept.
+func (s *CoverService) replace(ctx context.Context, wl *models.Watchlist, key string, body io.Reader, custom bool) error {
+	if err := s.store.Put(ctx, key, body, "image/jpeg"); err != nil {
+		return err
+	}
+	n, err := s.watchlists.SetCover(ctx, wl.ID.String(), s.store.URL(key), key, custom)
+	if err != nil {
+		s.deleteBlob(ctx, key)
+		return err
+	}
+	if n == 0 {
+		if key != wl.CoverKey {
+			s.deleteBlob(ctx, key)
+		}
+		return nil
+	}
+	if wl.CoverKey != "" && wl.CoverKey != key {
+		s.deleteBlob(ctx, wl.CoverKey)
+	}
+	return nil
+}
+
+func (s *CoverService) deleteBlob(ctx context.Context, key string) {
+	if err := s.store.Delete(ctx, key); err != nil && !errors.Is(err, storage.ErrNotFound) {
+		log.Printf("Failed to delete blob %s: %v", key, err)
+	}
+}
+
+// fetchPosters downloads all posters concurrently; any failure fails the lot
+// so a half-empty collage is never stored under the full key.
+func (s *CoverService) fetchPosters(ctx context.Context, paths []string) ([]image.Image, error) {
+	out := make([]image.Image, len(paths))
+	errs := make([]error, len(paths))
+	var wg sync.WaitGroup
+	for i, p := range paths {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			out[i], errs[i] = s.fetchPoster(ctx, tmdb.ImageURL(p, coverPosterSz))
+		}()
+	}
+	wg.Wait()
+	if err := errors.Join(errs...); err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (s *CoverService) fetchPoster(ctx context.Context, url string) (image.Image, error) {
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, url, nil)
+	if err != nil {
+		return nil, err
+	}
+	resp, err := s.http.Do(req)
+	if err != nil {
+		return nil, err
+	}
+	defer resp.Body.Close()
+	if resp.StatusCode != http.StatusOK {
+		return nil, fmt.Errorf("poster %s: status %d", url, resp.StatusCode)
+	}
+	img, _, err := image.Decode(io.LimitReader(resp.Body, 5<<20))
+	return img, err
+}
@@ -0,0 +1,144 @@
+package services
+
+import (
+	"context"
+	"errors"
+	"time"
+
+	"github.com/google/uuid"
+	"gorm.io/gorm"
+
+	"github.com/Dubjay18/scenee/internal/domain"
+	"github.com/Dubjay18/scenee/internal/models"
+	"github.com/Dubjay18/scenee/internal/repositories"
+)
+
+var ErrWatchedInFuture = errors.New("watched_on cannot be in the future")
+
+// LogWatchInput describes a diary entry. Either TMDBID or MovieID identifies
+// the movie; Rewatch is inferred from earlier entries when nil.
+type LogWatchInput struct {
+	TMDBID    int
+	MovieID   uuid.UUID
+	WatchedOn time.Time
+	Rewatch   *bool
+	Rating    int
+	Review    string
+	Note      string
+}
+
+type DiaryService struct {
+	logs    repositories.WatchLogRepository
+	reviews repositories.ReviewRepository
+	movies  repositories.MovieRepository
+	msvc    *MovieService
+}
+
+func NewDiaryService(logs repositories.WatchLogRepository, reviews repositories.ReviewRepository, movies repositories.MovieRepository, msvc *MovieService) *DiaryService {
+	return &DiaryService{logs: logs, reviews: reviews, movies: movies, msvc: msvc}
+}
+
+// LogWatch records that userID watched a movie. A rating creates or updates
+// the user's review of that movie and links it to the entry.
+func (s *DiaryService) LogWatch(ctx context.Context, userID string, in LogWatchInput) (*domain.DiaryEntry, error) {
+	if userID == "" {
+		return nil, ErrUnauthorized
+	}
+	watchedOn := in.WatchedOn
+	if watchedOn.IsZero() {
+		watchedOn = time.Now()
+	}
+	// Allow a day of slack for clients ahead of the server's timezone.
+	if watchedOn.After(time.Now().Add(24 * time.Hour)) {
+		return nil, ErrWatchedInFuture
+	}
+
+	movie, err := s.resolveMovie(ctx, in)
+	if err != nil {
+		return nil, err
+	}
+
+	rewatch := false
+	if in.Rewatch != nil {
+		rewatch = *in.Rewatch
+	} else if rewatch, err = s.logs.HasWatched(ctx, userID, movie.ID); err != nil {
+		return nil, err
+	}
+
+	entry := &models.WatchLog{
+		UserID:    uuid.MustParse(userID),
+		MovieID:   movie.ID,
+		WatchedOn: watchedOn,
+		Rewatch:   rewatch,
+		Note:      in.Note,
+		Movie:     *movie,
+	}
+	if in.Rating > 0 {
+		review, err := s.upsertReview(ctx, userID, movie.ID, in.Rating, in.Review)
+		if err != nil {
+			return nil, err
+		}
+		entry.ReviewID = &review.ID
+		entry.Review = review
+	}
+	if err := s.logs.Create(ctx, entry); err != nil {
+		return nil, err
+	}
+	return domain.DiaryEntryFromModel(entry), nil
+}
+
+func (s *DiaryService) resolveMovie(ctx context.Context, in LogWatchInput) (*models.Movie, error) {
+	if in.MovieID != uuid.Nil {
+		return s.movies.GetByID(ctx, in.MovieID.String())
+	}
+	dm, err := s.msvc.GetMovieByTMDBID(ctx, in.TMDBID)
+	if err != nil {
+		return nil, err
+	}
+	return dm.ToModel(), nil
+}
+
+func (s *DiaryService) upsertReview(ctx context.Context, userID string, movieID uuid.UUID, rating int, text string) (*models.Review, error) {
+	existing, err := s.reviews.GetByUserAndMovie(ctx, userID, movieID.String())
+	if err == nil {
+		existing.Rating = rating
+		if text != "" {
+			existing.Review = text
+		}
+		if err := s.reviews.Update(ctx, existing); err != nil {
+			return nil, err
+		}
+		return existing, nil
+	}
+	if !errors.Is(err, gorm.ErrRecordNotFound) {
+		return nil, err
+	}
+	review := &models.Review{UserID: uuid.MustParse(userID), MovieID: movieID, Rating: rating, Review: text}
+	if err := s.reviews.Create(ctx, review); err != nil {
+		return nil, err
+	}
+	return review, nil
+}
+
+func (s *DiaryService) Delete(ctx context.Context, userID, id string) error {
+	if userID == "" {
+		return ErrUnauthorized
+	}
+	return s.logs.Delete(ctx, id, userID)
+}
+
+// Diary returns the user's entries for a year (or a single month when month
+// is 1-12), grouped by month, newest first.
+func (s *DiaryService) Diary(ctx context.Context, userID string, year, month int) ([]domain.DiaryMonth, error) {
+	from := time.Date(year, time.January, 1, 0, 0, 0, 0, time.UTC)
+	to := from.AddDate(1, 0, 0)
+	if month >= 1 && month <= 12 {
+		from = time.Date(year, time.Month(month), 1, 0, 0, 0, 0, time.UTC)
+		to = from.AddDate(0, 1, 0)
+	}
+	logs, err := s.logs.ListByUser(ctx, userID, from, to)
+	if err != nil {
+		return nil, err
+	}
+	return domain.DiaryFromModel(logs), nil
+}
@@ -2,24 +2,96 @@ package services
 
 import (
 	"context"
+	"encoding/base64"
+	"errors"
+	"log"
+	"strings"
+	"time"
+
+	"github.com/google/uuid"
+	"gorm.io/gorm"
 
 	"github.com/Dubjay18/scenee/internal/models"
 	"github.com/Dubjay18/scenee/internal/repositories"
 )
 
+var (
+	ErrFollowSelf    = errors.New("cannot follow yourself")
+	ErrInvalidCursor = errors.New("invalid cursor")
+)
+
+const (
+	defaultFollowLimit = 20
+	maxFollowLimit     = 100
+)
+
+// Outcomes of Follow: private accounts get a request instead of a follower.
+const (
+	FollowStatusFollowing = "following"
+	FollowStatusRequested = "requested"
+)
+
 type FollowService struct {
-	follows repositories.FollowRepository
+	follows       repositories.FollowRepository
+	blocks        repositories.BlockRepository
+	users         repositories.UserRepository
+	notifications repositories.NotificationRepository
 }
 
-func NewFollowService(follows repositories.FollowRepository) *FollowService {
-	return &FollowService{follows: follows}
+func NewFollowService(follows repositories.FollowRepository, blocks repositories.BlockRepository, users repositories.UserRepository, notifications repositories.NotificationRepository) *FollowService {
+	return &FollowService{follows: follows, blocks: blocks, users: users, notifications: notifications}
 }
 
-func (s *FollowService) Follow(ctx context.Context, followerID, followeeID string) error {
-	return s.follows.Follow(ctx, followerID, followeeID)
+// Follow follows a public account straight away and asks a private one for
+// approval. It returns FollowStatusFollowing or FollowStatusRequested.
+func (s *FollowService) Follow(ctx context.Context, followerID, followeeID string) (string, error) {
+	if followerID == "" {
+		return "", ErrUnauthorized
+	}
+	if followerID == followeeID {
+		return "", ErrFollowSelf
+	}
+	if _, err := uuid.Parse(followeeID); err != nil {
+		return "", gorm.ErrRecordNotFound
+	}
+	followee, err := s.users.GetByID(ctx, followeeID)
+	if err != nil {
+		return "", err
+	}
+	if err := checkBlocked(ctx, s.blocks, followerID, followeeID); err != nil {
+		return "", err
+	}
+	following, err := s.follows.IsFollowing(ctx, followerID, followeeID)
+	if err != nil || following {
+		return FollowStatusFollowing, err
+	}
+	if followee.IsPrivate {
+		requested, err := s.follows.HasRequested(ctx, followerID, followeeID)
+		if err != nil || requested {
+			return FollowStatusRequested, err
+		}
+		if err := s.follows.Request(ctx, followerID, followeeID); err != nil {
+			return "", err
+		}
+		s.notify(ctx, followee.ID, followerID, models.NotificationFollowRequest)
+		return FollowStatusRequested, nil
+	}
+	// The account may have gone public with this request still pending.
+	if _, err := s.follows.DeleteRequest(ctx, followerID, followeeID); err != nil {
+		return "", err
+	}
+	if err := s.follows.Follow(ctx, followerID, followeeID); err != nil {
+		return "", err
+	}
+	s.notify(ctx, followee.ID, followerID, models.NotificationFollow)
+	return FollowStatusFollowing, nil
 }
 
+// Unfollow removes the follow or withdraws a pending request.
 func (s *FollowService) Unfollow(ctx context.Context, followerID, followeeID string) error {
+	if _, err := s.follows.DeleteRequest(ctx, followerID, followeeID); err != nil {
+		return err
+	}
 	return s.follows.Unfollow(ctx, followerID, followeeID)
 }
 
@@ -27,10 +99,218 @@ func (s *FollowService) IsFollowing(ctx context.Context, followerID, followeeID
 	return s.follows.IsFollowing(ctx, followerID, followeeID)
 }
 
-func (s *FollowService) GetFollowers(ctx context.Context, userID string) ([]models.User, error) {
-	return s.follows.GetFollowers(ctx, userID)
+// FollowListUser is a user in a follower or following list, with how they
+// relate to the viewer.
+type FollowListUser struct {
+	PublicUser
+	FollowedAt   time.Time `json:"followed_at"`
+	FollowedByMe bool      `json:"followed_by_me"`
+	FollowsMe    bool      `json:"follows_me"`
+}
+
+// FollowPage is one page of a follow listing. NextCursor is empty on the
+// last page.
+type FollowPage struct {
+	Users      []FollowListUser `json:"users"`
+	Total      int64            `json:"total"`
+	NextCursor string           `json:"next_cursor,omitempty"`
+}
+
+// Followers pages through who follows userID, newest first.
+func (s *FollowService) Followers(ctx context.Context, viewer, userID, cursor string, limit int) (*FollowPage, error) {
+	return s.list(ctx, viewer, userID, cursor, limit, s.follows.Followers)
+}
+
+// Following pages through who userID follows, newest first.
+func (s *FollowService) Following(ctx context.Context, viewer, userID, cursor string, limit int) (*FollowPage, error) {
+	return s.list(ctx, viewer, userID, cursor, limit, s.follows.Following)
+}
+
+// MutualFollowers pages through users following both the viewer and userID.
+func (s *FollowService) MutualFollowers(ctx context.Context, viewer, userID, cursor string, limit int) (*FollowPage, error) {
+	return s.list(ctx, viewer, userID, cursor, limit, func(ctx context.Context, q repositories.FollowQuery) ([]repositories.FollowEdge, int64, error) {
+		return s.follows.MutualFollowers(ctx, q, viewer)
+	})
+}
+
+// FollowersYouFollow pages through userID's followers that the viewer
+// follows.
+func (s *FollowService) FollowersYouFollow(ctx context.Context, viewer, userID, cursor string, limit int) (*FollowPage, error) {
+	return s.list(ctx, viewer, userID, cursor, limit, func(ctx context.Context, q repositories.FollowQuery) ([]repositories.FollowEdge, int64, error) {
+		return s.follows.FollowersFollowedBy(ctx, q, viewer)
+	})
+}
+
+// list runs a follow listing of userID for viewer. Users blocked either way
+// look missing, a private account's lists are for its followers only, and
+// users blocked either way by the viewer are left out of every list.
+func (s *FollowService) list(ctx context.Context, viewer, userID, cursor string, limit int, fetch func(context.Context, repositories.FollowQuery) ([]repositories.FollowEdge, int64, error)) (*FollowPage, error) {
+	if viewer == "" {
+		return nil, ErrUnauthorized
+	}
+	if _, err := uuid.Parse(userID); err != nil {
+		return nil, gorm.ErrRecordNotFound
+	}
+	u, err := s.users.GetByID(ctx, userID)
+	if err != nil {
+		return nil, err
+	}
+	if err := checkBlocked(ctx, s.blocks, viewer, userID); errors.Is(err, ErrBlocked) {
+		return nil, gorm.ErrRecordNotFound
+	} else if err != nil {
+		return nil, err
+	}
+	if u.IsPrivate && viewer != userID {
+		following, err := s.follows.IsFollowing(ctx, viewer, userID)
+		if err != nil {
+			return nil, err
+		}
+		if !following {
+			return nil, ErrForbidden
+		}
+	}
+
+	q := repositories.FollowQuery{UserID: userID, Limit: limit}
+	if q.Limit <= 0 {
+		q.Limit = defaultFollowLimit
+	}
+	q.Limit = min(q.Limit, maxFollowLimit)
+	if cursor != "" {
+		if q.After, err = decodeFollowCursor(cursor); err != nil {
+			return nil, err
+		}
+	}
+	if q.Exclude, err = hiddenFrom(ctx, s.blocks, viewer, false); err != nil {
+		return nil, err
+	}
+	// One extra row tells us whether there is another page.
+	limit = q.Limit
+	q.Limit++
+	edges, total, err := fetch(ctx, q)
+	if err != nil {
+		return nil, err
+	}
+	page := &FollowPage{Users: make([]FollowListUser, 0, min(len(edges), limit)), Total: total}
+	if len(edges) > limit {
+		edges = edges[:limit]
+		last := edges[limit-1]
+		page.NextCursor = encodeFollowCursor(repositories.FollowCursor{At: last.FollowedAt, ID: last.ID})
+	}
+
+	ids := make([]uuid.UUID, 0, len(edges))
+	for _, e := range edges {
+		ids = append(ids, e.ID)
+	}
+	followedByMe, followsMe, err := s.follows.Relationships(ctx, viewer, ids)
+	if err != nil {
+		return nil, err
+	}
+	for _, e := range edges {
+		page.Users = append(page.Users, FollowListUser{
+			PublicUser:   publicUser(e.User),
+			FollowedAt:   e.FollowedAt,
+			FollowedByMe: followedByMe[e.ID],
+			FollowsMe:    followsMe[e.ID],
+		})
+	}
+	return page, nil
+}
+
+// Cursors are opaque to clients: the follow time and user ID of the last
+// row, base64 encoded.
+func encodeFollowCursor(c repositories.FollowCursor) string {
+	return base64.RawURLEncoding.EncodeToString([]byte(c.At.UTC().Format(time.RFC3339Nano) + "|" + c.ID.String()))
+}
+
+func decodeFollowCursor(s string) (*repositories.FollowCursor, error) {
+	raw, err := base64.RawURLEncoding.DecodeString(s)
+	if err != nil {
+		return nil, ErrInvalidCursor
+	}
+	at, id, ok := strings.Cut(string(raw), "|")
+	if !ok {
+		return nil, ErrInvalidCursor
+	}
+	c := &repositories.FollowCursor{}
+	if c.At, err = time.Parse(time.RFC3339Nano, at); err != nil {
+		return nil, ErrInvalidCursor
+	}
+	if c.ID, err = uuid.Parse(id); err != nil {
+		return nil, ErrInvalidCursor
+	}
+	return c, nil
+}
+
+// FollowRequest is a pending request as shown to the account owner.
+type FollowRequest struct {
+	User        PublicUser `json:"user"`
+	RequestedAt time.Time  `json:"requested_at"`
+}
+
+// Requests lists who is waiting for userID to approve them, newest first.
+// Users blocked either way are left out.
+func (s *FollowService) Requests(ctx context.Context, userID string) ([]FollowRequest, error) {
+	if userID == "" {
+		return nil, ErrUnauthorized
+	}
+	hidden, err := hiddenFrom(ctx, s.blocks, userID, false)
+	if err != nil {
+		return nil, err
+	}
+	reqs, err := s.follows.ListRequests(ctx, userID, hidden)
+	if err != nil {
+		return nil, err
+	}
+	out := make([]FollowRequest, 0, len(reqs))
+	for _, r := range reqs {
+		out = append(out, FollowRequest{User: publicUser(r.Requester), RequestedAt: r.CreatedAt})
+	}
+	return out, nil
+}
+
+// Approve makes requesterID a follower of userID and lets them know. A
+// request between users blocked either way can't be approved.
+func (s *FollowService) Approve(ctx context.Context, userID, requesterID string) error {
+	if userID == "" {
+		return ErrUnauthorized
+	}
+	if _, err := uuid.Parse(requesterID); err != nil {
+		return gorm.ErrRecordNotFound
+	}
+	if err := checkBlocked(ctx, s.blocks, userID, requesterID); err != nil {
+		return err
+	}
+	if err := s.follows.ApproveRequest(ctx, requesterID, userID); err != nil {
+		return err
+	}
+	s.notify(ctx, uuid.MustParse(requesterID), userID, models.NotificationFollowAccept)
+	return nil
+}
+
+// Deny drops the request quietly; the requester is not told.
+func (s *FollowService) Deny(ctx context.Context, userID, requesterID string) error {
+	if userID == "" {
+		return ErrUnauthorized
+	}
+	if _, err := uuid.Parse(requesterID); err != nil {
+		return gorm.ErrRecordNotFound
+	}
+	deleted, err := s.follows.DeleteRequest(ctx, requesterID, userID)
+	if err != nil {
+		return err
+	}
+	if !deleted {
+		return gorm.ErrRecordNotFound
+	}
+	return nil
 }
 
-func (s *FollowService) GetFollowing(ctx context.Context, userID string) ([]models.User, error) {
-	return s.follows.GetFollowing(ctx, userID)
+// notify tells recipient about actor's follow activity. The entity is the
+// actor, the user the notification links to.
+func (s *FollowService) notify(ctx context.Context, recipient uuid.UUID, actor, kind string) {
+	actorID := uuid.MustParse(actor)
+	n := &models.Notification{UserID: recipient, Type: kind, ActorID: actorID, EntityID: actorID}
+	if err := s.notifications.Create(ctx, n); err != nil {
+		log.Printf("Failed to create %s notification: %v", kind, err)
+	}
 }
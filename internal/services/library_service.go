@@ -0,0 +1,138 @@
+package services
+
+import (
+	"context"
+	"errors"
+	"strings"
+
+	"github.com/google/uuid"
+
+	"github.com/Dubjay18/scenee/internal/models"
+	"github.com/Dubjay18/scenee/internal/repositories"
+)
+
+var ErrCollectionExists = errors.New("a collection with that name already exists")
+
+const (
+	defaultSavedLimit = 20
+	maxSavedLimit     = 100
+)
+
+// LibraryService manages a user's saved watchlists and the collections they
+// are filed into.
+type LibraryService struct {
+	saves repositories.SaveRepository
+}
+
+func NewLibraryService(saves repositories.SaveRepository) *LibraryService {
+	return &LibraryService{saves: saves}
+}
+
+type SavedPage struct {
+	Items []repositories.SavedWatchlist `json:"items"`
+	Page  int                           `json:"page"`
+	Limit int                           `json:"limit"`
+	Total int64                         `json:"total"`
+}
+
+// ListSaved returns one page of the user's library. collection may be empty
+// (everything), "unfiled", or a collection ID.
+func (s *LibraryService) ListSaved(ctx context.Context, userID, collection, sort string, page, limit int) (*SavedPage, error) {
+	if userID == "" {
+		return nil, ErrUnauthorized
+	}
+	if page < 1 {
+		page = 1
+	}
+	if limit <= 0 {
+		limit = defaultSavedLimit
+	}
+	limit = min(limit, maxSavedLimit)
+	f := repositories.SavedFilter{Sort: sort, Limit: limit, Offset: (page - 1) * limit}
+	switch collection {
+	case "":
+	case "unfiled":
+		f.Unfiled = true
+	default:
+		c, err := s.saves.GetCollection(ctx, userID, collection)
+		if err != nil {
+			return nil, err
+		}
+		f.CollectionID = &c.ID
+	}
+	items, total, err := s.saves.ListSaved(ctx, userID, f)
+	if err != nil {
+		return nil, err
+	}
+	return &SavedPage{Items: items, Page: page, Limit: limit, Total: total}, nil
+}
+
+// MoveSaved files a saved watchlist into a collection, or unfiles it when
+// collectionID is nil.
+func (s *LibraryService) MoveSaved(ctx context.Context, userID, watchlistID string, collectionID *uuid.UUID) error {
+	if userID == "" {
+		return ErrUnauthorized
+	}
+	if collectionID != nil {
+		if _, err := s.saves.GetCollection(ctx, userID, collectionID.String()); err != nil {
+			return err
+		}
+	}
+	return s.saves.SetCollection(ctx, userID, watchlistID, collectionID)
+}
+
+func (s *LibraryService) Collections(ctx context.Context, userID string) ([]models.SaveCollection, error) {
+	if userID == "" {
+		return nil, ErrUnauthorized
+	}
+	return s.saves.ListCollections(ctx, userID)
+}
+
+func (s *LibraryService) CreateCollection(ctx context.Context, userID, name string) (*models.SaveCollection, error) {
+	if userID == "" {
+		return nil, ErrUnauthorized
+	}
+	name = strings.TrimSpace(name)
+	if err := s.ensureNameFree(ctx, userID, "", name); err != nil {
+		return nil, err
+	}
+	c := &models.SaveCollection{UserID: uuid.MustParse(userID), Name: name}
+	if err := s.saves.CreateCollection(ctx, c); err != nil {
+		return nil, err
+	}
+	return c, nil
+}
+
+func (s *LibraryService) RenameCollection(ctx context.Context, userID, id, name string) (*models.SaveCollection, error) {
+	if userID == "" {
+		return nil, ErrUnauthorized
+	}
+	name = strings.TrimSpace(name)
+	if err := s.ensureNameFree(ctx, userID, id, name); err != nil {
+		return nil, err
+	}
+	if err := s.saves.RenameCollection(ctx, userID, id, name); err != nil {
+		return nil, err
+	}
+	return s.saves.GetCollection(ctx, userID, id)
+}
+
+func (s *LibraryService) DeleteCollection(ctx context.Context, userID, id string) error {
+	if userID == "" {
+		return ErrUnauthorized
+	}
+	return s.saves.DeleteCollection(ctx, userID, id)
+}
+
+func (s *LibraryService) ensureNameFree(ctx context.Context, userID, exceptID, name string) error {
+	existing, err := s.saves.ListCollections(ctx, userID)
+	if err != nil {
+		return err
+	}
+	for _, c := range existing {
+		if c.ID.String() != exceptID && strings.EqualFold(c.Name, name) {
+			return ErrCollectionExists
+		}
+	}
+	return nil
+}
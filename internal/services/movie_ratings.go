@@ -0,0 +1,84 @@
+package services
+
+import (
+	"context"
+	"errors"
+
+	"gorm.io/gorm"
+
+	"github.com/Dubjay18/scenee/internal/domain"
+	"github.com/Dubjay18/scenee/internal/models"
+)
+
+const (
+	// DefaultTopRatedMinVotes is the Bayesian prior weight and the minimum
+	// number of ratings a movie needs to be listed as top rated.
+	DefaultTopRatedMinVotes = 10
+	defaultTopRatedLimit    = 20
+	maxTopRatedLimit        = 100
+)
+
+// GetMovieDetails is GetMovieByTMDBID plus the Scenee community rating,
+// with TMDB details refreshed when older than movieRefreshTTL.
+func (s *MovieService) GetMovieDetails(ctx context.Context, tmdbID int) (*domain.Movie, error) {
+	mv, err := s.GetMovieByTMDBID(ctx, tmdbID)
+	if err != nil {
+		return nil, err
+	}
+	mv = s.refreshDetails(ctx, mv)
+	stats, err := s.ratings.Get(ctx, mv.ID)
+	if err != nil && !errors.Is(err, gorm.ErrRecordNotFound) {
+		return nil, err
+	}
+	mv.Community = domain.RatingSummaryFromModel(stats)
+	return mv, nil
+}
+
+type TopRatedMovie struct {
+	Movie *domain.Movie `json:"movie"`
+	// Score is the Bayesian weighted rating the list is ordered by.
+	Score float64 `json:"score"`
+}
+
+type TopRatedPage struct {
+	Movies   []TopRatedMovie `json:"movies"`
+	MinVotes int             `json:"min_votes"`
+	Page     int             `json:"page"`
+	Limit    int             `json:"limit"`
+	Total    int64           `json:"total"`
+}
+
+// TopRated lists the best rated movies on Scenee. Averages are pulled toward
+// the site-wide mean by minVotes phantom ratings so a single 10/10 doesn't
+// top the chart, and movies with fewer than minVotes ratings are left out.
+func (s *MovieService) TopRated(ctx context.Context, genre string, minVotes, page, limit int) (*TopRatedPage, error) {
+	if minVotes <= 0 {
+		minVotes = DefaultTopRatedMinVotes
+	}
+	if page < 1 {
+		page = 1
+	}
+	if limit <= 0 {
+		limit = defaultTopRatedLimit
+	}
+	limit = min(limit, maxTopRatedLimit)
+	mean, err := s.ratings.GlobalMean(ctx)
+	if err != nil {
+		return nil, err
+	}
+	rows, total, err := s.ratings.TopRated(ctx, minVotes, mean, genre, limit, (page-1)*limit)
+	if err != nil {
+		return nil, err
+	}
+	out := &TopRatedPage{Movies: make([]TopRatedMovie, 0, len(rows)), MinVotes: minVotes, Page: page, Limit: limit, Total: total}
+	for i := range rows {
+		mv := domain.MovieFromModel(&rows[i].Movie)
+		mv.Community = domain.RatingSummaryFromModel(&models.MovieRatingStats{
+			Count:     rows[i].RatingCount,
+			Sum:       rows[i].RatingSum,
+			Histogram: rows[i].Histogram,
+		})
+		out.Movies = append(out.Movies, TopRatedMovie{Movie: mv, Score: rows[i].Score})
+	}
+	return out, nil
+}
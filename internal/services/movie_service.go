@@ -3,13 +3,20 @@ package services
 import (
 	"context"
 	"errors"
+	"log"
+	"time"
 
 	"github.com/Dubjay18/scenee/internal/domain"
+	"github.com/Dubjay18/scenee/internal/models"
 	"github.com/Dubjay18/scenee/internal/repositories"
 	"github.com/Dubjay18/scenee/internal/tmdb"
 	"gorm.io/gorm"
 )
 
+// movieRefreshTTL is how long a movie's TMDB details are served before a
+// movie page fetches them again.
+const movieRefreshTTL = 7 * 24 * time.Hour
+
 type IMovieService interface {
 	// Define movie-related service methods here
 	GetMovieByTMDBID(tmdbID int) (*domain.Movie, error)
@@ -21,24 +28,26 @@ type IMovieService interface {
 type MovieService struct {
 	tmdbClient tmdb.Client
 	mrepo      repositories.MovieRepository
+	ratings    repositories.RatingRepository
 }
 
-func NewMovieService(tmdbClient tmdb.Client, mrepo repositories.MovieRepository) *MovieService {
+func NewMovieService(tmdbClient tmdb.Client, mrepo repositories.MovieRepository, ratings repositories.RatingRepository) *MovieService {
 	return &MovieService{
 		tmdbClient: tmdbClient,
 		mrepo:      mrepo,
+		ratings:    ratings,
 	}
 }
 
 func (s *MovieService) GetMovieByTMDBID(ctx context.Context, tmdbID int) (*domain.Movie, error) {
-	m, err := s.mrepo.GetByTMDBID(ctx, tmdbID)
+	m, err := s.mrepo.GetByTMDBID(ctx, models.MediaMovie, tmdbID)
 	if err == gorm.ErrRecordNotFound {
 		tm, err := s.tmdbClient.GetMovie(ctx, int64(tmdbID))
 		if err != nil {
 			return nil, err
 		}
 		// Add movie to the database
-		newMovie := s.tmdbClient.ToDomainMovie(tm)
+		newMovie := s.tmdbClient.ToDomainMovieDetails(tm)
 		// Convert domain.Movie to models.Movie and save
 		// (Assuming a ToModel method exists)
 		modelMovie := newMovie.ToModel()
@@ -106,3 +115,22 @@ func (s *MovieService) AddMovie(ctx context.Context, mov *domain.Movie) (*domain
 	}
 	return mov, nil
 }
+
+// refreshDetails refetches a movie whose details are missing or stale. If
+// TMDB can't be reached the stored copy is served as is.
+func (s *MovieService) refreshDetails(ctx context.Context, mv *domain.Movie) *domain.Movie {
+	if !mv.Details.Stale(time.Now(), movieRefreshTTL) {
+		return mv
+	}
+	tm, err := s.tmdbClient.GetMovie(ctx, int64(mv.TMDBID))
+	if err != nil {
+		log.Printf("Failed to refresh movie %d: %v", mv.TMDBID, err)
+		return mv
+	}
+	fresh := s.tmdbClient.ToDomainMovieDetails(tm)
+	fresh.ID, fresh.CreatedAt = mv.ID, mv.CreatedAt
+	if err := s.mrepo.Update(ctx, fresh.ToModel()); err != nil {
+		log.Printf("Failed to store refreshed movie %d: %v", mv.TMDBID, err)
+	}
+	return fresh
+}
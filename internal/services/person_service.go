@@ -0,0 +1,263 @@
+package services
+
+import (
+	"context"
+	"errors"
+	"log"
+	"time"
+
+	"gorm.io/gorm"
+
+	"github.com/Dubjay18/scenee/internal/domain"
+	"github.com/Dubjay18/scenee/internal/models"
+	"github.com/Dubjay18/scenee/internal/repositories"
+	"github.com/Dubjay18/scenee/internal/tmdb"
+)
+
+const (
+	// personRefreshTTL is how long a person page is served from the cache.
+	personRefreshTTL = 7 * 24 * time.Hour
+	// creditCheckAge is how often people with followers are refetched to
+	// look for new credits, and creditCheckBatch how many one run covers.
+	creditCheckAge   = 24 * time.Hour
+	creditCheckBatch = 50
+	// maxCreditNotifications caps notifications per person per refresh,
+	// for when TMDB backfills a long filmography at once.
+	maxCreditNotifications = 5
+
+	defaultPersonLimit = 20
+	maxPersonLimit     = 100
+)
+
+// PersonService serves people pages and notifies users who follow a person
+// when a new film they're credited on shows up on TMDB.
+type PersonService struct {
+	tmdbClient    tmdb.Client
+	people        repositories.PersonRepository
+	movies        *MovieService
+	notifications repositories.NotificationRepository
+}
+
+func NewPersonService(tmdbClient tmdb.Client, people repositories.PersonRepository, movies *MovieService, notifications repositories.NotificationRepository) *PersonService {
+	return &PersonService{tmdbClient: tmdbClient, people: people, movies: movies, notifications: notifications}
+}
+
+// PersonSummary is a person search result.
+type PersonSummary struct {
+	TMDBID             int      `json:"tmdb_id"`
+	Name               string   `json:"name"`
+	KnownForDepartment string   `json:"known_for_department"`
+	ProfilePath        string   `json:"profile_path"`
+	KnownFor           []string `json:"known_for"`
+}
+
+type PersonSearchResult struct {
+	People     []PersonSummary `json:"people"`
+	TotalCount int             `json:"total_count"`
+	TotalPages int             `json:"total_pages"`
+	Page       int             `json:"page"`
+}
+
+type PersonPage struct {
+	People []PersonSummary `json:"people"`
+	Page   int             `json:"page"`
+	Limit  int             `json:"limit"`
+	Total  int64           `json:"total"`
+}
+
+// Search searches TMDB for people by name.
+func (s *PersonService) Search(ctx context.Context, query string, page int) (*PersonSearchResult, error) {
+	resp, err := s.tmdbClient.SearchPeople(ctx, query, page)
+	if err != nil {
+		return nil, err
+	}
+	out := &PersonSearchResult{People: make([]PersonSummary, 0, len(resp.Results)), TotalCount: resp.TotalResults, TotalPages: resp.TotalPages, Page: resp.Page}
+	for _, p := range resp.Results {
+		ps := PersonSummary{TMDBID: int(p.ID), Name: p.Name, KnownForDepartment: p.KnownForDepartment, ProfilePath: p.ProfilePath, KnownFor: []string{}}
+		for _, t := range p.KnownFor {
+			title := t.Title
+			if t.MediaType == models.MediaTV {
+				title = t.Name
+			}
+			ps.KnownFor = append(ps.KnownFor, title)
+		}
+		out.People = append(out.People, ps)
+	}
+	return out, nil
+}
+
+// Get returns a person with their filmography, fetching them from TMDB the
+// first time and again when older than personRefreshTTL.
+func (s *PersonService) Get(ctx context.Context, tmdbID int) (*domain.Person, error) {
+	p, err := s.cached(ctx, tmdbID)
+	if err != nil {
+		return nil, err
+	}
+	if !p.Stale(time.Now(), personRefreshTTL) {
+		return p, nil
+	}
+	fresh, err := s.refresh(ctx, p)
+	if err != nil {
+		log.Printf("Failed to refresh person %d: %v", tmdbID, err)
+		return p, nil
+	}
+	return fresh, nil
+}
+
+// cached returns the stored person, fetching and storing them if new.
+func (s *PersonService) cached(ctx context.Context, tmdbID int) (*domain.Person, error) {
+	m, err := s.people.GetByTMDBID(ctx, tmdbID)
+	if errors.Is(err, gorm.ErrRecordNotFound) {
+		pd, err := s.tmdbClient.GetPerson(ctx, int64(tmdbID))
+		if err != nil {
+			return nil, err
+		}
+		m = s.tmdbClient.ToDomainPerson(pd).ToModel()
+		if err := s.people.Upsert(ctx, m); err != nil {
+			return nil, err
+		}
+	} else if err != nil {
+		return nil, err
+	}
+	return domain.PersonFromModel(m), nil
+}
+
+// refresh refetches p from TMDB, stores it and notifies p's followers of
+// movies they weren't credited on before.
+func (s *PersonService) refresh(ctx context.Context, p *domain.Person) (*domain.Person, error) {
+	pd, err := s.tmdbClient.GetPerson(ctx, int64(p.TMDBID))
+	if err != nil {
+		return nil, err
+	}
+	fresh := s.tmdbClient.ToDomainPerson(pd)
+	fresh.ID = p.ID
+	if err := s.people.Upsert(ctx, fresh.ToModel()); err != nil {
+		return nil, err
+	}
+	if added := newMovieCredits(p.Credits, fresh.Credits); len(added) > 0 {
+		s.notifyCredits(ctx, fresh, added)
+	}
+	return fresh, nil
+}
+
+// newMovieCredits returns the TMDB IDs of movies in fresh but not in old,
+// once each, in fresh's order.
+func newMovieCredits(old, fresh []domain.Credit) []int {
+	seen := make(map[int]bool, len(old))
+	for _, c := range old {
+		if c.MediaType == models.MediaMovie {
+			seen[c.TMDBID] = true
+		}
+	}
+	var added []int
+	for _, c := range fresh {
+		if c.MediaType == models.MediaMovie && !seen[c.TMDBID] {
+			seen[c.TMDBID] = true
+			added = append(added, c.TMDBID)
+		}
+	}
+	return added
+}
+
+func (s *PersonService) notifyCredits(ctx context.Context, p *domain.Person, tmdbIDs []int) {
+	followers, err := s.people.FollowerIDs(ctx, p.ID)
+	if err != nil || len(followers) == 0 {
+		if err != nil {
+			log.Printf("Failed to list followers of person %d: %v", p.TMDBID, err)
+		}
+		return
+	}
+	for _, id := range tmdbIDs[:min(len(tmdbIDs), maxCreditNotifications)] {
+		mv, err := s.movies.GetMovieByTMDBID(ctx, id)
+		if err != nil {
+			log.Printf("Failed to fetch movie %d for person %d: %v", id, p.TMDBID, err)
+			continue
+		}
+		for _, uid := range followers {
+			n := &models.Notification{UserID: uid, Type: models.NotificationPersonCredit, ActorID: p.ID, EntityID: mv.ID}
+			if err := s.notifications.Create(ctx, n); err != nil {
+				log.Printf("Failed to create %s notification: %v", models.NotificationPersonCredit, err)
+			}
+		}
+	}
+}
+
+// Follow subscribes viewer to new credits of the person tmdbID.
+func (s *PersonService) Follow(ctx context.Context, viewer string, tmdbID int) error {
+	if viewer == "" {
+		return ErrUnauthorized
+	}
+	p, err := s.cached(ctx, tmdbID)
+	if err != nil {
+		return err
+	}
+	return s.people.Follow(ctx, viewer, p.ID)
+}
+
+func (s *PersonService) Unfollow(ctx context.Context, viewer string, tmdbID int) error {
+	if viewer == "" {
+		return ErrUnauthorized
+	}
+	m, err := s.people.GetByTMDBID(ctx, tmdbID)
+	if err != nil {
+		return err
+	}
+	return s.people.Unfollow(ctx, viewer, m.ID)
+}
+
+// IsFollowing reports whether viewer follows the person tmdbID.
+func (s *PersonService) IsFollowing(ctx context.Context, viewer string, tmdbID int) (bool, error) {
+	if viewer == "" {
+		return false, ErrUnauthorized
+	}
+	m, err := s.people.GetByTMDBID(ctx, tmdbID)
+	if errors.Is(err, gorm.ErrRecordNotFound) {
+		return false, nil
+	}
+	if err != nil {
+		return false, err
+	}
+	return s.people.IsFollowing(ctx, viewer, m.ID)
+}
+
+// Followed pages through the people viewer follows.
+func (s *PersonService) Followed(ctx context.Context, viewer string, page, limit int) (*PersonPage, error) {
+	if viewer == "" {
+		return nil, ErrUnauthorized
+	}
+	if page < 1 {
+		page = 1
+	}
+	if limit <= 0 {
+		limit = defaultPersonLimit
+	}
+	limit = min(limit, maxPersonLimit)
+	people, total, err := s.people.Followed(ctx, viewer, limit, (page-1)*limit)
+	if err != nil {
+		return nil, err
+	}
+	out := &PersonPage{People: make([]PersonSummary, 0, len(people)), Page: page, Limit: limit, Total: total}
+	for _, p := range people {
+		out.People = append(out.People, PersonSummary{TMDBID: p.TMDBID, Name: p.Name, KnownForDepartment: p.KnownForDepartment, ProfilePath: p.ProfilePath, KnownFor: []string{}})
+	}
+	return out, nil
+}
+
+// CheckNewCredits refetches followed people not checked in creditCheckAge
+// so their followers hear about new films. It runs as a periodic job.
+func (s *PersonService) CheckNewCredits(ctx context.Context) error {
+	due, err := s.people.DueForCheck(ctx, time.Now().Add(-creditCheckAge), creditCheckBatch)
+	if err != nil {
+		return err
+	}
+	for i := range due {
+		if err := ctx.Err(); err != nil {
+			return err
+		}
+		p := domain.PersonFromModel(&due[i])
+		if _, err := s.refresh(ctx, p); err != nil {
+			log.Printf("Failed to check credits of person %d: %v", p.TMDBID, err)
+		}
+	}
+	return nil
+}
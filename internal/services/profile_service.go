@@ -0,0 +1,172 @@
+package services
+
+import (
+	"context"
+	"errors"
+	"time"
+
+	"github.com/google/uuid"
+	"gorm.io/gorm"
+
+	"github.com/Dubjay18/scenee/internal/models"
+	"github.com/Dubjay18/scenee/internal/repositories"
+)
+
+// profileReviewLimit is how many recent reviews a profile shows.
+const profileReviewLimit = 5
+
+// Profile is what anyone signed in can see of a user. It deliberately leaves
+// out email, role and anything else from the account itself. For private
+// accounts only approved followers get watch stats, lists and reviews.
+type Profile struct {
+	ID        uuid.UUID `json:"id"`
+	Username  string    `json:"username"`
+	Bio       string    `json:"bio"`
+	AvatarURL string    `json:"avatar_url"`
+	JoinedAt  time.Time `json:"joined_at"`
+
+	Counts     repositories.ProfileCounts `json:"counts"`
+	WatchStats repositories.WatchStats    `json:"watch_stats"`
+	Watchlists []models.Watchlist         `json:"watchlists"`
+	Reviews    []ProfileReview            `json:"recent_reviews"`
+
+	IsMe        bool `json:"is_me"`
+	IsFollowing bool `json:"is_following"`
+	FollowsMe   bool `json:"follows_me"`
+
+	IsPrivate bool `json:"is_private"`
+	// FollowRequested means the viewer's request to follow is pending.
+	FollowRequested bool `json:"follow_requested"`
+}
+
+// ProfileReview is a review as listed on its author's profile: the movie it
+// is about and the spoiler-safe preview rather than the full text.
+type ProfileReview struct {
+	ID              uuid.UUID `json:"id"`
+	MovieID         uuid.UUID `json:"movie_id"`
+	MovieTitle      string    `json:"movie_title"`
+	PosterURL       string    `json:"poster_url"`
+	Rating          int       `json:"rating"`
+	Preview         string    `json:"preview"`
+	Spoiler         bool      `json:"spoiler"`
+	HasSpoilers     bool      `json:"has_spoilers"`
+	ContentWarnings []string  `json:"content_warnings"`
+	LikeCount       int       `json:"like_count"`
+	ReplyCount      int       `json:"reply_count"`
+	CreatedAt       time.Time `json:"created_at"`
+}
+
+type ProfileService struct {
+	users      repositories.UserRepository
+	watchlists repositories.WatchlistRepository
+	follows    repositories.FollowRepository
+	blocks     repositories.BlockRepository
+	profiles   repositories.ProfileRepository
+}
+
+func NewProfileService(users repositories.UserRepository, watchlists repositories.WatchlistRepository, follows repositories.FollowRepository, blocks repositories.BlockRepository, profiles repositories.ProfileRepository) *ProfileService {
+	return &ProfileService{users: users, watchlists: watchlists, follows: follows, blocks: blocks, profiles: profiles}
+}
+
+// Get returns the profile of the user with the given ID as viewer sees it.
+func (s *ProfileService) Get(ctx context.Context, viewer, id string) (*Profile, error) {
+	u, err := s.users.GetByID(ctx, id)
+	if err != nil {
+		return nil, err
+	}
+	return s.build(ctx, viewer, u)
+}
+
+// GetByUsername is Get for a username, ignoring case.
+func (s *ProfileService) GetByUsername(ctx context.Context, viewer, username string) (*Profile, error) {
+	u, err := s.users.GetByUsername(ctx, username)
+	if err != nil {
+		return nil, err
+	}
+	return s.build(ctx, viewer, u)
+}
+
+// build assembles the profile. Users blocked either way look missing to
+// each other.
+func (s *ProfileService) build(ctx context.Context, viewer string, u *models.User) (*Profile, error) {
+	id := u.ID.String()
+	if err := checkBlocked(ctx, s.blocks, viewer, id); errors.Is(err, ErrBlocked) {
+		return nil, gorm.ErrRecordNotFound
+	} else if err != nil {
+		return nil, err
+	}
+	p := &Profile{
+		ID:         u.ID,
+		Username:   u.Username,
+		Bio:        u.Bio,
+		AvatarURL:  u.AvatarUrl,
+		JoinedAt:   u.CreatedAt,
+		IsMe:       viewer == id,
+		IsPrivate:  u.IsPrivate,
+		Watchlists: []models.Watchlist{},
+		Reviews:    []ProfileReview{},
+	}
+
+	counts, err := s.profiles.Counts(ctx, id)
+	if err != nil {
+		return nil, err
+	}
+	p.Counts = *counts
+	if viewer != "" && !p.IsMe {
+		if p.IsFollowing, err = s.follows.IsFollowing(ctx, viewer, id); err != nil {
+			return nil, err
+		}
+		if p.FollowsMe, err = s.follows.IsFollowing(ctx, id, viewer); err != nil {
+			return nil, err
+		}
+		if !p.IsFollowing && u.IsPrivate {
+			if p.FollowRequested, err = s.follows.HasRequested(ctx, viewer, id); err != nil {
+				return nil, err
+			}
+		}
+	}
+	if u.IsPrivate && !p.IsMe && !p.IsFollowing {
+		return p, nil
+	}
+
+	stats, err := s.profiles.WatchStats(ctx, id, time.Now())
+	if err != nil {
+		return nil, err
+	}
+	p.WatchStats = *stats
+
+	// Only public lists, even on your own profile: it shows what others see.
+	lists, err := s.watchlists.ListPublicByOwner(ctx, id)
+	if err != nil {
+		return nil, err
+	}
+	if lists != nil {
+		p.Watchlists = lists
+	}
+
+	reviews, err := s.profiles.RecentReviews(ctx, id, profileReviewLimit)
+	if err != nil {
+		return nil, err
+	}
+	for _, r := range reviews {
+		renderReview(&r)
+		pr := ProfileReview{
+			ID:              r.ID,
+			MovieID:         r.MovieID,
+			Rating:          r.Rating,
+			Preview:         r.Preview,
+			Spoiler:         r.Spoiler,
+			HasSpoilers:     r.HasSpoilers,
+			ContentWarnings: r.ContentWarnings,
+			LikeCount:       r.LikeCount,
+			ReplyCount:      r.ReplyCount,
+			CreatedAt:       r.CreatedAt,
+		}
+		if r.Movie != nil {
+			pr.MovieTitle = r.Movie.Title
+			pr.PosterURL = r.Movie.PosterURL
+		}
+		p.Reviews = append(p.Reviews, pr)
+	}
+	return p, nil
+}
@@ -2,25 +2,115 @@ package services
 
 import (
 	"context"
+	"errors"
+	"log"
+	"slices"
+	"strings"
 
+	"github.com/google/uuid"
+	"gorm.io/gorm"
+
+	"github.com/Dubjay18/scenee/internal/markup"
 	"github.com/Dubjay18/scenee/internal/models"
 	"github.com/Dubjay18/scenee/internal/repositories"
 )
 
+const (
+	defaultReviewLimit = 20
+	maxReviewLimit     = 100
+	// reviewPreviewLen is the length of Review.Preview in characters.
+	reviewPreviewLen = 280
+)
+
 type ReviewService struct {
-	reviews repositories.ReviewRepository
+	reviews       repositories.ReviewRepository
+	follows       repositories.FollowRepository
+	blocks        repositories.BlockRepository
+	notifications repositories.NotificationRepository
 }
 
-func NewReviewService(reviews repositories.ReviewRepository) *ReviewService {
-	return &ReviewService{reviews: reviews}
+func NewReviewService(reviews repositories.ReviewRepository, follows repositories.FollowRepository, blocks repositories.BlockRepository, notifications repositories.NotificationRepository) *ReviewService {
+	return &ReviewService{reviews: reviews, follows: follows, blocks: blocks, notifications: notifications}
 }
 
+var (
+	ErrUnknownContentWarning = errors.New("unknown content warning; allowed: " + strings.Join(models.ContentWarnings, ", "))
+	ErrReviewExists          = errors.New("you have already reviewed this movie; edit that review instead")
+	ErrEmptyReply            = errors.New("reply body is empty")
+)
+
+// Create adds the author's review of a movie. Each user has at most one
+// review per movie, so one person's ratings count once in its stats.
 func (s *ReviewService) Create(ctx context.Context, review *models.Review) error {
-	return s.reviews.Create(ctx, review)
+	var err error
+	if review.ContentWarnings, err = normalizeWarnings(review.ContentWarnings); err != nil {
+		return err
+	}
+	if err := s.reviews.Create(ctx, review); errors.Is(err, gorm.ErrDuplicatedKey) {
+		return ErrReviewExists
+	} else if err != nil {
+		return err
+	}
+	renderReview(review)
+	return nil
+}
+
+type ReviewPage struct {
+	Reviews []models.Review `json:"reviews"`
+	Page    int             `json:"page"`
+	Limit   int             `json:"limit"`
+	Total   int64           `json:"total"`
 }
 
-func (s *ReviewService) GetByMovieID(ctx context.Context, movieID string) ([]models.Review, error) {
-	return s.reviews.GetByMovieID(ctx, movieID)
+// ListByMovie returns a page of a movie's reviews sorted by recent, popular
+// or rating. With following set, only reviews by people the viewer follows
+// are included. Reviews by users blocked either way are never included.
+func (s *ReviewService) ListByMovie(ctx context.Context, viewer, movieID, sort string, following bool, page, limit int) (*ReviewPage, error) {
+	if page < 1 {
+		page = 1
+	}
+	if limit <= 0 {
+		limit = defaultReviewLimit
+	}
+	limit = min(limit, maxReviewLimit)
+	f := repositories.ReviewFilter{Sort: sort, Limit: limit, Offset: (page - 1) * limit}
+	if following {
+		if viewer == "" {
+			return nil, ErrUnauthorized
+		}
+		ids, err := s.follows.FollowingIDs(ctx, viewer)
+		if err != nil {
+			return nil, err
+		}
+		f.AuthorIDs = ids
+	}
+	hidden, err := hiddenFrom(ctx, s.blocks, viewer, false)
+	if err != nil {
+		return nil, err
+	}
+	f.ExcludeAuthorIDs = hidden
+	reviews, total, err := s.reviews.ListByMovie(ctx, movieID, f)
+	if err != nil {
+		return nil, err
+	}
+	if viewer != "" && len(reviews) > 0 {
+		ids := make([]uuid.UUID, len(reviews))
+		for i, r := range reviews {
+			ids[i] = r.ID
+		}
+		liked, err := s.reviews.LikedBy(ctx, viewer, ids)
+		if err != nil {
+			return nil, err
+		}
+		for i := range reviews {
+			v := liked[reviews[i].ID]
+			reviews[i].LikedByMe = &v
+		}
+	}
+	for i := range reviews {
+		renderReview(&reviews[i])
+	}
+	return &ReviewPage{Reviews: reviews, Page: page, Limit: limit, Total: total}, nil
 }
 
 func (s *ReviewService) GetByUserAndMovie(ctx context.Context, userID, movieID string) (*models.Review, error) {
@@ -28,9 +118,167 @@ func (s *ReviewService) GetByUserAndMovie(ctx context.Context, userID, movieID s
 }
 
 func (s *ReviewService) Update(ctx context.Context, review *models.Review) error {
-	return s.reviews.Update(ctx, review)
+	var err error
+	if review.ContentWarnings, err = normalizeWarnings(review.ContentWarnings); err != nil {
+		return err
+	}
+	if err := s.reviews.Update(ctx, review); err != nil {
+		return err
+	}
+	renderReview(review)
+	return nil
+}
+
+// renderReview fills r's rendered forms from its markup, parsing it once per
+// form. Reviews are rendered only on their way into a response, not on load.
+func renderReview(r *models.Review) {
+	if r.ContentWarnings == nil {
+		r.ContentWarnings = []string{}
+	}
+	r.ReviewHTML = markup.HTML(r.Review)
+	redacted := markup.PlainText(r.Review, true)
+	r.HasSpoilers = r.Spoiler || redacted != markup.PlainText(r.Review, false)
+	r.Preview = ""
+	if !r.Spoiler {
+		r.Preview = markup.Excerpt(redacted, reviewPreviewLen)
+	}
+}
+
+// normalizeWarnings lowercases and dedupes content warnings and rejects any
+// outside models.ContentWarnings.
+func normalizeWarnings(in []string) ([]string, error) {
+	out := make([]string, 0, len(in))
+	for _, w := range in {
+		w = strings.ToLower(strings.TrimSpace(w))
+		if !slices.Contains(models.ContentWarnings, w) {
+			return nil, ErrUnknownContentWarning
+		}
+		if !slices.Contains(out, w) {
+			out = append(out, w)
+		}
+	}
+	return out, nil
 }
 
 func (s *ReviewService) Delete(ctx context.Context, id, userID string) error {
 	return s.reviews.Delete(ctx, id, userID)
 }
+
+// Like marks a review as helpful and notifies its author the first time.
+func (s *ReviewService) Like(ctx context.Context, userID, movieID, reviewID string) error {
+	if userID == "" {
+		return ErrUnauthorized
+	}
+	review, err := s.reviews.GetByID(ctx, movieID, reviewID)
+	if err != nil {
+		return err
+	}
+	if err := checkBlocked(ctx, s.blocks, userID, review.UserID.String()); err != nil {
+		return err
+	}
+	created, err := s.reviews.Like(ctx, userID, review.ID)
+	if err != nil {
+		return err
+	}
+	if created {
+		s.notify(ctx, review.UserID, userID, models.NotificationReviewLike, review.ID)
+	}
+	return nil
+}
+
+func (s *ReviewService) Unlike(ctx context.Context, userID, movieID, reviewID string) error {
+	if userID == "" {
+		return ErrUnauthorized
+	}
+	review, err := s.reviews.GetByID(ctx, movieID, reviewID)
+	if err != nil {
+		return err
+	}
+	return s.reviews.Unlike(ctx, userID, review.ID)
+}
+
+type ReplyThread struct {
+	Replies []models.ReviewReply `json:"replies"`
+	Page    int                  `json:"page"`
+	Limit   int                  `json:"limit"`
+	Total   int64                `json:"total"`
+}
+
+// Replies pages through the thread under a review, oldest first, without
+// replies from users blocked either way.
+func (s *ReviewService) Replies(ctx context.Context, viewer, movieID, reviewID string, page, limit int) (*ReplyThread, error) {
+	review, err := s.reviews.GetByID(ctx, movieID, reviewID)
+	if err != nil {
+		return nil, err
+	}
+	if page < 1 {
+		page = 1
+	}
+	if limit <= 0 {
+		limit = defaultReviewLimit
+	}
+	limit = min(limit, maxReviewLimit)
+	hidden, err := hiddenFrom(ctx, s.blocks, viewer, false)
+	if err != nil {
+		return nil, err
+	}
+	replies, total, err := s.reviews.ListReplies(ctx, review.ID.String(), hidden, limit, (page-1)*limit)
+	if err != nil {
+		return nil, err
+	}
+	return &ReplyThread{Replies: replies, Page: page, Limit: limit, Total: total}, nil
+}
+
+// Reply adds to the thread under a review and notifies the review's author.
+func (s *ReviewService) Reply(ctx context.Context, userID, movieID, reviewID, body string) (*models.ReviewReply, error) {
+	if userID == "" {
+		return nil, ErrUnauthorized
+	}
+	if body = strings.TrimSpace(body); body == "" {
+		return nil, ErrEmptyReply
+	}
+	review, err := s.reviews.GetByID(ctx, movieID, reviewID)
+	if err != nil {
+		return nil, err
+	}
+	if err := checkBlocked(ctx, s.blocks, userID, review.UserID.String()); err != nil {
+		return nil, err
+	}
+	reply := &models.ReviewReply{ReviewID: review.ID, UserID: uuid.MustParse(userID), Body: body}
+	if err := s.reviews.CreateReply(ctx, reply); err != nil {
+		return nil, err
+	}
+	s.notify(ctx, review.UserID, userID, models.NotificationReviewReply, review.ID)
+	return s.reviews.GetReply(ctx, review.ID.String(), reply.ID.String())
+}
+
+// DeleteReply removes a reply. Its author and the review's author may do so.
+func (s *ReviewService) DeleteReply(ctx context.Context, userID, movieID, reviewID, replyID string) error {
+	if userID == "" {
+		return ErrUnauthorized
+	}
+	review, err := s.reviews.GetByID(ctx, movieID, reviewID)
+	if err != nil {
+		return err
+	}
+	reply, err := s.reviews.GetReply(ctx, review.ID.String(), replyID)
+	if err != nil {
+		return err
+	}
+	if reply.UserID.String() != userID && review.UserID.String() != userID {
+		return ErrForbidden
+	}
+	return s.reviews.DeleteReply(ctx, reply)
+}
+
+// notify tells recipient about actor's activity; nothing is sent for your
+// own actions.
+func (s *ReviewService) notify(ctx context.Context, recipient uuid.UUID, actor, kind string, entity uuid.UUID) {
+	if recipient.String() == actor {
+		return
+	}
+	n := &models.Notification{UserID: recipient, Type: kind, ActorID: uuid.MustParse(actor), EntityID: entity}
+	if err := s.notifications.Create(ctx, n); err != nil {
+		log.Printf("Failed to create %s notification: %v", kind, err)
+	}
+}
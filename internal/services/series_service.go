@@ -0,0 +1,310 @@
+package services
+
+import (
+	"context"
+	"errors"
+	"log"
+	"time"
+
+	"github.com/google/uuid"
+	"gorm.io/gorm"
+
+	"github.com/Dubjay18/scenee/internal/domain"
+	"github.com/Dubjay18/scenee/internal/models"
+	"github.com/Dubjay18/scenee/internal/repositories"
+	"github.com/Dubjay18/scenee/internal/tmdb"
+)
+
+// seasonRefreshTTL is how long a cached season's episodes are served
+// before TMDB is asked again; airing seasons gain episodes weekly.
+const seasonRefreshTTL = 24 * time.Hour
+
+// GetTitle resolves a TMDB ID of mediaType to a cached movie or series,
+// fetching it from TMDB the first time.
+func (s *MovieService) GetTitle(ctx context.Context, mediaType string, tmdbID int) (*domain.Movie, error) {
+	if mediaType == models.MediaTV {
+		return s.GetSeriesByTMDBID(ctx, tmdbID)
+	}
+	return s.GetMovieByTMDBID(ctx, tmdbID)
+}
+
+// GetSeriesByTMDBID is GetMovieByTMDBID for series.
+func (s *MovieService) GetSeriesByTMDBID(ctx context.Context, tmdbID int) (*domain.Movie, error) {
+	m, err := s.mrepo.GetByTMDBID(ctx, models.MediaTV, tmdbID)
+	if errors.Is(err, gorm.ErrRecordNotFound) {
+		tv, err := s.tmdbClient.GetTV(ctx, int64(tmdbID))
+		if err != nil {
+			return nil, err
+		}
+		series := s.tmdbClient.ToDomainSeries(tv)
+		if err := s.mrepo.Create(ctx, series.ToModel()); err != nil {
+			return nil, err
+		}
+		return series, nil
+	}
+	if err != nil {
+		return nil, err
+	}
+	return domain.MovieFromModel(m), nil
+}
+
+// GetSeriesDetails is GetSeriesByTMDBID with the season list refreshed
+// when older than movieRefreshTTL.
+func (s *MovieService) GetSeriesDetails(ctx context.Context, tmdbID int) (*domain.Movie, error) {
+	series, err := s.GetSeriesByTMDBID(ctx, tmdbID)
+	if err != nil {
+		return nil, err
+	}
+	if !series.Series.Stale(time.Now(), movieRefreshTTL) {
+		return series, nil
+	}
+	tv, err := s.tmdbClient.GetTV(ctx, int64(tmdbID))
+	if err != nil {
+		log.Printf("Failed to refresh series %d: %v", tmdbID, err)
+		return series, nil
+	}
+	fresh := s.tmdbClient.ToDomainSeries(tv)
+	fresh.ID, fresh.CreatedAt = series.ID, series.CreatedAt
+	if err := s.mrepo.Update(ctx, fresh.ToModel()); err != nil {
+		log.Printf("Failed to store refreshed series %d: %v", tmdbID, err)
+	}
+	return fresh, nil
+}
+
+// SearchSeries searches TMDB for series. Results aren't cached until a
+// series is opened or added to a list.
+func (s *MovieService) SearchSeries(ctx context.Context, query string, page int) (*domain.SearchResult, error) {
+	resp, err := s.tmdbClient.SearchTV(ctx, query, page)
+	if err != nil {
+		return nil, err
+	}
+	series := make([]domain.Movie, 0, len(resp.Results))
+	for i := range resp.Results {
+		if dm := s.tmdbClient.ToDomainSeries(&resp.Results[i]); dm != nil {
+			series = append(series, *dm)
+		}
+	}
+	return &domain.SearchResult{
+		Movies:     series,
+		TotalCount: resp.TotalResults,
+		TotalPages: resp.TotalPages,
+		Page:       resp.Page,
+	}, nil
+}
+
+// SeriesService serves seasons and episodes of series and tracks which
+// episodes each user has watched.
+type SeriesService struct {
+	tmdbClient tmdb.Client
+	movies     *MovieService
+	episodes   repositories.EpisodeRepository
+}
+
+func NewSeriesService(tmdbClient tmdb.Client, movies *MovieService, episodes repositories.EpisodeRepository) *SeriesService {
+	return &SeriesService{tmdbClient: tmdbClient, movies: movies, episodes: episodes}
+}
+
+// SeasonView is a season with its episodes; Episode.Watched is only set
+// when there is a viewer.
+type SeasonView struct {
+	domain.SeasonSummary
+	SeriesID uuid.UUID        `json:"series_id"`
+	Episodes []models.Episode `json:"episodes"`
+}
+
+type SeasonProgressView struct {
+	Number  int `json:"number"`
+	Watched int `json:"watched"`
+	Total   int `json:"total"`
+}
+
+// SeriesProgress is how far a user is through a series. Totals come from
+// TMDB's episode counts and leave specials (season 0) out.
+type SeriesProgress struct {
+	SeriesID      uuid.UUID            `json:"series_id"`
+	TMDBID        int                  `json:"tmdb_id"`
+	Title         string               `json:"title"`
+	Watched       int                  `json:"watched"`
+	Total         int                  `json:"total"`
+	Percent       float64              `json:"percent"`
+	Seasons       []SeasonProgressView `json:"seasons"`
+	Next          *models.Episode      `json:"next,omitempty"`
+	LastWatchedAt *time.Time           `json:"last_watched_at,omitempty"`
+}
+
+// Series returns a series with its season list.
+func (s *SeriesService) Series(ctx context.Context, tmdbID int) (*domain.Movie, error) {
+	return s.movies.GetSeriesDetails(ctx, tmdbID)
+}
+
+// Search searches TMDB for series.
+func (s *SeriesService) Search(ctx context.Context, query string, page int) (*domain.SearchResult, error) {
+	return s.movies.SearchSeries(ctx, query, page)
+}
+
+// Season returns a season of the series tmdbID. A viewer, if any, gets
+// their watched flags filled in.
+func (s *SeriesService) Season(ctx context.Context, viewer string, tmdbID, number int) (*SeasonView, error) {
+	series, err := s.movies.GetSeriesDetails(ctx, tmdbID)
+	if err != nil {
+		return nil, err
+	}
+	summary, ok := series.Series.Season(number)
+	if !ok {
+		return nil, gorm.ErrRecordNotFound
+	}
+	eps, err := s.season(ctx, series, number)
+	if err != nil {
+		return nil, err
+	}
+	if viewer != "" {
+		ids := make([]uuid.UUID, 0, len(eps))
+		for _, ep := range eps {
+			ids = append(ids, ep.ID)
+		}
+		watched, err := s.episodes.WatchedIDs(ctx, viewer, ids)
+		if err != nil {
+			return nil, err
+		}
+		for i := range eps {
+			eps[i].Watched = watched[eps[i].ID]
+		}
+	}
+	return &SeasonView{SeasonSummary: summary, SeriesID: series.ID, Episodes: eps}, nil
+}
+
+// season returns the cached episodes of a season, fetching them from TMDB
+// when missing or older than seasonRefreshTTL. If TMDB can't be reached
+// the cached episodes are served as they are.
+func (s *SeriesService) season(ctx context.Context, series *domain.Movie, number int) ([]models.Episode, error) {
+	eps, err := s.episodes.Season(ctx, series.ID, number)
+	if err != nil {
+		return nil, err
+	}
+	if len(eps) > 0 && !seasonStale(eps, time.Now()) {
+		return eps, nil
+	}
+	sd, err := s.tmdbClient.GetSeason(ctx, int64(series.TMDBID), number)
+	if err != nil {
+		if len(eps) > 0 {
+			log.Printf("Failed to refresh season %d of series %d: %v", number, series.TMDBID, err)
+			return eps, nil
+		}
+		return nil, err
+	}
+	if err := s.episodes.UpsertSeason(ctx, sd.ToEpisodes(series.ID)); err != nil {
+		return nil, err
+	}
+	return s.episodes.Season(ctx, series.ID, number)
+}
+
+func seasonStale(eps []models.Episode, now time.Time) bool {
+	for _, ep := range eps {
+		if now.Sub(ep.UpdatedAt) > seasonRefreshTTL {
+			return true
+		}
+	}
+	return false
+}
+
+// SetEpisodeWatched marks or unmarks one episode as watched by viewer.
+func (s *SeriesService) SetEpisodeWatched(ctx context.Context, viewer string, tmdbID, season, episode int, watched bool) error {
+	if viewer == "" {
+		return ErrUnauthorized
+	}
+	series, err := s.movies.GetSeriesByTMDBID(ctx, tmdbID)
+	if err != nil {
+		return err
+	}
+	ep, err := s.episodes.Get(ctx, series.ID, season, episode)
+	if errors.Is(err, gorm.ErrRecordNotFound) {
+		// Not cached yet: load the season and look again
+		if _, err := s.season(ctx, series, season); err != nil {
+			return err
+		}
+		ep, err = s.episodes.Get(ctx, series.ID, season, episode)
+	}
+	if err != nil {
+		return err
+	}
+	if watched {
+		return s.episodes.MarkWatched(ctx, viewer, []uuid.UUID{ep.ID})
+	}
+	return s.episodes.UnmarkWatched(ctx, viewer, []uuid.UUID{ep.ID})
+}
+
+// SetSeasonWatched marks every aired episode of a season as watched by
+// viewer, or unmarks every episode, returning how many it covered.
+func (s *SeriesService) SetSeasonWatched(ctx context.Context, viewer string, tmdbID, season int, watched bool) (int, error) {
+	if viewer == "" {
+		return 0, ErrUnauthorized
+	}
+	series, err := s.movies.GetSeriesByTMDBID(ctx, tmdbID)
+	if err != nil {
+		return 0, err
+	}
+	eps, err := s.season(ctx, series, season)
+	if err != nil {
+		return 0, err
+	}
+	if len(eps) == 0 {
+		return 0, gorm.ErrRecordNotFound
+	}
+	now := time.Now()
+	ids := make([]uuid.UUID, 0, len(eps))
+	for _, ep := range eps {
+		if watched && (ep.AirDate == nil || ep.AirDate.After(now)) {
+			continue
+		}
+		ids = append(ids, ep.ID)
+	}
+	if watched {
+		return len(ids), s.episodes.MarkWatched(ctx, viewer, ids)
+	}
+	return len(ids), s.episodes.UnmarkWatched(ctx, viewer, ids)
+}
+
+// Progress reports how far viewer is through the series tmdbID and the
+// next aired episode they haven't watched among the seasons loaded so far.
+func (s *SeriesService) Progress(ctx context.Context, viewer string, tmdbID int) (*SeriesProgress, error) {
+	if viewer == "" {
+		return nil, ErrUnauthorized
+	}
+	series, err := s.movies.GetSeriesDetails(ctx, tmdbID)
+	if err != nil {
+		return nil, err
+	}
+	rows, err := s.episodes.Progress(ctx, viewer, series.ID)
+	if err != nil {
+		return nil, err
+	}
+	watched := make(map[int]int, len(rows))
+	out := &SeriesProgress{SeriesID: series.ID, TMDBID: series.TMDBID, Title: series.Title, Seasons: []SeasonProgressView{}}
+	for _, row := range rows {
+		watched[row.SeasonNumber] = row.Watched
+		if out.LastWatchedAt == nil || row.LastWatchedAt.After(*out.LastWatchedAt) {
+			last := row.LastWatchedAt
+			out.LastWatchedAt = &last
+		}
+	}
+	if series.Series != nil {
+		for _, season := range series.Series.Seasons {
+			if season.Number == 0 {
+				continue
+			}
+			sp := SeasonProgressView{Number: season.Number, Watched: watched[season.Number], Total: season.EpisodeCount}
+			out.Seasons = append(out.Seasons, sp)
+			out.Watched += sp.Watched
+			out.Total += sp.Total
+		}
+	}
+	if out.Total > 0 {
+		out.Percent = float64(min(out.Watched, out.Total)*100) / float64(out.Total)
+	}
+	next, err := s.episodes.NextUnwatched(ctx, viewer, series.ID, time.Now())
+	if err != nil && !errors.Is(err, gorm.ErrRecordNotFound) {
+		return nil, err
+	}
+	out.Next = next
+	return out, nil
+}
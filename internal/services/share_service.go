@@ -0,0 +1,225 @@
+package services
+
+import (
+	"context"
+	"errors"
+	"log"
+	"strings"
+	"time"
+
+	"github.com/google/uuid"
+	"gorm.io/gorm"
+
+	"github.com/Dubjay18/scenee/internal/models"
+	"github.com/Dubjay18/scenee/internal/repositories"
+)
+
+var (
+	ErrShareSelf        = errors.New("cannot share a watchlist with yourself")
+	ErrUnknownRecipient = errors.New("unknown recipient")
+	ErrNoRecipients     = errors.New("at least one recipient is required")
+)
+
+const (
+	defaultShareLimit = 20
+	maxShareLimit     = 100
+)
+
+// ShareService sends watchlists straight to other users. A share lets its
+// recipient see the list even when it is private or its owner's account is.
+type ShareService struct {
+	shares        repositories.ShareRepository
+	watchlists    repositories.WatchlistRepository
+	users         repositories.UserRepository
+	blocks        repositories.BlockRepository
+	notifications repositories.NotificationRepository
+}
+
+func NewShareService(shares repositories.ShareRepository, watchlists repositories.WatchlistRepository, users repositories.UserRepository, blocks repositories.BlockRepository, notifications repositories.NotificationRepository) *ShareService {
+	return &ShareService{shares: shares, watchlists: watchlists, users: users, blocks: blocks, notifications: notifications}
+}
+
+// SharedWatchlist is the part of a shared list the inbox shows.
+type SharedWatchlist struct {
+	ID         uuid.UUID  `json:"id"`
+	Title      string     `json:"title"`
+	Slug       string     `json:"slug"`
+	CoverURL   string     `json:"cover_url"`
+	ItemCount  int        `json:"item_count"`
+	Visibility string     `json:"visibility"`
+	Owner      PublicUser `json:"owner"`
+}
+
+// ReceivedShare is one entry in the shared-with-me inbox.
+type ReceivedShare struct {
+	ID        uuid.UUID       `json:"id"`
+	From      PublicUser      `json:"from"`
+	Watchlist SharedWatchlist `json:"watchlist"`
+	Message   string          `json:"message"`
+	CreatedAt time.Time       `json:"created_at"`
+}
+
+type SharePage struct {
+	Shares []ReceivedShare `json:"shares"`
+	Page   int             `json:"page"`
+	Limit  int             `json:"limit"`
+	Total  int64           `json:"total"`
+}
+
+// Share sends a watchlist to each of recipients with an optional message.
+// Owners may share any of their lists. Anyone else who can see a list may
+// share it when both the list and its owner's account are public or
+// unlisted: sharing doesn't widen who can see a private list or a private
+// account's lists, so only their owner can. Recipients blocked either way by
+// the sender or the owner are refused. Sending the same list to someone again
+// is a no-op, so the returned shares are only the new ones.
+func (s *ShareService) Share(ctx context.Context, from, watchlistID string, recipients []string, message string) ([]models.Share, error) {
+	if from == "" {
+		return nil, ErrUnauthorized
+	}
+	wl, err := s.watchlists.GetSummary(ctx, watchlistID)
+	if err != nil {
+		return nil, err
+	}
+	if err := s.checkCanShare(ctx, wl, from); err != nil {
+		return nil, err
+	}
+
+	ids, err := recipientIDs(from, recipients)
+	if err != nil {
+		return nil, err
+	}
+	users, err := s.users.GetByIDs(ctx, ids)
+	if err != nil {
+		return nil, err
+	}
+	if len(users) != len(ids) {
+		return nil, ErrUnknownRecipient
+	}
+	for _, id := range ids {
+		if err := checkBlocked(ctx, s.blocks, from, id.String()); err != nil {
+			return nil, err
+		}
+		if err := checkBlocked(ctx, s.blocks, wl.OwnerID, id.String()); err != nil {
+			return nil, err
+		}
+	}
+
+	sent, err := s.shares.SentTo(ctx, from, wl.ID.String(), ids)
+	if err != nil {
+		return nil, err
+	}
+	already := make(map[uuid.UUID]bool, len(sent))
+	for _, id := range sent {
+		already[id] = true
+	}
+	sender := uuid.MustParse(from)
+	message = strings.TrimSpace(message)
+	shares := make([]models.Share, 0, len(ids))
+	for _, id := range ids {
+		if !already[id] {
+			shares = append(shares, models.Share{FromUserID: sender, ToUserID: id, WatchlistID: wl.ID, Message: message})
+		}
+	}
+	if err := s.shares.Create(ctx, shares); err != nil {
+		return nil, err
+	}
+	for _, sh := range shares {
+		n := &models.Notification{UserID: sh.ToUserID, Type: models.NotificationShare, ActorID: sender, EntityID: wl.ID}
+		if err := s.notifications.Create(ctx, n); err != nil {
+			log.Printf("Failed to create %s notification: %v", models.NotificationShare, err)
+		}
+	}
+	return shares, nil
+}
+
+// checkCanShare returns gorm.ErrRecordNotFound when from can't see wl at
+// all and ErrForbidden when they can but may not share it.
+func (s *ShareService) checkCanShare(ctx context.Context, wl *models.Watchlist, from string) error {
+	if wl.OwnerID == from {
+		return nil
+	}
+	if err := checkBlocked(ctx, s.blocks, from, wl.OwnerID); errors.Is(err, ErrBlocked) {
+		return gorm.ErrRecordNotFound
+	} else if err != nil {
+		return err
+	}
+	if err := checkListVisible(ctx, s.watchlists, wl, from); err != nil {
+		return err
+	}
+	if wl.Visibility == models.PrivateVisibility {
+		return ErrForbidden
+	}
+	// Seen by everyone means the owner's account is public
+	public, err := s.watchlists.OwnerVisible(ctx, wl.OwnerID, "")
+	if err != nil {
+		return err
+	}
+	if !public {
+		return ErrForbidden
+	}
+	return nil
+}
+
+// recipientIDs parses and dedupes recipients, refusing the sender.
+func recipientIDs(from string, recipients []string) ([]uuid.UUID, error) {
+	seen := make(map[uuid.UUID]bool, len(recipients))
+	ids := make([]uuid.UUID, 0, len(recipients))
+	for _, r := range recipients {
+		id, err := uuid.Parse(r)
+		if err != nil {
+			return nil, ErrUnknownRecipient
+		}
+		if id.String() == from {
+			return nil, ErrShareSelf
+		}
+		if !seen[id] {
+			seen[id] = true
+			ids = append(ids, id)
+		}
+	}
+	if len(ids) == 0 {
+		return nil, ErrNoRecipients
+	}
+	return ids, nil
+}
+
+// Inbox pages through what has been shared with userID, newest first,
+// leaving out senders blocked either way.
+func (s *ShareService) Inbox(ctx context.Context, userID string, page, limit int) (*SharePage, error) {
+	if userID == "" {
+		return nil, ErrUnauthorized
+	}
+	if page < 1 {
+		page = 1
+	}
+	if limit <= 0 {
+		limit = defaultShareLimit
+	}
+	limit = min(limit, maxShareLimit)
+	hidden, err := hiddenFrom(ctx, s.blocks, userID, false)
+	if err != nil {
+		return nil, err
+	}
+	shares, total, err := s.shares.ListReceived(ctx, userID, hidden, limit, (page-1)*limit)
+	if err != nil {
+		return nil, err
+	}
+	out := &SharePage{Shares: make([]ReceivedShare, 0, len(shares)), Page: page, Limit: limit, Total: total}
+	for _, sh := range shares {
+		rs := ReceivedShare{ID: sh.ID, From: publicUser(sh.From), Message: sh.Message, CreatedAt: sh.CreatedAt}
+		if wl := sh.Watchlist; wl != nil {
+			rs.Watchlist = SharedWatchlist{
+				ID:         wl.ID,
+				Title:      wl.Title,
+				Slug:       wl.Slug,
+				CoverURL:   wl.CoverUrl,
+				ItemCount:  wl.ItemCount,
+				Visibility: wl.Visibility,
+				Owner:      publicUser(wl.Owner),
+			}
+		}
+		out.Shares = append(out.Shares, rs)
+	}
+	return out, nil
+}
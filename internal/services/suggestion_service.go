@@ -0,0 +1,202 @@
+package services
+
+import (
+	"context"
+	"fmt"
+	"sort"
+	"time"
+
+	"github.com/google/uuid"
+	"gorm.io/gorm"
+
+	"github.com/Dubjay18/scenee/internal/repositories"
+)
+
+const (
+	defaultSuggestionLimit = 10
+	maxSuggestionLimit     = 50
+	// suggestionPool is how many candidates each signal puts forward.
+	suggestionPool = 50
+	// Weaker overlap than this says more about popular films than taste.
+	minSharedFilms = 3
+	minCoRated     = 3
+	// popularActivity is how recently a fallback account must have updated
+	// a public list.
+	popularActivity = 30 * 24 * time.Hour
+)
+
+// Suggestion is an account to follow and why it was picked, strongest
+// reason first.
+type Suggestion struct {
+	User    PublicUser `json:"user"`
+	Reasons []string   `json:"reasons"`
+}
+
+// SuggestionService recommends accounts from three signals: who the people
+// you follow follow, films you both keep on watchlists and how closely your
+// review ratings agree. When those come up short, as they do for new users,
+// popular active accounts fill the rest.
+type SuggestionService struct {
+	suggestions repositories.SuggestionRepository
+	users       repositories.UserRepository
+	blocks      repositories.BlockRepository
+}
+
+func NewSuggestionService(suggestions repositories.SuggestionRepository, users repositories.UserRepository, blocks repositories.BlockRepository) *SuggestionService {
+	return &SuggestionService{suggestions: suggestions, users: users, blocks: blocks}
+}
+
+// suggestionScore accumulates one candidate's signals. Each signal scores
+// from 0 to its weight so no single one swamps the others.
+type suggestionScore struct {
+	score   float64
+	reasons []scoredReason
+}
+
+type scoredReason struct {
+	text  string
+	score float64
+}
+
+func (s *suggestionScore) add(score float64, reason string) {
+	s.score += score
+	s.reasons = append(s.reasons, scoredReason{text: reason, score: score})
+}
+
+// Suggest returns up to limit accounts for userID to follow, best first.
+// Blocked and muted users are never suggested.
+func (s *SuggestionService) Suggest(ctx context.Context, userID string, limit int) ([]Suggestion, error) {
+	if userID == "" {
+		return nil, ErrUnauthorized
+	}
+	if limit <= 0 {
+		limit = defaultSuggestionLimit
+	}
+	limit = min(limit, maxSuggestionLimit)
+	hidden, err := hiddenFrom(ctx, s.blocks, userID, true)
+	if err != nil {
+		return nil, err
+	}
+	q := repositories.SuggestionQuery{UserID: userID, Exclude: hidden, Limit: suggestionPool}
+
+	scores := map[uuid.UUID]*suggestionScore{}
+	score := func(id uuid.UUID) *suggestionScore {
+		if scores[id] == nil {
+			scores[id] = &suggestionScore{}
+		}
+		return scores[id]
+	}
+
+	mutuals, err := s.suggestions.FriendsOfFriends(ctx, q)
+	if err != nil {
+		return nil, err
+	}
+	for _, c := range mutuals {
+		reason := fmt.Sprintf("%d mutual follows", c.Mutuals)
+		if c.Mutuals == 1 {
+			reason = "1 mutual follow"
+		}
+		score(c.UserID).add(0.4*min(float64(c.Mutuals)/5, 1), reason)
+	}
+
+	overlaps, err := s.suggestions.WatchlistOverlap(ctx, q, minSharedFilms)
+	if err != nil {
+		return nil, err
+	}
+	for _, c := range overlaps {
+		// A quarter of both lists combined is already a strong overlap.
+		score(c.UserID).add(0.3*min(c.Jaccard/0.25, 1), fmt.Sprintf("%d films in common on your watchlists", c.SharedFilms))
+	}
+
+	matches, err := s.suggestions.TasteMatches(ctx, q, minCoRated)
+	if err != nil {
+		return nil, err
+	}
+	for _, c := range matches {
+		// Strangers agree about half the time, so only agreement above
+		// that counts, and more so the more films it rests on.
+		taste := 0.3 * max(0, 2*(c.Agreement-0.5)) * min(float64(c.CoRated)/10, 1)
+		if taste == 0 {
+			continue
+		}
+		reason := fmt.Sprintf("similar ratings on %d films", c.CoRated)
+		if c.LovedDirector != "" {
+			reason = "you both love " + c.LovedDirector
+		}
+		score(c.UserID).add(taste, reason)
+	}
+
+	if len(scores) < limit {
+		popular, err := s.suggestions.Popular(ctx, q, time.Now().Add(-popularActivity))
+		if err != nil {
+			return nil, err
+		}
+		for _, c := range popular {
+			if len(scores) >= limit {
+				break
+			}
+			if scores[c.UserID] != nil {
+				continue
+			}
+			// Scores below any real signal, so these only fill gaps.
+			reason := "active on Scenee"
+			if c.Followers > 1 {
+				reason = fmt.Sprintf("popular: %d followers", c.Followers)
+			}
+			score(c.UserID).add(0.01*min(float64(c.Followers)/1000, 1), reason)
+		}
+	}
+
+	ids := make([]uuid.UUID, 0, len(scores))
+	for id := range scores {
+		ids = append(ids, id)
+	}
+	sort.Slice(ids, func(i, j int) bool {
+		a, b := scores[ids[i]].score, scores[ids[j]].score
+		if a != b {
+			return a > b
+		}
+		return ids[i].String() < ids[j].String()
+	})
+	if len(ids) > limit {
+		ids = ids[:limit]
+	}
+	users, err := s.users.GetByIDs(ctx, ids)
+	if err != nil {
+		return nil, err
+	}
+	byID := make(map[uuid.UUID]PublicUser, len(users))
+	for _, u := range users {
+		byID[u.ID] = publicUser(u)
+	}
+
+	out := make([]Suggestion, 0, len(ids))
+	for _, id := range ids {
+		u, ok := byID[id]
+		if !ok {
+			continue
+		}
+		rs := scores[id].reasons
+		sort.SliceStable(rs, func(i, j int) bool { return rs[i].score > rs[j].score })
+		reasons := make([]string, 0, len(rs))
+		for _, r := range rs {
+			reasons = append(reasons, r.text)
+		}
+		out = append(out, Suggestion{User: u, Reasons: reasons})
+	}
+	return out, nil
+}
+
+// Dismiss stops suggesting dismissedID to userID.
+func (s *SuggestionService) Dismiss(ctx context.Context, userID, dismissedID string) error {
+	if userID == "" {
+		return ErrUnauthorized
+	}
+	if _, err := uuid.Parse(dismissedID); err != nil || dismissedID == userID {
+		return gorm.ErrRecordNotFound
+	}
+	if _, err := s.users.GetByID(ctx, dismissedID); err != nil {
+		return err
+	}
+	return s.suggestions.Dismiss(ctx, userID, dismissedID)
+}
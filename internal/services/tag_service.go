@@ -0,0 +1,121 @@
+package services
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"log"
+	"time"
+
+	"github.com/Dubjay18/scenee/internal/cache"
+	"github.com/Dubjay18/scenee/internal/models"
+	"github.com/Dubjay18/scenee/internal/repositories"
+	"github.com/Dubjay18/scenee/internal/tags"
+)
+
+type TagService struct {
+	tags     repositories.TagRepository
+	popCache *cache.TTLCache[int, []repositories.TagCount]
+}
+
+func NewTagService(repo repositories.TagRepository) *TagService {
+	return &TagService{
+		tags:     repo,
+		popCache: cache.NewTTL[int, []repositories.TagCount](5 * time.Minute),
+	}
+}
+
+// Autocomplete suggests existing tags for a partially typed one.
+func (s *TagService) Autocomplete(ctx context.Context, q string, limit int) ([]repositories.TagCount, error) {
+	prefix := tags.Normalize(q)
+	if prefix == "" {
+		return []repositories.TagCount{}, nil
+	}
+	return s.tags.Search(ctx, prefix, limit)
+}
+
+func (s *TagService) Popular(ctx context.Context, limit int) ([]repositories.TagCount, error) {
+	if out, ok := s.popCache.Get(limit); ok {
+		return out, nil
+	}
+	out, err := s.tags.Popular(ctx, limit)
+	if err != nil {
+		return nil, err
+	}
+	s.popCache.Set(limit, out)
+	return out, nil
+}
+
+type TagWatchlistsPage struct {
+	Tag   string             `json:"tag"`
+	Items []models.Watchlist `json:"items"`
+	Page  int                `json:"page"`
+	Limit int                `json:"limit"`
+	Total int64              `json:"total"`
+}
+
+// Watchlists lists public watchlists tagged with tag (normalized first).
+func (s *TagService) Watchlists(ctx context.Context, tag, sort string, page, limit int) (*TagWatchlistsPage, error) {
+	t := tags.Normalize(tag)
+	out := &TagWatchlistsPage{Tag: t, Items: []models.Watchlist{}, Page: page, Limit: limit}
+	if t == "" {
+		return out, nil
+	}
+	items, total, err := s.tags.Watchlists(ctx, t, sort, limit, (page-1)*limit)
+	if err != nil {
+		return nil, err
+	}
+	out.Items, out.Total = items, total
+	return out, nil
+}
+
+// normalizeBatch bounds how many watchlists NormalizeStored loads at once.
+const normalizeBatch = 500
+
+// NormalizeStored rewrites tags saved before normalization was enforced into
+// the form tags.Normalize gives, keeping the first MaxTags distinct ones, so
+// they match tags added through the API. Lists already normalized are
+// skipped, so after the first run this finds nothing to do.
+func (s *TagService) NormalizeStored(ctx context.Context) error {
+	fixed := 0
+	for {
+		rows, err := s.tags.Unnormalized(ctx, normalizeBatch)
+		if err != nil {
+			return err
+		}
+		for _, row := range rows {
+			if err := s.tags.SetTags(ctx, row.ID, normalizeStored(row.Tags)); err != nil {
+				return err
+			}
+		}
+		fixed += len(rows)
+		if len(rows) < normalizeBatch {
+			break
+		}
+	}
+	if fixed > 0 {
+		log.Printf("normalized the tags of %d watchlists", fixed)
+	}
+	return nil
+}
+
+func normalizeStored(raw []byte) []string {
+	var in []any
+	_ = json.Unmarshal(raw, &in)
+	out := make([]string, 0, min(len(in), tags.MaxTags))
+	seen := make(map[string]bool, len(in))
+	for _, v := range in {
+		if v == nil {
+			continue
+		}
+		t := tags.Normalize(fmt.Sprint(v))
+		if t == "" || seen[t] {
+			continue
+		}
+		seen[t] = true
+		if out = append(out, t); len(out) == tags.MaxTags {
+			break
+		}
+	}
+	return out
+}
@@ -0,0 +1,93 @@
+package services
+
+import (
+	"context"
+	"slices"
+	"strings"
+	"time"
+
+	"github.com/google/uuid"
+
+	"github.com/Dubjay18/scenee/internal/models"
+	"github.com/Dubjay18/scenee/internal/repositories"
+)
+
+// trendingWindows maps each trending window to its decay parameters. Shorter
+// windows forget faster so they surface what is hot right now.
+var trendingWindows = map[string]repositories.RankingParams{
+	"day":   {HalfLife: 6 * time.Hour, Lookback: 3 * 24 * time.Hour},
+	"week":  {HalfLife: 2 * 24 * time.Hour, Lookback: 14 * 24 * time.Hour},
+	"month": {HalfLife: 7 * 24 * time.Hour, Lookback: 60 * 24 * time.Hour},
+}
+
+// longestLookback is the furthest back any of windows reads events.
+func longestLookback(windows map[string]repositories.RankingParams) time.Duration {
+	var d time.Duration
+	for _, p := range windows {
+		d = max(d, p.Lookback)
+	}
+	return d
+}
+
+// Signal weights: a fork or save says more than a like, and a view is cheap.
+const (
+	trendingLikeWeight = 3
+	trendingSaveWeight = 4
+	trendingViewWeight = 0.25
+	trendingForkWeight = 5
+
+	trendingPerScope = 200
+)
+
+// TrendingWatchlists returns the precomputed trending lists for window,
+// optionally narrowed to a genre or a tag. Lists by users the viewer has
+// blocked, been blocked by or muted are left out.
+func (s *WatchlistService) TrendingWatchlists(ctx context.Context, viewer, window, genre, tag string, limit int) ([]models.Watchlist, error) {
+	if _, ok := trendingWindows[window]; !ok {
+		window = "week"
+	}
+	dimension, value := "all", ""
+	switch {
+	case genre != "":
+		dimension, value = "genre", strings.ToLower(strings.TrimSpace(genre))
+	case tag != "":
+		dimension, value = "tag", strings.ToLower(strings.TrimSpace(tag))
+	}
+	hidden, err := hiddenFrom(ctx, s.blocks, viewer, true)
+	if err != nil {
+		return nil, err
+	}
+	// Over-fetch so hidden lists don't shorten the page.
+	lists, err := s.rankings.Top(ctx, window, dimension, value, limit+len(hidden))
+	if err != nil || len(hidden) == 0 {
+		return lists, err
+	}
+	out := make([]models.Watchlist, 0, limit)
+	for _, wl := range lists {
+		if len(out) == limit {
+			break
+		}
+		if owner, err := uuid.Parse(wl.OwnerID); err == nil && slices.Contains(hidden, owner) {
+			continue
+		}
+		out = append(out, wl)
+	}
+	return out, nil
+}
+
+// RecomputeTrending rebuilds the ranking table for every window. It runs as
+// a periodic job.
+func (s *WatchlistService) RecomputeTrending(ctx context.Context) error {
+	for window, p := range trendingWindows {
+		p.Window = window
+		p.LikeWeight = trendingLikeWeight
+		p.SaveWeight = trendingSaveWeight
+		p.ViewWeight = trendingViewWeight
+		p.ForkWeight = trendingForkWeight
+		p.PerScope = trendingPerScope
+		if err := s.rankings.Recompute(ctx, p); err != nil {
+			return err
+		}
+	}
+	return nil
+}
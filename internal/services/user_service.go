@@ -2,17 +2,55 @@ package services
 
 import (
 	"context"
+	"strings"
+
+	"github.com/google/uuid"
 
 	"github.com/Dubjay18/scenee/internal/models"
 	"github.com/Dubjay18/scenee/internal/repositories"
 )
 
 type UserService struct {
-	users repositories.UserRepository
+	users  repositories.UserRepository
+	blocks repositories.BlockRepository
+}
+
+func NewUserService(users repositories.UserRepository, blocks repositories.BlockRepository) *UserService {
+	return &UserService{users: users, blocks: blocks}
+}
+
+// PublicUser is the part of an account other users may see.
+type PublicUser struct {
+	ID        uuid.UUID `json:"id"`
+	Username  string    `json:"username"`
+	Bio       string    `json:"bio"`
+	AvatarURL string    `json:"avatar_url"`
+}
+
+func publicUser(u models.User) PublicUser {
+	return PublicUser{ID: u.ID, Username: u.Username, Bio: u.Bio, AvatarURL: u.AvatarUrl}
+}
+
+func publicUsers(users []models.User) []PublicUser {
+	out := make([]PublicUser, 0, len(users))
+	for _, u := range users {
+		out = append(out, publicUser(u))
+	}
+	return out
 }
 
-func NewUserService(users repositories.UserRepository) *UserService {
-	return &UserService{users: users}
+// SearchUsers finds users by username, leaving out anyone viewer has
+// blocked or been blocked by.
+func (s *UserService) SearchUsers(ctx context.Context, viewer, query string, limit, offset int) ([]PublicUser, error) {
+	hidden, err := hiddenFrom(ctx, s.blocks, viewer, false)
+	if err != nil {
+		return nil, err
+	}
+	users, err := s.users.Search(ctx, strings.TrimSpace(query), hidden, limit, offset)
+	if err != nil {
+		return nil, err
+	}
+	return publicUsers(users), nil
 }
 
 func (s *UserService) Upsert(ctx context.Context, user *models.User) error {
@@ -23,6 +61,11 @@ func (s *UserService) GetByID(ctx context.Context, id string) (*models.User, err
 	return s.users.GetByID(ctx, id)
 }
 
+// GetByUsername finds a user by username, ignoring case.
+func (s *UserService) GetByUsername(ctx context.Context, username string) (*models.User, error) {
+	return s.users.GetByUsername(ctx, username)
+}
+
 func (s *UserService) GetByEmail(ctx context.Context, email string) (*models.User, error) {
 	return s.users.GetByEmail(ctx, email)
 }
@@ -0,0 +1,248 @@
+package services
+
+import (
+	"context"
+	"errors"
+	"sync"
+
+	"github.com/Dubjay18/scenee/internal/domain"
+	"github.com/Dubjay18/scenee/internal/models"
+	"github.com/Dubjay18/scenee/internal/repositories"
+	"github.com/Dubjay18/scenee/internal/tmdb"
+	"github.com/google/uuid"
+)
+
+// batchFetchWorkers bounds concurrent TMDb lookups for a batch add.
+const batchFetchWorkers = 8
+
+// Per-operation outcomes reported by BatchItems.
+const (
+	BatchAdded     = "added"
+	BatchRemoved   = "removed"
+	BatchMoved     = "moved"
+	BatchDuplicate = "duplicate"
+	BatchNotFound  = "not_found"
+	BatchFailed    = "failed"
+)
+
+type BatchAdd struct {
+	TMDBID int `json:"tmdb_id" validate:"required,gt=0"`
+	// MediaType is models.MediaMovie (the default) or models.MediaTV.
+	MediaType string `json:"media_type" validate:"omitempty,oneof=movie tv"`
+	Notes     string `json:"notes" validate:"max=1000"`
+}
+
+// titleKey identifies a TMDB title; movie and series IDs overlap.
+type titleKey struct {
+	MediaType string
+	TMDBID    int
+}
+
+func (a BatchAdd) key() titleKey {
+	if a.MediaType == "" {
+		return titleKey{models.MediaMovie, a.TMDBID}
+	}
+	return titleKey{a.MediaType, a.TMDBID}
+}
+
+type BatchMove struct {
+	ItemID   uuid.UUID `json:"item_id" validate:"required"`
+	Position int       `json:"position" validate:"gte=0"`
+}
+
+type BatchItemsInput struct {
+	Add    []BatchAdd  `json:"add" validate:"max=100,dive"`
+	Remove []uuid.UUID `json:"remove" validate:"max=100"`
+	Move   []BatchMove `json:"move" validate:"max=100,dive"`
+}
+
+type BatchItemResult struct {
+	Op        string     `json:"op"`
+	TMDBID    int        `json:"tmdb_id,omitempty"`
+	MediaType string     `json:"media_type,omitempty"`
+	ItemID    *uuid.UUID `json:"item_id,omitempty"`
+	Position  *int       `json:"position,omitempty"`
+	Status    string     `json:"status"`
+	Error     string     `json:"error,omitempty"`
+}
+
+type BatchItemsResult struct {
+	Results   []BatchItemResult `json:"results"`
+	ItemCount int               `json:"item_count"`
+}
+
+// BatchItems adds, removes and moves many items in a single transaction.
+// Movies not cached yet are fetched from TMDb concurrently beforehand; a
+// failed lookup only fails that entry.
+func (s *WatchlistService) BatchItems(ctx context.Context, owner, watchlistID string, in BatchItemsInput) (*BatchItemsResult, error) {
+	if owner == "" {
+		return nil, ErrUnauthorized
+	}
+	if err := s.watchlists.EnsureOwner(ctx, watchlistID, owner); err != nil {
+		return nil, err
+	}
+	if err := s.ensureManual(ctx, watchlistID); err != nil {
+		return nil, err
+	}
+
+	out := &BatchItemsResult{}
+	addResults := make([]BatchItemResult, len(in.Add))
+	seen := make(map[titleKey]bool, len(in.Add))
+	var keys []titleKey
+	for i, a := range in.Add {
+		k := a.key()
+		addResults[i] = BatchItemResult{Op: "add", TMDBID: a.TMDBID, MediaType: k.MediaType}
+		if seen[k] {
+			addResults[i].Status = BatchDuplicate
+			continue
+		}
+		seen[k] = true
+		keys = append(keys, k)
+	}
+	movies, fetchErrs := s.fetchMovies(ctx, keys)
+
+	var batch repositories.ItemBatch
+	movieToAdd := make(map[uuid.UUID]int, len(in.Add)) // movie ID -> index into in.Add
+	for i, a := range in.Add {
+		if addResults[i].Status != "" {
+			continue
+		}
+		k := a.key()
+		mv, ok := movies[k]
+		if !ok {
+			addResults[i].Status = BatchFailed
+			if err := fetchErrs[k]; err != nil {
+				if errors.Is(err, tmdb.ErrNotFound) {
+					addResults[i].Status = BatchNotFound
+				}
+				addResults[i].Error = err.Error()
+			}
+			continue
+		}
+		if _, dup := movieToAdd[mv.ID]; dup {
+			addResults[i].Status = BatchDuplicate
+			continue
+		}
+		movieToAdd[mv.ID] = i
+		batch.Add = append(batch.Add, models.WatchlistItem{ID: uuid.New(), MovieID: mv.ID, Note: a.Notes, MediaType: k.MediaType})
+	}
+	batch.Remove = in.Remove
+	for _, m := range in.Move {
+		batch.Move = append(batch.Move, repositories.ItemMove{ItemID: m.ItemID, Position: m.Position})
+	}
+
+	wlID := uuid.MustParse(watchlistID)
+	var res *repositories.ItemBatchResult
+	err := s.change(ctx, func(watchlists repositories.WatchlistRepository) ([]models.WatchlistRevision, error) {
+		var err error
+		if res, err = watchlists.ApplyItemBatch(ctx, watchlistID, owner, batch); err != nil {
+			return nil, err
+		}
+		return batchRevisions(wlID, owner, res), nil
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	for movieID := range res.Duplicate {
+		addResults[movieToAdd[movieID]].Status = BatchDuplicate
+	}
+	for i := range res.Added {
+		it := res.Added[i]
+		r := &addResults[movieToAdd[it.MovieID]]
+		r.Status, r.ItemID, r.Position = BatchAdded, &it.ID, &it.Position
+	}
+	out.Results = append(out.Results, addResults...)
+
+	removed := make(map[uuid.UUID]models.WatchlistItem, len(res.Removed))
+	for _, it := range res.Removed {
+		removed[it.ID] = it
+	}
+	for _, id := range in.Remove {
+		r := BatchItemResult{Op: "remove", ItemID: &id, Status: BatchNotFound}
+		if _, ok := removed[id]; ok {
+			r.Status = BatchRemoved
+			delete(removed, id)
+		}
+		out.Results = append(out.Results, r)
+	}
+
+	moved := make(map[uuid.UUID]repositories.MovedItem, len(res.Moved))
+	for _, m := range res.Moved {
+		moved[m.Item.ID] = m
+	}
+	for _, m := range in.Move {
+		r := BatchItemResult{Op: "move", ItemID: &m.ItemID, Status: BatchNotFound}
+		if mi, ok := moved[m.ItemID]; ok {
+			r.Status, r.Position = BatchMoved, &mi.Item.Position
+			delete(moved, m.ItemID)
+		}
+		out.Results = append(out.Results, r)
+	}
+	out.ItemCount = res.ItemCount
+	if len(res.Added)+len(res.Removed)+len(res.Moved) > 0 {
+		s.touchCover(wlID)
+	}
+	return out, nil
+}
+
+// batchRevisions records a batch in the order it was applied: removals,
+// then additions, then moves.
+func batchRevisions(watchlistID uuid.UUID, owner string, res *repositories.ItemBatchResult) []models.WatchlistRevision {
+	revs := make([]models.WatchlistRevision, 0, len(res.Removed)+len(res.Added)+len(res.Moved))
+	for i := range res.Removed {
+		it := &res.Removed[i]
+		revs = append(revs, revision(watchlistID, owner, models.RevisionRemoveItem, &it.ID, models.SnapshotItem(it), nil))
+	}
+	for i := range res.Added {
+		it := &res.Added[i]
+		revs = append(revs, revision(watchlistID, owner, models.RevisionAddItem, &it.ID, nil, models.SnapshotItem(it)))
+	}
+	for _, mi := range res.Moved {
+		if mi.From == mi.Item.Position {
+			continue
+		}
+		before := mi.Item
+		before.Position = mi.From
+		revs = append(revs, revision(watchlistID, owner, models.RevisionMoveItem, &mi.Item.ID, models.SnapshotItem(&before), models.SnapshotItem(&mi.Item)))
+	}
+	return revs
+}
+
+// fetchMovies resolves TMDb titles to cached movies and series using a
+// bounded worker pool.
+func (s *WatchlistService) fetchMovies(ctx context.Context, keys []titleKey) (map[titleKey]*domain.Movie, map[titleKey]error) {
+	movies := make(map[titleKey]*domain.Movie, len(keys))
+	errs := make(map[titleKey]error)
+	var mu sync.Mutex
+	var wg sync.WaitGroup
+	jobs := make(chan titleKey)
+	for w := 0; w < min(batchFetchWorkers, len(keys)); w++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			for id := range jobs {
+				mv, err := s.msvc.GetTitle(ctx, id.MediaType, id.TMDBID)
+				mu.Lock()
+				if err != nil {
+					errs[id] = err
+				} else {
+					movies[id] = mv
+				}
+				mu.Unlock()
+			}
+		}()
+	}
+	for _, id := range keys {
+		if err := ctx.Err(); err != nil {
+			mu.Lock()
+			errs[id] = err
+			mu.Unlock()
+			continue
+		}
+		jobs <- id
+	}
+	close(jobs)
+	wg.Wait()
+	return movies, errs
+}
@@ -0,0 +1,267 @@
+package services
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"log"
+	"slices"
+	"time"
+
+	"github.com/Dubjay18/scenee/internal/models"
+	"github.com/Dubjay18/scenee/internal/repositories"
+	"github.com/google/uuid"
+)
+
+// HistoryRetention is how long deleted watchlists can be restored and
+// revisions reverted to. Older revisions are purged.
+const HistoryRetention = 30 * 24 * time.Hour
+
+const (
+	defaultHistoryLimit = 50
+	maxHistoryLimit     = 200
+)
+
+var (
+	ErrRetentionExpired = errors.New("revision is older than the retention window")
+	ErrNotRevertible    = errors.New("revision cannot be reverted")
+)
+
+// revision describes a change actor made to a watchlist or one of its items.
+func revision(watchlistID uuid.UUID, actor, action string, itemID *uuid.UUID, before, after any) models.WatchlistRevision {
+	actorID, _ := uuid.Parse(actor)
+	return models.WatchlistRevision{
+		WatchlistID: watchlistID,
+		ActorID:     actorID,
+		Action:      action,
+		ItemID:      itemID,
+		Before:      models.EncodeSnapshot(before),
+		After:       models.EncodeSnapshot(after),
+	}
+}
+
+// change runs fn in a transaction and records the revisions it returns in
+// the same one, so a change is never saved without its history.
+func (s *WatchlistService) change(ctx context.Context, fn func(watchlists repositories.WatchlistRepository) ([]models.WatchlistRevision, error)) error {
+	return s.watchlists.Tx(ctx, func(watchlists repositories.WatchlistRepository, history repositories.WatchlistHistoryRepository) error {
+		revs, err := fn(watchlists)
+		if err != nil {
+			return err
+		}
+		for i := range revs {
+			if err := history.Record(ctx, &revs[i]); err != nil {
+				return err
+			}
+		}
+		return nil
+	})
+}
+
+// History returns the owner's view of a watchlist's change log, newest first.
+func (s *WatchlistService) History(ctx context.Context, owner, id string, beforeSeq int64, limit int) ([]models.WatchlistRevision, error) {
+	if owner == "" {
+		return nil, ErrUnauthorized
+	}
+	if err := s.watchlists.EnsureOwner(ctx, id, owner); err != nil {
+		return nil, err
+	}
+	if limit <= 0 {
+		limit = defaultHistoryLimit
+	}
+	return s.history.List(ctx, id, beforeSeq, min(limit, maxHistoryLimit))
+}
+
+// PurgeHistory deletes revisions older than HistoryRetention, which can no
+// longer be reverted to.
+func (s *WatchlistService) PurgeHistory(ctx context.Context) error {
+	n, err := s.history.Purge(ctx, time.Now().Add(-HistoryRetention))
+	if err != nil {
+		return err
+	}
+	if n > 0 {
+		log.Printf("purged %d watchlist revisions past retention", n)
+	}
+	return nil
+}
+
+// RestoreWatchlist undeletes a watchlist deleted within HistoryRetention.
+func (s *WatchlistService) RestoreWatchlist(ctx context.Context, owner, id string) (*models.Watchlist, error) {
+	if owner == "" {
+		return nil, ErrUnauthorized
+	}
+	wl, err := s.watchlists.GetDeleted(ctx, id, owner)
+	if err != nil {
+		return nil, err
+	}
+	if time.Since(wl.DeletedAt.Time) > HistoryRetention {
+		return nil, ErrRetentionExpired
+	}
+	err = s.change(ctx, func(watchlists repositories.WatchlistRepository) ([]models.WatchlistRevision, error) {
+		if err := watchlists.Restore(ctx, id, owner); err != nil {
+			return nil, err
+		}
+		return []models.WatchlistRevision{revision(wl.ID, owner, models.RevisionRestore, nil, nil, models.SnapshotWatchlist(wl))}, nil
+	})
+	if err != nil {
+		return nil, err
+	}
+	return s.watchlists.GetByID(ctx, id)
+}
+
+// RevertRevision puts a watchlist back the way it was right after revision
+// revisionID, undoing every later change to its metadata and items. The
+// revert is itself a revision, so it can be reverted too. Smart lists only
+// get their metadata and rules back; their items follow the rules.
+func (s *WatchlistService) RevertRevision(ctx context.Context, owner, id, revisionID string) (*models.Watchlist, error) {
+	if owner == "" {
+		return nil, ErrUnauthorized
+	}
+	wl, err := s.watchlists.GetByID(ctx, id)
+	if err != nil {
+		return nil, err
+	}
+	if wl.OwnerID != owner {
+		return nil, ErrForbidden
+	}
+	rev, err := s.history.Get(ctx, id, revisionID)
+	if err != nil {
+		return nil, err
+	}
+	if time.Since(rev.CreatedAt) > HistoryRetention {
+		return nil, ErrRetentionExpired
+	}
+	later, err := s.history.After(ctx, rev)
+	if err != nil {
+		return nil, err
+	}
+
+	current := snapshotList(wl)
+	target, err := undoRevisions(current, later)
+	if err != nil {
+		return nil, err
+	}
+	if wl.Kind == models.SmartWatchlist {
+		target.Items = current.Items
+	}
+	metaChanged := string(models.EncodeSnapshot(target.Watchlist)) != string(models.EncodeSnapshot(current.Watchlist))
+	itemsChanged := !slices.Equal(target.Items, current.Items)
+	if !metaChanged && !itemsChanged {
+		return wl, nil
+	}
+	if target.Watchlist.Slug != wl.Slug {
+		taken, err := s.watchlists.SlugTaken(ctx, target.Watchlist.Slug, id)
+		if err != nil {
+			return nil, err
+		}
+		if taken {
+			return nil, ErrSlugTaken
+		}
+	}
+
+	err = s.change(ctx, func(watchlists repositories.WatchlistRepository) ([]models.WatchlistRevision, error) {
+		if metaChanged {
+			reverted := *wl
+			target.Watchlist.Apply(&reverted)
+			if err := watchlists.Update(ctx, &reverted); err != nil {
+				return nil, err
+			}
+		}
+		if itemsChanged {
+			items := make([]models.WatchlistItem, len(target.Items))
+			for i, it := range target.Items {
+				items[i] = models.WatchlistItem{ID: it.ID, MovieID: it.MovieID, Note: it.Note, MediaType: it.MediaType, AddedAt: time.Now()}
+				if items[i].MediaType == "" {
+					items[i].MediaType = models.MediaMovie // snapshots from before series
+				}
+			}
+			if err := watchlists.ReplaceItems(ctx, id, owner, items); err != nil {
+				return nil, err
+			}
+		}
+		return []models.WatchlistRevision{revision(wl.ID, owner, models.RevisionRevert, nil, current, target)}, nil
+	})
+	if err != nil {
+		return nil, err
+	}
+	if itemsChanged {
+		s.touchCover(wl.ID)
+	}
+	if wl.Kind == models.SmartWatchlist && string(target.Watchlist.Rules) != string(current.Watchlist.Rules) {
+		return s.materialize(ctx, wl, true)
+	}
+	return s.watchlists.GetByID(ctx, id)
+}
+
+// snapshotList captures wl with its items, which must be loaded in order.
+func snapshotList(wl *models.Watchlist) models.ListSnapshot {
+	snap := models.ListSnapshot{Watchlist: models.SnapshotWatchlist(wl), Items: make([]models.ItemSnapshot, len(wl.Items))}
+	for i := range wl.Items {
+		snap.Items[i] = models.SnapshotItem(&wl.Items[i])
+		snap.Items[i].Position = i
+	}
+	return snap
+}
+
+// undoRevisions works back from state through revs, newest first, to the
+// state before the oldest of them. Item positions are renumbered from 0.
+func undoRevisions(state models.ListSnapshot, revs []models.WatchlistRevision) (models.ListSnapshot, error) {
+	meta, items := state.Watchlist, slices.Clone(state.Items)
+	indexOf := func(id uuid.UUID) int {
+		return slices.IndexFunc(items, func(it models.ItemSnapshot) bool { return it.ID == id })
+	}
+	for _, rev := range revs {
+		switch rev.Action {
+		case models.RevisionUpdate:
+			var prev models.WatchlistSnapshot
+			if err := json.Unmarshal(rev.Before, &prev); err != nil {
+				return state, ErrNotRevertible
+			}
+			meta = prev
+		case models.RevisionRevert:
+			var prev models.ListSnapshot
+			if err := json.Unmarshal(rev.Before, &prev); err != nil {
+				return state, ErrNotRevertible
+			}
+			meta, items = prev.Watchlist, slices.Clone(prev.Items)
+		case models.RevisionAddItem:
+			if rev.ItemID == nil {
+				return state, ErrNotRevertible
+			}
+			if i := indexOf(*rev.ItemID); i >= 0 {
+				items = slices.Delete(items, i, i+1)
+			}
+		case models.RevisionRemoveItem, models.RevisionMoveItem:
+			var prev models.ItemSnapshot
+			if err := json.Unmarshal(rev.Before, &prev); err != nil {
+				return state, ErrNotRevertible
+			}
+			i := indexOf(prev.ID)
+			if i < 0 && rev.Action == models.RevisionMoveItem {
+				continue // removed later on and not put back
+			}
+			if i >= 0 {
+				if rev.Action == models.RevisionMoveItem {
+					prev.Note = items[i].Note // moving doesn't change the note
+				}
+				items = slices.Delete(items, i, i+1)
+			}
+			items = slices.Insert(items, max(0, min(prev.Position, len(items))), prev)
+		case models.RevisionEditNote:
+			var prev models.ItemSnapshot
+			if err := json.Unmarshal(rev.Before, &prev); err != nil {
+				return state, ErrNotRevertible
+			}
+			if i := indexOf(prev.ID); i >= 0 {
+				items[i].Note = prev.Note
+			}
+		case models.RevisionCreate, models.RevisionDelete, models.RevisionRestore:
+			// The list exists now; nothing about its contents to undo.
+		default:
+			return state, ErrNotRevertible
+		}
+	}
+	for i := range items {
+		items[i].Position = i
+	}
+	return models.ListSnapshot{Watchlist: meta, Items: items}, nil
+}
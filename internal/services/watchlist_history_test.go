@@ -0,0 +1,56 @@
+package services
+
+import (
+	"slices"
+	"testing"
+
+	"github.com/google/uuid"
+
+	"github.com/Dubjay18/scenee/internal/models"
+)
+
+func TestUndoRevisionsRestoresEarlierState(t *testing.T) {
+	wl := uuid.New()
+	owner := uuid.NewString()
+	a := models.ItemSnapshot{ID: uuid.New(), MovieID: uuid.New(), Note: "first", MediaType: models.MediaMovie}
+	b := models.ItemSnapshot{ID: uuid.New(), MovieID: uuid.New(), MediaType: models.MediaMovie}
+	c := models.ItemSnapshot{ID: uuid.New(), MovieID: uuid.New(), MediaType: models.MediaTV}
+	at := func(it models.ItemSnapshot, pos int) models.ItemSnapshot { it.Position = pos; return it }
+	meta := models.WatchlistSnapshot{Title: "Horror", Slug: "horror"}
+
+	// The list was [a b] titled Horror. Since then: c added, a moved to the
+	// end, b removed, a's note edited and the list renamed.
+	renamed := meta
+	renamed.Title, renamed.Slug = "Scary", "scary"
+	edited := at(a, 1)
+	edited.Note = "second"
+	since := []models.WatchlistRevision{ // newest first, as History.After returns them
+		revision(wl, owner, models.RevisionUpdate, nil, meta, renamed),
+		revision(wl, owner, models.RevisionEditNote, &a.ID, at(a, 1), edited),
+		revision(wl, owner, models.RevisionRemoveItem, &b.ID, at(b, 0), nil),
+		revision(wl, owner, models.RevisionMoveItem, &a.ID, at(a, 0), at(a, 2)),
+		revision(wl, owner, models.RevisionAddItem, &c.ID, nil, at(c, 2)),
+	}
+	now := models.ListSnapshot{Watchlist: renamed, Items: []models.ItemSnapshot{at(c, 0), edited}}
+
+	got, err := undoRevisions(now, since)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if got.Watchlist.Title != "Horror" || got.Watchlist.Slug != "horror" {
+		t.Errorf("metadata = %+v, want the Horror list back", got.Watchlist)
+	}
+	if want := []models.ItemSnapshot{at(a, 0), at(b, 1)}; !slices.Equal(got.Items, want) {
+		t.Errorf("items = %+v, want %+v", got.Items, want)
+	}
+
+	// Undoing a revert puts back whatever it replaced
+	reverted := revision(wl, owner, models.RevisionRevert, nil, now, got)
+	back, err := undoRevisions(got, []models.WatchlistRevision{reverted})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if back.Watchlist.Title != "Scary" || !slices.Equal(back.Items, now.Items) {
+		t.Errorf("undoing the revert = %+v, want %+v", back, now)
+	}
+}
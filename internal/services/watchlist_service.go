@@ -5,59 +5,118 @@ import (
 	"encoding/json"
 	"errors"
 	"fmt"
+	"log"
+	"slices"
+	"strconv"
+	"strings"
 	"time"
 
 	"github.com/Dubjay18/scenee/internal/cache"
 	"github.com/Dubjay18/scenee/internal/domain"
 	"github.com/Dubjay18/scenee/internal/models"
 	"github.com/Dubjay18/scenee/internal/repositories"
+	"github.com/Dubjay18/scenee/internal/slug"
+	"github.com/Dubjay18/scenee/internal/tags"
 	"github.com/Dubjay18/scenee/internal/tmdb"
 	"github.com/google/uuid"
+	"gorm.io/gorm"
 )
 
 var (
 	ErrUnauthorized = errors.New("unauthorized")
 	ErrForbidden    = errors.New("forbidden")
+	ErrSlugTaken    = errors.New("slug already taken")
+	ErrInvalidSlug  = errors.New("slug must contain only lowercase letters, digits and single hyphens")
+
+	ErrSmartListReadOnly = errors.New("smart watchlists are computed from rules; edit the rules instead")
+	ErrNotSmartList      = errors.New("watchlist is not a smart watchlist")
+	ErrCannotSaveOwn     = errors.New("cannot save your own watchlist")
 )
 
+// smartListTTL is how long a materialized smart list is served before its
+// rules are re-evaluated on read.
+const smartListTTL = 10 * time.Minute
+
+// viewDedupWindow is the bucket a viewer's repeat visits collapse into.
+const viewDedupWindow = 30 * time.Minute
+
+// viewRetention is how long view rows are kept: as long as the longest
+// trending window looks back, since rankings are computed from them.
+var viewRetention = longestLookback(trendingWindows)
+
+// maxSlugSuffix bounds the sequential "-2", "-3", ... search before falling
+// back to a random suffix.
+const maxSlugSuffix = 50
+
+// SlugMovedError is returned when a watchlist is requested by a slug it no
+// longer uses. Watchlist is the list the old slug now points to.
+type SlugMovedError struct {
+	Watchlist *models.Watchlist
+}
+
+func (e *SlugMovedError) Error() string {
+	return "watchlist moved to " + e.Watchlist.Slug
+}
+
 type WatchlistService struct {
 	watchlists repositories.WatchlistRepository
+	history    repositories.WatchlistHistoryRepository
+	rankings   repositories.RankingRepository
+	watchLogs  repositories.WatchLogRepository
 	msvc       *MovieService
+	blocks     repositories.BlockRepository
+	covers     *CoverService
 	feedCache  *cache.TTLCache[string, []byte]
 }
 
-func NewWatchlistService(repo repositories.WatchlistRepository, tmdbClient *tmdb.Client) *WatchlistService {
+func NewWatchlistService(repo repositories.WatchlistRepository, history repositories.WatchlistHistoryRepository, rankings repositories.RankingRepository, watchLogs repositories.WatchLogRepository, blocks repositories.BlockRepository, msvc *MovieService, covers *CoverService) *WatchlistService {
 	return &WatchlistService{
 		watchlists: repo,
-		msvc:       NewMovieService(*tmdbClient, nil),
+		history:    history,
+		rankings:   rankings,
+		watchLogs:  watchLogs,
+		blocks:     blocks,
+		msvc:       msvc,
+		covers:     covers,
 		feedCache:  cache.NewTTL[string, []byte](60 * time.Second),
 	}
 }
 
-func (s *WatchlistService) SaveWatchlist(ctx context.Context, userID, watchlistID string) error {
+// SaveWatchlist adds someone else's watchlist to the user's library and
+// reports whether it wasn't saved already.
+func (s *WatchlistService) SaveWatchlist(ctx context.Context, userID, watchlistID string) (bool, error) {
 	if userID == "" {
-		return ErrUnauthorized
+		return false, ErrUnauthorized
 	}
-	watchlist, err := s.watchlists.GetByID(ctx, watchlistID)
+	watchlist, err := s.watchlists.GetSummary(ctx, watchlistID)
 	if err != nil {
-		return err
+		return false, err
 	}
 	if watchlist.OwnerID == userID {
-		return errors.New("cannot save your own watchlist")
+		return false, ErrCannotSaveOwn
+	}
+	if err := s.checkVisible(ctx, watchlist, userID); err != nil {
+		return false, err
+	}
+	if err := checkBlocked(ctx, s.blocks, userID, watchlist.OwnerID); err != nil {
+		return false, err
 	}
 	return s.watchlists.Save(ctx, userID, watchlistID)
 }
 
+func (s *WatchlistService) UnsaveWatchlist(ctx context.Context, userID, watchlistID string) error {
+	if userID == "" {
+		return ErrUnauthorized
+	}
+	return s.watchlists.Unsave(ctx, userID, watchlistID)
+}
+
 func (s *WatchlistService) SearchMovies(ctx context.Context, query string, page int) (*domain.SearchResult, error) {
 	return s.msvc.SearchMovies(ctx, query, page)
 }
 
 func (s *WatchlistService) GetMovie(ctx context.Context, id int) (*domain.Movie, error) {
-	return s.msvc.GetMovieByTMDBID(ctx, id)
-}
-
-func (s *WatchlistService) TrendingWatchlists(ctx context.Context, window string, limit int) ([]models.Watchlist, error) {
-	return s.watchlists.Top(ctx, window, limit)
+	return s.msvc.GetMovieDetails(ctx, id)
 }
 
 func (s *WatchlistService) GetWatchlist(ctx context.Context, id, requester string) (*models.Watchlist, error) {
@@ -65,17 +124,97 @@ func (s *WatchlistService) GetWatchlist(ctx context.Context, id, requester strin
 	if err != nil {
 		return nil, err
 	}
-	if wl.Visibility == models.PrivateVisibility && wl.OwnerID != requester {
+	if err := s.checkVisible(ctx, wl, requester); errors.Is(err, gorm.ErrRecordNotFound) {
+		return nil, ErrForbidden
+	} else if err != nil {
+		return nil, err
+	}
+	if err := checkBlocked(ctx, s.blocks, requester, wl.OwnerID); err != nil {
 		return nil, ErrForbidden
 	}
+	if wl, err = s.materialize(ctx, wl, false); err != nil {
+		return nil, err
+	}
+	if err := s.annotateItemsWatched(ctx, wl, requester); err != nil {
+		return nil, err
+	}
 	return wl, nil
 }
 
 func (s *WatchlistService) ListByOwner(ctx context.Context, owner, requester string) ([]models.Watchlist, error) {
+	var lists []models.Watchlist
+	var err error
 	if requester != "" && owner == requester {
-		return s.watchlists.ListByOwner(ctx, owner)
+		lists, err = s.watchlists.ListByOwner(ctx, owner)
+	} else {
+		// Blocked users, and private accounts to non-followers, look as if
+		// they had no lists.
+		if err := checkBlocked(ctx, s.blocks, requester, owner); errors.Is(err, ErrBlocked) {
+			return []models.Watchlist{}, nil
+		} else if err != nil {
+			return nil, err
+		}
+		visible, err := s.watchlists.OwnerVisible(ctx, owner, requester)
+		if err != nil {
+			return nil, err
+		}
+		if !visible {
+			return []models.Watchlist{}, nil
+		}
+		lists, err = s.watchlists.ListPublicByOwner(ctx, owner)
+	}
+	if err != nil {
+		return nil, err
+	}
+	if err := s.annotateProgress(ctx, lists, requester); err != nil {
+		return nil, err
 	}
-	return s.watchlists.ListPublicByOwner(ctx, owner)
+	return lists, nil
+}
+
+// annotateItemsWatched marks which items the viewer has watched and sets the
+// list's progress counter. Anonymous viewers get neither.
+func (s *WatchlistService) annotateItemsWatched(ctx context.Context, wl *models.Watchlist, viewer string) error {
+	if viewer == "" {
+		return nil
+	}
+	movieIDs := make([]uuid.UUID, 0, len(wl.Items))
+	for _, it := range wl.Items {
+		movieIDs = append(movieIDs, it.MovieID)
+	}
+	watched, err := s.watchLogs.WatchedMovieIDs(ctx, viewer, movieIDs)
+	if err != nil {
+		return err
+	}
+	count := 0
+	for i := range wl.Items {
+		wl.Items[i].Watched = watched[wl.Items[i].MovieID]
+		if wl.Items[i].Watched {
+			count++
+		}
+	}
+	wl.WatchedCount = &count
+	return nil
+}
+
+// annotateProgress sets "watched/item_count" progress on each list for the viewer.
+func (s *WatchlistService) annotateProgress(ctx context.Context, lists []models.Watchlist, viewer string) error {
+	if viewer == "" || len(lists) == 0 {
+		return nil
+	}
+	ids := make([]uuid.UUID, 0, len(lists))
+	for _, wl := range lists {
+		ids = append(ids, wl.ID)
+	}
+	counts, err := s.watchLogs.WatchedCounts(ctx, viewer, ids)
+	if err != nil {
+		return err
+	}
+	for i := range lists {
+		n := counts[lists[i].ID]
+		lists[i].WatchedCount = &n
+	}
+	return nil
 }
 
 func (s *WatchlistService) CreateWatchlist(ctx context.Context, owner string, watchlist *models.Watchlist) error {
@@ -83,7 +222,134 @@ func (s *WatchlistService) CreateWatchlist(ctx context.Context, owner string, wa
 		return ErrUnauthorized
 	}
 	watchlist.OwnerID = owner
-	return s.watchlists.Create(ctx, watchlist)
+	normalized, err := tags.NormalizeAll(watchlist.Tags)
+	if err != nil {
+		return err
+	}
+	watchlist.Tags = normalized
+	if watchlist.Kind == "" {
+		watchlist.Kind = models.ManualWatchlist
+	}
+	if watchlist.Kind == models.SmartWatchlist {
+		if err := s.checkSmartRules(ctx, owner, models.DecodeSmartRules(watchlist.Rules)); err != nil {
+			return err
+		}
+	}
+	if err := s.createWithSlug(ctx, watchlist); err != nil {
+		return err
+	}
+	if watchlist.Kind == models.SmartWatchlist {
+		refreshed, err := s.materialize(ctx, watchlist, true)
+		if err != nil {
+			return err
+		}
+		*watchlist = *refreshed
+	}
+	return nil
+}
+
+// ForkWatchlist copies a visible watchlist, items and notes included, into a
+// new private list owned by owner. Smart lists are forked as a manual
+// snapshot of their current items.
+func (s *WatchlistService) ForkWatchlist(ctx context.Context, owner, sourceID string) (*models.Watchlist, error) {
+	if owner == "" {
+		return nil, ErrUnauthorized
+	}
+	src, err := s.GetWatchlist(ctx, sourceID, owner)
+	if err != nil {
+		return nil, err
+	}
+	fork := &models.Watchlist{
+		OwnerID:      owner,
+		Title:        src.Title,
+		Description:  src.Description,
+		Tags:         src.Tags,
+		Visibility:   models.PrivateVisibility,
+		Kind:         models.ManualWatchlist,
+		ForkedFromID: &src.ID,
+	}
+	if err := s.createWithSlug(ctx, fork); err != nil {
+		return nil, err
+	}
+	if len(src.Items) > 0 {
+		var batch repositories.ItemBatch
+		for _, it := range src.Items {
+			batch.Add = append(batch.Add, models.WatchlistItem{ID: uuid.New(), MovieID: it.MovieID, Note: it.Note, AddedAt: time.Now(), MediaType: it.MediaType})
+		}
+		err := s.change(ctx, func(watchlists repositories.WatchlistRepository) ([]models.WatchlistRevision, error) {
+			res, err := watchlists.ApplyItemBatch(ctx, fork.ID.String(), owner, batch)
+			if err != nil {
+				return nil, err
+			}
+			return batchRevisions(fork.ID, owner, res), nil
+		})
+		if err != nil {
+			return nil, err
+		}
+		s.touchCover(fork.ID)
+	}
+	return s.watchlists.GetByID(ctx, fork.ID.String())
+}
+
+// createWithSlug creates the watchlist under a free slug, recording its
+// creation by its owner.
+func (s *WatchlistService) createWithSlug(ctx context.Context, watchlist *models.Watchlist) error {
+	// Two creates racing for the same title can both see a slug as free; the
+	// loser re-checks and moves on to the next suffix.
+	for attempt := 0; ; attempt++ {
+		sl, err := s.uniqueSlug(ctx, watchlist.Title, "")
+		if err != nil {
+			return err
+		}
+		watchlist.Slug = sl
+		err = s.change(ctx, func(watchlists repositories.WatchlistRepository) ([]models.WatchlistRevision, error) {
+			if err := watchlists.Create(ctx, watchlist); err != nil {
+				return nil, err
+			}
+			return []models.WatchlistRevision{revision(watchlist.ID, watchlist.OwnerID, models.RevisionCreate, nil, nil, models.SnapshotWatchlist(watchlist))}, nil
+		})
+		if err == nil || attempt >= 2 {
+			return err
+		}
+		if taken, terr := s.watchlists.SlugTaken(ctx, sl, ""); terr != nil || !taken {
+			return err
+		}
+	}
+}
+
+// uniqueSlug derives a slug from title that no other watchlist uses, adding
+// "-2", "-3", ... on collision.
+func (s *WatchlistService) uniqueSlug(ctx context.Context, title, exceptID string) (string, error) {
+	base := slug.Make(title)
+	if base == "" {
+		base = "watchlist"
+	}
+	candidate := base
+	for n := 2; n <= maxSlugSuffix; n++ {
+		taken, err := s.watchlists.SlugTaken(ctx, candidate, exceptID)
+		if err != nil {
+			return "", err
+		}
+		if !taken {
+			return candidate, nil
+		}
+		candidate = slug.WithSuffix(base, n)
+	}
+	return slug.WithSuffix(base, int(uuid.New().ID()%1_000_000)), nil
+}
+
+// sameSlugBase reports whether current was generated from base, either
+// exactly or with a collision suffix.
+func sameSlugBase(current, base string) bool {
+	if current == base {
+		return true
+	}
+	rest, ok := strings.CutPrefix(current, base+"-")
+	if !ok || rest == "" {
+		return false
+	}
+	_, err := strconv.Atoi(rest)
+	return err == nil
 }
 
 func (s *WatchlistService) UpdateWatchlist(ctx context.Context, owner, id string, updater func(existing *models.Watchlist)) (*models.Watchlist, error) {
@@ -97,41 +363,203 @@ func (s *WatchlistService) UpdateWatchlist(ctx context.Context, owner, id string
 	if existing.OwnerID != owner {
 		return nil, ErrForbidden
 	}
+	before := models.SnapshotWatchlist(existing)
+	prevTitle, prevSlug, prevRules := existing.Title, existing.Slug, string(existing.Rules)
 	if updater != nil {
 		updater(existing)
 	}
-	if err := s.watchlists.Update(ctx, existing); err != nil {
+	if existing.Tags, err = tags.NormalizeAll(existing.Tags); err != nil {
 		return nil, err
 	}
+	switch {
+	case existing.Slug != prevSlug:
+		// Explicit rename requested by the owner.
+		if !slug.Valid(existing.Slug) {
+			return nil, ErrInvalidSlug
+		}
+		taken, err := s.watchlists.SlugTaken(ctx, existing.Slug, id)
+		if err != nil {
+			return nil, err
+		}
+		if taken {
+			return nil, ErrSlugTaken
+		}
+	case existing.Title != prevTitle:
+		base := slug.Make(existing.Title)
+		if base != "" && !sameSlugBase(prevSlug, base) {
+			sl, err := s.uniqueSlug(ctx, existing.Title, id)
+			if err != nil {
+				return nil, err
+			}
+			existing.Slug = sl
+		}
+	}
+	rulesChanged := string(existing.Rules) != prevRules
+	if rulesChanged {
+		if existing.Kind != models.SmartWatchlist {
+			return nil, ErrNotSmartList
+		}
+		if err := s.checkSmartRules(ctx, owner, models.DecodeSmartRules(existing.Rules)); err != nil {
+			return nil, err
+		}
+	}
+	err = s.change(ctx, func(watchlists repositories.WatchlistRepository) ([]models.WatchlistRevision, error) {
+		if err := watchlists.Update(ctx, existing); err != nil {
+			return nil, err
+		}
+		return []models.WatchlistRevision{revision(existing.ID, owner, models.RevisionUpdate, nil, before, models.SnapshotWatchlist(existing))}, nil
+	})
+	if err != nil {
+		return nil, err
+	}
+	if rulesChanged {
+		return s.materialize(ctx, existing, true)
+	}
 	return existing, nil
 }
 
+// RefreshSmartList re-evaluates a smart list's rules immediately.
+func (s *WatchlistService) RefreshSmartList(ctx context.Context, owner, id string) (*models.Watchlist, error) {
+	if owner == "" {
+		return nil, ErrUnauthorized
+	}
+	wl, err := s.watchlists.GetSummary(ctx, id)
+	if err != nil {
+		return nil, err
+	}
+	if wl.OwnerID != owner {
+		return nil, ErrForbidden
+	}
+	if wl.Kind != models.SmartWatchlist {
+		return nil, ErrNotSmartList
+	}
+	return s.materialize(ctx, wl, true)
+}
+
+// materialize recomputes a smart list's items and returns the reloaded list.
+// Without force that only happens once they are older than smartListTTL, by
+// whichever reader claims the refresh first, and items are only rewritten
+// when the rules now match something different. Manual lists are returned
+// unchanged.
+func (s *WatchlistService) materialize(ctx context.Context, wl *models.Watchlist, force bool) (*models.Watchlist, error) {
+	if wl.Kind != models.SmartWatchlist {
+		return wl, nil
+	}
+	if !force {
+		if wl.RefreshedAt != nil && time.Since(*wl.RefreshedAt) < smartListTTL {
+			return wl, nil
+		}
+		claimed, err := s.watchlists.ClaimRefresh(ctx, wl.ID, wl.RefreshedAt)
+		if err != nil || !claimed {
+			return wl, err
+		}
+	}
+	ids, err := s.watchlists.ResolveSmartRules(ctx, wl.OwnerID, models.DecodeSmartRules(wl.Rules))
+	if err != nil {
+		return nil, err
+	}
+	if !force && slices.EqualFunc(wl.Items, ids, func(it models.WatchlistItem, id uuid.UUID) bool { return it.MovieID == id }) {
+		return wl, nil
+	}
+	if err := s.watchlists.MaterializeItems(ctx, wl.ID, ids); err != nil {
+		return nil, err
+	}
+	s.touchCover(wl.ID)
+	return s.watchlists.GetByID(ctx, wl.ID.String())
+}
+
+// checkSmartRules makes sure list references in rules point at the owner's own lists.
+func (s *WatchlistService) checkSmartRules(ctx context.Context, owner string, rules *models.SmartRules) error {
+	if rules == nil {
+		return nil
+	}
+	for _, ref := range []*uuid.UUID{rules.InWatchlist, rules.NotInWatchlist} {
+		if ref == nil {
+			continue
+		}
+		if err := s.watchlists.EnsureOwner(ctx, ref.String(), owner); err != nil {
+			if errors.Is(err, gorm.ErrRecordNotFound) {
+				return ErrForbidden
+			}
+			return err
+		}
+	}
+	return nil
+}
+
+// touchCover schedules a cover refresh after the list's items changed.
+func (s *WatchlistService) touchCover(id uuid.UUID) {
+	if s.covers != nil {
+		s.covers.Touch(id)
+	}
+}
+
+// ensureManual rejects hand edits to smart lists' items.
+func (s *WatchlistService) ensureManual(ctx context.Context, watchlistID string) error {
+	wl, err := s.watchlists.GetSummary(ctx, watchlistID)
+	if err != nil {
+		return err
+	}
+	if wl.Kind == models.SmartWatchlist {
+		return ErrSmartListReadOnly
+	}
+	return nil
+}
+
 func (s *WatchlistService) DeleteWatchlist(ctx context.Context, owner, id string) error {
 	if owner == "" {
 		return ErrUnauthorized
 	}
-	return s.watchlists.Delete(ctx, id, owner)
+	wl, err := s.watchlists.GetSummary(ctx, id)
+	if err != nil {
+		return err
+	}
+	if wl.OwnerID != owner {
+		return ErrForbidden
+	}
+	return s.change(ctx, func(watchlists repositories.WatchlistRepository) ([]models.WatchlistRevision, error) {
+		if err := watchlists.Delete(ctx, id, owner); err != nil {
+			return nil, err
+		}
+		return []models.WatchlistRevision{revision(wl.ID, owner, models.RevisionDelete, nil, models.SnapshotWatchlist(wl), nil)}, nil
+	})
 }
 
-func (s *WatchlistService) AddItem(ctx context.Context, owner, watchlistID string, tmdbID int, notes string) (*models.WatchlistItem, error) {
+// AddItem adds the movie or series (per mediaType, default movie) with TMDB
+// ID tmdbID to a manual list.
+func (s *WatchlistService) AddItem(ctx context.Context, owner, watchlistID, mediaType string, tmdbID int, notes string) (*models.WatchlistItem, error) {
 	if owner == "" {
 		return nil, ErrUnauthorized
 	}
-	movie, err := s.msvc.GetMovieByTMDBID(ctx, tmdbID)
+	if err := s.ensureManual(ctx, watchlistID); err != nil {
+		return nil, err
+	}
+	if mediaType == "" {
+		mediaType = models.MediaMovie
+	}
+	movie, err := s.msvc.GetTitle(ctx, mediaType, tmdbID)
 	if err != nil {
 		return nil, err
 	}
 	item := &models.WatchlistItem{
 		WatchlistID: uuid.MustParse(watchlistID),
 		MovieID:     movie.ID,
+		MediaType:   mediaType,
 		Note:        notes,
 		ID:          uuid.New(),
 		Position:    0, // Will be set in repository
 		AddedAt:     time.Now(),
 	}
-	if err := s.watchlists.AddItem(ctx, item, owner); err != nil {
+	err = s.change(ctx, func(watchlists repositories.WatchlistRepository) ([]models.WatchlistRevision, error) {
+		if err := watchlists.AddItem(ctx, item, owner); err != nil {
+			return nil, err
+		}
+		return []models.WatchlistRevision{revision(item.WatchlistID, owner, models.RevisionAddItem, &item.ID, nil, models.SnapshotItem(item))}, nil
+	})
+	if err != nil {
 		return nil, err
 	}
+	s.touchCover(item.WatchlistID)
 	return item, nil
 }
 
@@ -139,16 +567,157 @@ func (s *WatchlistService) RemoveItem(ctx context.Context, owner, watchlistID, i
 	if owner == "" {
 		return ErrUnauthorized
 	}
-	return s.watchlists.RemoveItem(ctx, watchlistID, itemID, owner)
+	if err := s.ensureManual(ctx, watchlistID); err != nil {
+		return err
+	}
+	item, err := s.watchlists.GetItem(ctx, watchlistID, itemID)
+	if err != nil {
+		return err
+	}
+	err = s.change(ctx, func(watchlists repositories.WatchlistRepository) ([]models.WatchlistRevision, error) {
+		if err := watchlists.RemoveItem(ctx, watchlistID, itemID, owner); err != nil {
+			return nil, err
+		}
+		return []models.WatchlistRevision{revision(item.WatchlistID, owner, models.RevisionRemoveItem, &item.ID, models.SnapshotItem(item), nil)}, nil
+	})
+	if err != nil {
+		return err
+	}
+	s.touchCover(item.WatchlistID)
+	return nil
 }
 
-func (s *WatchlistService) Like(ctx context.Context, owner, watchlistID string) error {
+// UpdateItem changes an item's note and/or moves it to a new position.
+func (s *WatchlistService) UpdateItem(ctx context.Context, owner, watchlistID, itemID string, note *string, position *int) (*models.WatchlistItem, error) {
 	if owner == "" {
-		return ErrUnauthorized
+		return nil, ErrUnauthorized
+	}
+	if err := s.ensureManual(ctx, watchlistID); err != nil {
+		return nil, err
+	}
+	if err := s.watchlists.EnsureOwner(ctx, watchlistID, owner); err != nil {
+		return nil, err
+	}
+	item, err := s.watchlists.GetItem(ctx, watchlistID, itemID)
+	if err != nil {
+		return nil, err
+	}
+	moved := false
+	err = s.change(ctx, func(watchlists repositories.WatchlistRepository) ([]models.WatchlistRevision, error) {
+		var revs []models.WatchlistRevision
+		if note != nil && *note != item.Note {
+			before := models.SnapshotItem(item)
+			if err := watchlists.UpdateItemNote(ctx, watchlistID, itemID, *note, owner); err != nil {
+				return nil, err
+			}
+			item.Note = *note
+			revs = append(revs, revision(item.WatchlistID, owner, models.RevisionEditNote, &item.ID, before, models.SnapshotItem(item)))
+		}
+		if position != nil && *position != item.Position {
+			before := models.SnapshotItem(item)
+			pos, err := watchlists.MoveItem(ctx, watchlistID, itemID, *position, owner)
+			if err != nil {
+				return nil, err
+			}
+			if pos != before.Position {
+				item.Position, moved = pos, true
+				revs = append(revs, revision(item.WatchlistID, owner, models.RevisionMoveItem, &item.ID, before, models.SnapshotItem(item)))
+			}
+		}
+		return revs, nil
+	})
+	if err != nil {
+		return nil, err
+	}
+	if moved {
+		s.touchCover(item.WatchlistID)
+	}
+	return item, nil
+}
+
+// Like reports whether the like is new, so callers notify only once.
+func (s *WatchlistService) Like(ctx context.Context, owner, watchlistID string) (bool, error) {
+	if owner == "" {
+		return false, ErrUnauthorized
+	}
+	wl, err := s.watchlists.GetSummary(ctx, watchlistID)
+	if err != nil {
+		return false, err
+	}
+	if err := s.checkVisible(ctx, wl, owner); err != nil {
+		return false, err
+	}
+	if err := checkBlocked(ctx, s.blocks, owner, wl.OwnerID); err != nil {
+		return false, err
 	}
 	return s.watchlists.Like(ctx, owner, watchlistID)
 }
 
+func (s *WatchlistService) checkVisible(ctx context.Context, wl *models.Watchlist, viewer string) error {
+	return checkListVisible(ctx, s.watchlists, wl, viewer)
+}
+
+// checkListVisible returns gorm.ErrRecordNotFound unless viewer may see wl:
+// private lists are the owner's alone, and lists of private accounts are
+// only for the owner's approved followers. The owner sharing a list with
+// someone lets them see it either way; shares from anyone else don't.
+func checkListVisible(ctx context.Context, watchlists repositories.WatchlistRepository, wl *models.Watchlist, viewer string) error {
+	if wl.OwnerID == viewer {
+		return nil
+	}
+	if wl.Visibility != models.PrivateVisibility {
+		visible, err := watchlists.OwnerVisible(ctx, wl.OwnerID, viewer)
+		if err != nil {
+			return err
+		}
+		if visible {
+			return nil
+		}
+	}
+	if viewer != "" {
+		shared, err := watchlists.SharedWith(ctx, wl.ID.String(), viewer)
+		if err != nil {
+			return err
+		}
+		if shared {
+			return nil
+		}
+	}
+	return gorm.ErrRecordNotFound
+}
+
+// SearchWatchlists finds public lists by title, description or tag, leaving
+// out lists whose owners are blocked either way.
+func (s *WatchlistService) SearchWatchlists(ctx context.Context, viewer, query string, limit, offset int) ([]models.Watchlist, error) {
+	hidden, err := hiddenFrom(ctx, s.blocks, viewer, false)
+	if err != nil {
+		return nil, err
+	}
+	return s.watchlists.Search(ctx, viewer, strings.TrimSpace(query), hidden, limit, offset)
+}
+
+// RecordView counts a view of the watchlist, at most once per viewer per
+// viewDedupWindow.
+func (s *WatchlistService) RecordView(ctx context.Context, watchlistID uuid.UUID, viewerKey string) error {
+	if viewerKey == "" {
+		return nil
+	}
+	_, err := s.watchlists.RecordView(ctx, watchlistID, viewerKey, time.Now().UTC().Truncate(viewDedupWindow))
+	return err
+}
+
+// PurgeViews deletes view rows older than viewRetention.
+func (s *WatchlistService) PurgeViews(ctx context.Context) error {
+	n, err := s.watchlists.PurgeViews(ctx, time.Now().Add(-viewRetention))
+	if err != nil {
+		return err
+	}
+	if n > 0 {
+		log.Printf("purged %d watchlist views past retention", n)
+	}
+	return nil
+}
+
 func (s *WatchlistService) Unlike(ctx context.Context, owner, watchlistID string) error {
 	if owner == "" {
 		return ErrUnauthorized
@@ -160,8 +729,42 @@ func (s *WatchlistService) GetByID(ctx context.Context, id string) (*models.Watc
 	return s.watchlists.GetByID(ctx, id)
 }
 
-func (s *WatchlistService) GetBySlug(ctx context.Context, slug string) (*models.Watchlist, error) {
-	return s.watchlists.GetBySlug(ctx, slug)
+// GetBySlug looks up a public or unlisted watchlist. Old slugs yield a
+// *SlugMovedError pointing at the current one.
+func (s *WatchlistService) GetBySlug(ctx context.Context, sl string) (*models.Watchlist, error) {
+	wl, err := s.watchlists.GetBySlug(ctx, sl)
+	if errors.Is(err, gorm.ErrRecordNotFound) {
+		return nil, s.slugRedirect(ctx, "", sl, err)
+	}
+	if err != nil {
+		return nil, err
+	}
+	return s.materialize(ctx, wl, false)
+}
+
+// GetByOwnerSlug resolves the vanity URL /u/{username}/{slug}. Old slugs
+// only redirect to a list that username owns.
+func (s *WatchlistService) GetByOwnerSlug(ctx context.Context, username, sl string) (*models.Watchlist, error) {
+	wl, err := s.watchlists.GetByOwnerUsernameAndSlug(ctx, username, sl)
+	if errors.Is(err, gorm.ErrRecordNotFound) {
+		return nil, s.slugRedirect(ctx, username, sl, err)
+	}
+	if err != nil {
+		return nil, err
+	}
+	return s.materialize(ctx, wl, false)
+}
+
+// slugRedirect reports where an old slug, of username's lists if given, moved.
+func (s *WatchlistService) slugRedirect(ctx context.Context, username, sl string, notFound error) error {
+	moved, err := s.watchlists.ResolveSlugRedirect(ctx, username, sl)
+	if err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return notFound
+		}
+		return err
+	}
+	return &SlugMovedError{Watchlist: moved}
 }
 
 type FeedOptions struct {
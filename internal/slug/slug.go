@@ -0,0 +1,61 @@
+// Package slug builds URL-safe identifiers from free-form titles.
+package slug
+
+import (
+	"strconv"
+	"strings"
+	"unicode"
+
+	"golang.org/x/text/unicode/norm"
+)
+
+// MaxLength caps generated slugs so URLs stay readable.
+const MaxLength = 80
+
+// Make lowercases the title, strips accents and collapses anything that is not
+// a letter or digit into single hyphens. It returns "" when nothing usable remains.
+func Make(title string) string {
+	var b strings.Builder
+	pendingHyphen := false
+	for _, r := range norm.NFKD.String(title) {
+		switch {
+		case unicode.Is(unicode.Mn, r):
+			// combining marks left over from decomposition (é -> e + ́)
+			continue
+		case r < unicode.MaxASCII && (unicode.IsLetter(r) || unicode.IsDigit(r)):
+			if pendingHyphen && b.Len() > 0 {
+				b.WriteByte('-')
+			}
+			pendingHyphen = false
+			b.WriteRune(unicode.ToLower(r))
+		case r == '\'' || r == '’':
+			// "Director's Cut" -> "directors-cut"
+			continue
+		default:
+			pendingHyphen = true
+		}
+		if b.Len() >= MaxLength {
+			break
+		}
+	}
+	return strings.Trim(truncate(b.String(), MaxLength), "-")
+}
+
+// WithSuffix appends a numeric collision suffix, trimming the base so the
+// result still fits within MaxLength.
+func WithSuffix(base string, n int) string {
+	suffix := "-" + strconv.Itoa(n)
+	return strings.TrimRight(truncate(base, MaxLength-len(suffix)), "-") + suffix
+}
+
+// Valid reports whether s is already in canonical slug form.
+func Valid(s string) bool {
+	return s != "" && len(s) <= MaxLength && Make(s) == s
+}
+
+func truncate(s string, n int) string {
+	if len(s) <= n {
+		return s
+	}
+	return s[:n]
+}
@@ -0,0 +1,42 @@
+package slug
+
+import "testing"
+
+func TestMake(t *testing.T) {
+	cases := map[string]string{
+		"My Favourite Films":     "my-favourite-films",
+		"  Best of 2024!!  ":     "best-of-2024",
+		"Amélie & Café Noir":     "amelie-cafe-noir",
+		"Director's Cut":         "directors-cut",
+		"---":                    "",
+		"東京物語":                   "",
+		"Sci-Fi / Fantasy (90s)": "sci-fi-fantasy-90s",
+	}
+	for in, want := range cases {
+		if got := Make(in); got != want {
+			t.Errorf("Make(%q) = %q, want %q", in, got, want)
+		}
+	}
+}
+
+func TestWithSuffixRespectsMaxLength(t *testing.T) {
+	long := Make("a very long watchlist title that keeps going and going well past any sensible url length")
+	got := WithSuffix(long, 12)
+	if len(got) > MaxLength {
+		t.Fatalf("len(%q) = %d, want <= %d", got, len(got), MaxLength)
+	}
+	if got[len(got)-3:] != "-12" {
+		t.Fatalf("WithSuffix() = %q, want -12 suffix", got)
+	}
+}
+
+func TestValid(t *testing.T) {
+	if !Valid("horror-classics") {
+		t.Error("expected horror-classics to be valid")
+	}
+	for _, s := range []string{"", "Horror", "horror--classics", "-horror", "horror classics"} {
+		if Valid(s) {
+			t.Errorf("expected %q to be invalid", s)
+		}
+	}
+}
@@ -0,0 +1,47 @@
+// Package tags normalizes the free-form tags users put on watchlists.
+package tags
+
+import (
+	"errors"
+	"strings"
+
+	"github.com/Dubjay18/scenee/internal/slug"
+)
+
+const (
+	// MaxTags is how many tags a watchlist may carry.
+	MaxTags = 10
+	// MaxLength caps a single normalized tag.
+	MaxLength = 30
+)
+
+var ErrTooMany = errors.New("a watchlist can have at most 10 tags")
+
+// Normalize canonicalizes one tag: lowercase ASCII letters, digits and single
+// hyphens ("#Sci Fi" -> "sci-fi"). It returns "" when nothing usable remains.
+func Normalize(tag string) string {
+	t := slug.Make(tag)
+	if len(t) > MaxLength {
+		t = strings.TrimRight(t[:MaxLength], "-")
+	}
+	return t
+}
+
+// NormalizeAll normalizes tags, dropping empties and duplicates while keeping
+// first-seen order. It fails with ErrTooMany when more than MaxTags remain.
+func NormalizeAll(in []string) ([]string, error) {
+	out := make([]string, 0, len(in))
+	seen := make(map[string]bool, len(in))
+	for _, raw := range in {
+		t := Normalize(raw)
+		if t == "" || seen[t] {
+			continue
+		}
+		seen[t] = true
+		out = append(out, t)
+	}
+	if len(out) > MaxTags {
+		return nil, ErrTooMany
+	}
+	return out, nil
+}
@@ -0,0 +1,50 @@
+package tags
+
+import (
+	"errors"
+	"reflect"
+	"strings"
+	"testing"
+)
+
+func TestNormalize(t *testing.T) {
+	cases := map[string]string{
+		"Horror":                "horror",
+		"#Sci Fi":               "sci-fi",
+		"  Film Noir  ":         "film-noir",
+		"Café":                  "cafe",
+		"!!!":                   "",
+		strings.Repeat("a", 40): strings.Repeat("a", MaxLength),
+	}
+	for in, want := range cases {
+		if got := Normalize(in); got != want {
+			t.Errorf("Normalize(%q) = %q, want %q", in, got, want)
+		}
+	}
+}
+
+func TestNormalizeAllDedupes(t *testing.T) {
+	got, err := NormalizeAll([]string{"Horror", "horror", "#HORROR", "", "80s", "Sci Fi", "sci-fi"})
+	if err != nil {
+		t.Fatal(err)
+	}
+	want := []string{"horror", "80s", "sci-fi"}
+	if !reflect.DeepEqual(got, want) {
+		t.Fatalf("NormalizeAll() = %v, want %v", got, want)
+	}
+}
+
+func TestNormalizeAllLimit(t *testing.T) {
+	in := make([]string, 0, MaxTags+1)
+	for i := 0; i <= MaxTags; i++ {
+		in = append(in, strings.Repeat("x", i+1))
+	}
+	if _, err := NormalizeAll(in); !errors.Is(err, ErrTooMany) {
+		t.Fatalf("NormalizeAll(%d tags) err = %v, want ErrTooMany", len(in), err)
+	}
+	// duplicates don't count towards the limit
+	dup := append(in[:MaxTags:MaxTags], "X")
+	if _, err := NormalizeAll(dup); err != nil {
+		t.Fatalf("NormalizeAll with duplicate: %v", err)
+	}
+}
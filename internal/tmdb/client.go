@@ -3,7 +3,10 @@ package tmdb
 import (
 	"context"
 	"encoding/json"
+	"errors"
 	"fmt"
+	"io"
+	"log"
 	"net/http"
 	"net/url"
 	"strconv"
@@ -13,10 +16,19 @@ import (
 	"github.com/Dubjay18/scenee/internal/domain"
 )
 
+// Client calls the TMDB API. Requests share a token bucket rate limiter,
+// are retried with jittered backoff on 429 and 5xx, and go through a
+// circuit breaker; while TMDB is down, the last good response to the same
+// request is served if there is one. Copies of a Client share that state.
 type Client struct {
 	APIKey  string
 	BaseURL string
 	HTTP    *http.Client
+
+	limiter   *tokenBucket
+	breaker   *breaker
+	stale     *staleCache
+	retryBase time.Duration
 }
 
 type Movie struct {
@@ -53,51 +65,140 @@ type DiscoverResponse struct {
 
 func New(apiKey, base string) *Client {
 	return &Client{
-		APIKey:  apiKey,
-		BaseURL: base,
-		HTTP:    &http.Client{Timeout: 10 * time.Second},
+		APIKey:    apiKey,
+		BaseURL:   base,
+		HTTP:      &http.Client{Timeout: 10 * time.Second},
+		limiter:   newTokenBucket(defaultRate, defaultBurst),
+		breaker:   newBreaker(breakerThreshold, breakerCooldown),
+		stale:     newStaleCache(staleBytes),
+		retryBase: retryBase,
 	}
 }
 
-func (c *Client) SearchMovies(ctx context.Context, query string, page int) (*SearchMoviesResponse, error) {
-	u, _ := url.Parse(c.BaseURL + "/search/movie")
-	q := u.Query()
-	q.Set("api_key", c.APIKey)
-	q.Set("query", query)
-	if page > 0 {
-		q.Set("page", fmt.Sprint(page))
+// get requests path with params and decodes the JSON response into out.
+// Errors unwrap to ErrNotFound, ErrRateLimited or ErrUnavailable where
+// they apply.
+func (c *Client) get(ctx context.Context, path string, params url.Values, out any) error {
+	if params == nil {
+		params = url.Values{}
 	}
-	u.RawQuery = q.Encode()
+	key := path + "?" + params.Encode()
+	body, err := c.fetch(ctx, path, params)
+	if err != nil {
+		if errors.Is(err, ErrUnavailable) && c.stale != nil {
+			if cached, ok := c.stale.get(key); ok {
+				log.Printf("tmdb %s: serving stale response: %v", path, err)
+				return json.Unmarshal(cached, out)
+			}
+		}
+		return err
+	}
+	if err := json.Unmarshal(body, out); err != nil {
+		return fmt.Errorf("tmdb %s: %w", path, err)
+	}
+	if c.stale != nil {
+		c.stale.put(key, body)
+	}
+	return nil
+}
 
-	req, _ := http.NewRequestWithContext(ctx, http.MethodGet, u.String(), nil)
+// fetch returns the body of a 200 response, retrying rate limited and
+// failed requests. Only upstream failures count against the breaker.
+func (c *Client) fetch(ctx context.Context, path string, params url.Values) (body []byte, err error) {
+	if c.breaker != nil {
+		ok, probe := c.breaker.allow(time.Now())
+		if !ok {
+			return nil, &StatusError{Path: path, Err: fmt.Errorf("circuit open: %w", ErrUnavailable)}
+		}
+		defer func() { c.breaker.done(probe, err, ctx.Err() != nil, time.Now()) }()
+	}
+	for attempt := 0; ; attempt++ {
+		if c.limiter != nil {
+			if err = c.limiter.wait(ctx); err != nil {
+				return nil, err
+			}
+		}
+		var wait time.Duration
+		body, wait, err = c.do(ctx, path, params)
+		retryable := errors.Is(err, ErrRateLimited) || errors.Is(err, ErrUnavailable)
+		if !retryable || attempt+1 >= maxAttempts || wait > maxRetryAfter {
+			return body, err
+		}
+		if wait > 0 {
+			wait += jitter(wait / 4)
+		} else {
+			wait = backoff(c.retryBase, attempt)
+		}
+		if sleep(ctx, wait) != nil {
+			return body, err
+		}
+	}
+}
+
+// do makes one request. wait is the response's Retry-After, if any.
+func (c *Client) do(ctx context.Context, path string, params url.Values) (body []byte, wait time.Duration, err error) {
+	q := url.Values{}
+	for k, v := range params {
+		q[k] = v
+	}
+	q.Set("api_key", c.APIKey)
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, c.BaseURL+path+"?"+q.Encode(), nil)
+	if err != nil {
+		return nil, 0, err
+	}
 	res, err := c.HTTP.Do(req)
 	if err != nil {
-		return nil, err
+		if ctx.Err() != nil {
+			return nil, 0, ctx.Err()
+		}
+		return nil, 0, &StatusError{Path: path, Err: fmt.Errorf("%w: %v", ErrUnavailable, withoutURL(err))}
 	}
 	defer res.Body.Close()
-	if res.StatusCode != http.StatusOK {
-		return nil, fmt.Errorf("tmdb status %d", res.StatusCode)
+	switch {
+	case res.StatusCode == http.StatusOK:
+		body, err = io.ReadAll(res.Body)
+		if err != nil {
+			return nil, 0, &StatusError{Path: path, Err: fmt.Errorf("%w: %v", ErrUnavailable, withoutURL(err))}
+		}
+		return body, 0, nil
+	case res.StatusCode == http.StatusNotFound:
+		return nil, 0, &StatusError{Path: path, Status: res.StatusCode, Err: ErrNotFound}
+	case res.StatusCode == http.StatusTooManyRequests:
+		return nil, retryAfter(res.Header, time.Now()), &StatusError{Path: path, Status: res.StatusCode, Err: ErrRateLimited}
+	case res.StatusCode >= 500:
+		return nil, retryAfter(res.Header, time.Now()), &StatusError{Path: path, Status: res.StatusCode, Err: ErrUnavailable}
+	default:
+		return nil, 0, &StatusError{Path: path, Status: res.StatusCode}
+	}
+}
+
+// withoutURL drops the request URL a transport error carries, since its
+// query string holds the API key.
+func withoutURL(err error) error {
+	var urlErr *url.Error
+	if errors.As(err, &urlErr) {
+		return urlErr.Err
+	}
+	return err
+}
+
+func (c *Client) SearchMovies(ctx context.Context, query string, page int) (*SearchMoviesResponse, error) {
+	q := url.Values{"query": {query}}
+	if page > 0 {
+		q.Set("page", fmt.Sprint(page))
 	}
 	var out SearchMoviesResponse
-	if err := json.NewDecoder(res.Body).Decode(&out); err != nil {
+	if err := c.get(ctx, "/search/movie", q, &out); err != nil {
 		return nil, err
 	}
 	return &out, nil
 }
 
-func (c *Client) GetMovie(ctx context.Context, id int64) (*Movie, error) {
-	u := fmt.Sprintf("%s/movie/%d?api_key=%s", c.BaseURL, id, url.QueryEscape(c.APIKey))
-	req, _ := http.NewRequestWithContext(ctx, http.MethodGet, u, nil)
-	res, err := c.HTTP.Do(req)
-	if err != nil {
-		return nil, err
-	}
-	defer res.Body.Close()
-	if res.StatusCode != http.StatusOK {
-		return nil, fmt.Errorf("tmdb status %d", res.StatusCode)
-	}
-	var out Movie
-	if err := json.NewDecoder(res.Body).Decode(&out); err != nil {
+// GetMovie fetches a movie with its credits, videos, release dates, watch
+// providers, similar titles and recommendations in one request.
+func (c *Client) GetMovie(ctx context.Context, id int64) (*MovieDetails, error) {
+	var out MovieDetails
+	if err := c.get(ctx, fmt.Sprintf("/movie/%d", id), url.Values{"append_to_response": {movieAppend}}, &out); err != nil {
 		return nil, err
 	}
 	return &out, nil
@@ -108,27 +209,15 @@ func (c *Client) TrendingMovies(ctx context.Context, window string, page int, re
 	if window == "" {
 		window = "day"
 	}
-	u, _ := url.Parse(c.BaseURL + "/trending/movie/" + window)
-	q := u.Query()
-	q.Set("api_key", c.APIKey)
+	q := url.Values{}
 	if page > 0 {
 		q.Set("page", fmt.Sprint(page))
 	}
 	if region != "" {
 		q.Set("region", region)
 	}
-	u.RawQuery = q.Encode()
-	req, _ := http.NewRequestWithContext(ctx, http.MethodGet, u.String(), nil)
-	res, err := c.HTTP.Do(req)
-	if err != nil {
-		return nil, err
-	}
-	defer res.Body.Close()
-	if res.StatusCode != http.StatusOK {
-		return nil, fmt.Errorf("tmdb status %d", res.StatusCode)
-	}
 	var out TrendingResponse
-	if err := json.NewDecoder(res.Body).Decode(&out); err != nil {
+	if err := c.get(ctx, "/trending/movie/"+window, q, &out); err != nil {
 		return nil, err
 	}
 	return &out, nil
@@ -137,9 +226,7 @@ func (c *Client) TrendingMovies(ctx context.Context, window string, page int, re
 // DiscoverMovies provides a randomized-like feed using discover with sort_by.
 // Filters: with_genres, primary_release_year, region, sort_by (popularity.desc|vote_average.desc|release_date.desc)
 func (c *Client) DiscoverMovies(ctx context.Context, page int, genre, year, region, sortBy string) (*DiscoverResponse, error) {
-	u, _ := url.Parse(c.BaseURL + "/discover/movie")
-	q := u.Query()
-	q.Set("api_key", c.APIKey)
+	q := url.Values{}
 	if page > 0 {
 		q.Set("page", fmt.Sprint(page))
 	}
@@ -156,18 +243,8 @@ func (c *Client) DiscoverMovies(ctx context.Context, page int, genre, year, regi
 		sortBy = "popularity.desc"
 	}
 	q.Set("sort_by", sortBy)
-	u.RawQuery = q.Encode()
-	req, _ := http.NewRequestWithContext(ctx, http.MethodGet, u.String(), nil)
-	res, err := c.HTTP.Do(req)
-	if err != nil {
-		return nil, err
-	}
-	defer res.Body.Close()
-	if res.StatusCode != http.StatusOK {
-		return nil, fmt.Errorf("tmdb status %d", res.StatusCode)
-	}
 	var out DiscoverResponse
-	if err := json.NewDecoder(res.Body).Decode(&out); err != nil {
+	if err := c.get(ctx, "/discover/movie", q, &out); err != nil {
 		return nil, err
 	}
 	return &out, nil
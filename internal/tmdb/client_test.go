@@ -0,0 +1,224 @@
+package tmdb
+
+import (
+	"context"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"sync/atomic"
+	"testing"
+	"time"
+)
+
+// newTestClient points a client at h, which is told how many requests
+// the server has had including this one.
+func newTestClient(t *testing.T, h func(w http.ResponseWriter, r *http.Request, n int32)) (*Client, *atomic.Int32) {
+	t.Helper()
+	var calls atomic.Int32
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		n := calls.Add(1)
+		if r.URL.Query().Get("api_key") != "key" {
+			t.Errorf("request without api_key: %s", r.URL)
+		}
+		h(w, r, n)
+	}))
+	t.Cleanup(srv.Close)
+	c := New("key", srv.URL)
+	c.limiter = newTokenBucket(1000, 100)
+	c.breaker = newBreaker(2, time.Minute)
+	c.retryBase = time.Millisecond
+	return c, &calls
+}
+
+func TestClientRetriesRateLimited(t *testing.T) {
+	c, calls := newTestClient(t, func(w http.ResponseWriter, r *http.Request, n int32) {
+		if r.URL.Path != "/movie/27205" || r.URL.Query().Get("append_to_response") != movieAppend {
+			t.Errorf("unexpected request %s", r.URL)
+		}
+		if n == 1 {
+			w.Header().Set("Retry-After", "0")
+			w.WriteHeader(http.StatusTooManyRequests)
+			return
+		}
+		_, _ = w.Write([]byte(`{"id": 27205, "title": "Inception"}`))
+	})
+	m, err := c.GetMovie(context.Background(), 27205)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if m.Title != "Inception" || calls.Load() != 2 {
+		t.Errorf("got %q after %d calls, want Inception after 2", m.Title, calls.Load())
+	}
+}
+
+func TestClientTypedErrors(t *testing.T) {
+	c, calls := newTestClient(t, func(w http.ResponseWriter, r *http.Request, _ int32) {
+		switch r.URL.Path {
+		case "/movie/1":
+			w.WriteHeader(http.StatusNotFound)
+		case "/movie/2":
+			w.Header().Set("Retry-After", "120")
+			w.WriteHeader(http.StatusTooManyRequests)
+		}
+	})
+	ctx := context.Background()
+
+	_, err := c.GetMovie(ctx, 1)
+	var se *StatusError
+	if !errors.Is(err, ErrNotFound) || !errors.As(err, &se) || se.Status != http.StatusNotFound {
+		t.Errorf("404: err = %v, want ErrNotFound", err)
+	}
+	if calls.Load() != 1 {
+		t.Errorf("404 made %d calls, want no retry", calls.Load())
+	}
+
+	// A Retry-After beyond maxRetryAfter is reported rather than waited out
+	if _, err := c.GetMovie(ctx, 2); !errors.Is(err, ErrRateLimited) {
+		t.Errorf("429: err = %v, want ErrRateLimited", err)
+	}
+	if calls.Load() != 2 {
+		t.Errorf("429 with a long Retry-After made %d calls, want 1", calls.Load()-1)
+	}
+}
+
+func TestClientBreakerServesStale(t *testing.T) {
+	var down atomic.Bool
+	c, calls := newTestClient(t, func(w http.ResponseWriter, r *http.Request, _ int32) {
+		if down.Load() {
+			w.WriteHeader(http.StatusBadGateway)
+			return
+		}
+		_, _ = w.Write([]byte(`{"page": 1, "total_results": 1, "results": [{"id": 603, "title": "The Matrix"}]}`))
+	})
+	ctx := context.Background()
+
+	if _, err := c.SearchMovies(ctx, "matrix", 1); err != nil {
+		t.Fatal(err)
+	}
+	down.Store(true)
+
+	// Each failing request is retried, then the last good response served
+	for i := 0; i < 2; i++ {
+		res, err := c.SearchMovies(ctx, "matrix", 1)
+		if err != nil || len(res.Results) != 1 {
+			t.Fatalf("while down: %v, %+v, want the stale response", err, res)
+		}
+	}
+	if want := int32(1 + 2*maxAttempts); calls.Load() != want {
+		t.Errorf("made %d calls, want %d", calls.Load(), want)
+	}
+
+	// Two failures opened the breaker: no more calls, stale or error
+	before := calls.Load()
+	if res, err := c.SearchMovies(ctx, "matrix", 1); err != nil || res.Results[0].Title != "The Matrix" {
+		t.Errorf("breaker open: %v, %+v, want the stale response", err, res)
+	}
+	if _, err := c.SearchMovies(ctx, "neo", 1); !errors.Is(err, ErrUnavailable) {
+		t.Errorf("breaker open, nothing cached: err = %v, want ErrUnavailable", err)
+	}
+	if calls.Load() != before {
+		t.Errorf("open breaker let %d calls through", calls.Load()-before)
+	}
+}
+
+func TestRetryAfter(t *testing.T) {
+	now := time.Date(2025, 12, 1, 12, 0, 0, 0, time.UTC)
+	for v, want := range map[string]time.Duration{
+		"":                              0,
+		"3":                             3 * time.Second,
+		"Mon, 01 Dec 2025 12:00:05 GMT": 5 * time.Second,
+		"Mon, 01 Dec 2025 11:00:00 GMT": 0,
+		"soon":                          0,
+	} {
+		h := http.Header{}
+		if v != "" {
+			h.Set("Retry-After", v)
+		}
+		if got := retryAfter(h, now); got != want {
+			t.Errorf("retryAfter(%q) = %v, want %v", v, got, want)
+		}
+	}
+}
+
+func TestClientBreakerAbandonedProbe(t *testing.T) {
+	var down atomic.Bool
+	down.Store(true)
+	c, _ := newTestClient(t, func(w http.ResponseWriter, r *http.Request, _ int32) {
+		if down.Load() {
+			w.WriteHeader(http.StatusBadGateway)
+			return
+		}
+		_, _ = w.Write([]byte(`{"id": 603, "title": "The Matrix"}`))
+	})
+	c.breaker = newBreaker(1, time.Millisecond)
+	ctx := context.Background()
+
+	if _, err := c.GetMovie(ctx, 603); !errors.Is(err, ErrUnavailable) {
+		t.Fatalf("err = %v, want ErrUnavailable", err)
+	}
+	time.Sleep(2 * time.Millisecond)
+	down.Store(false)
+
+	// The probe's caller gives up while waiting for a token
+	cancelled, cancel := context.WithCancel(ctx)
+	cancel()
+	c.limiter = newTokenBucket(1, 0)
+	if _, err := c.GetMovie(cancelled, 603); !errors.Is(err, context.Canceled) {
+		t.Fatalf("cancelled probe: err = %v, want context.Canceled", err)
+	}
+	c.limiter = newTokenBucket(1000, 100)
+	if m, err := c.GetMovie(ctx, 603); err != nil || m.Title != "The Matrix" {
+		t.Fatalf("after an abandoned probe: %v, %+v, want the next call to probe", err, m)
+	}
+}
+
+func TestBreakerReleaseKeepsOthersProbe(t *testing.T) {
+	now := time.Now()
+	b := newBreaker(1, time.Millisecond)
+	b.done(false, ErrUnavailable, false, now)
+
+	ok, probe := b.allow(now.Add(time.Second))
+	if !ok || !probe {
+		t.Fatalf("allow after cooldown = %v, %v, want the probe", ok, probe)
+	}
+	// A non-probe call started before the circuit opened is abandoned
+	b.done(false, context.Canceled, true, now)
+	if ok, _ := b.allow(now.Add(time.Second)); ok {
+		t.Error("a second probe was let through while the first is running")
+	}
+}
+
+func TestStaleCacheBoundedByBytes(t *testing.T) {
+	c := newStaleCache(10)
+	c.put("a", []byte("1234"))
+	c.put("b", []byte("1234"))
+	c.put("a", []byte("123456"))
+	if c.size != 10 {
+		t.Errorf("size after replacing a = %d, want 10", c.size)
+	}
+	c.put("c", []byte("12"))
+	if _, ok := c.get("a"); ok {
+		t.Error("oldest entry kept past the byte limit")
+	}
+	c.put("big", []byte("12345678901"))
+	if _, ok := c.get("big"); ok || c.size > 10 {
+		t.Errorf("body over the limit cached, size %d", c.size)
+	}
+	if got := backoff(0, 2); got != 0 {
+		t.Errorf("backoff with no base = %v, want 0", got)
+	}
+}
+
+func TestClientTransportErrorHidesAPIKey(t *testing.T) {
+	c := New("secret-key", "http://127.0.0.1:1")
+	c.limiter = newTokenBucket(1000, 100)
+	c.retryBase = time.Millisecond
+	_, err := c.GetMovie(context.Background(), 1)
+	if !errors.Is(err, ErrUnavailable) {
+		t.Fatalf("err = %v, want ErrUnavailable", err)
+	}
+	if strings.Contains(err.Error(), "secret-key") {
+		t.Errorf("error leaks the API key: %v", err)
+	}
+}
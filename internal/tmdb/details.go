@@ -0,0 +1,217 @@
+package tmdb
+
+import (
+	"sort"
+	"strconv"
+	"time"
+
+	"github.com/Dubjay18/scenee/internal/domain"
+)
+
+// movieAppend is what GetMovie asks TMDB to send along with a movie, saving
+// a request per sub-resource.
+const movieAppend = "credits,videos,release_dates,watch/providers,similar,recommendations"
+
+const (
+	// maxCast keeps the billed cast, not every extra.
+	maxCast = 20
+	// maxRelated bounds similar titles and recommendations each.
+	maxRelated = 10
+)
+
+// crewJobs are the crew credits worth showing on a movie page.
+var crewJobs = map[string]bool{
+	"Director":                true,
+	"Screenplay":              true,
+	"Writer":                  true,
+	"Novel":                   true,
+	"Producer":                true,
+	"Director of Photography": true,
+	"Original Music Composer": true,
+	"Editor":                  true,
+}
+
+// MovieDetails is a movie with the sub-resources in movieAppend.
+type MovieDetails struct {
+	Movie
+	Credits         Credits           `json:"credits"`
+	Videos          VideoList         `json:"videos"`
+	ReleaseDates    ReleaseDateList   `json:"release_dates"`
+	WatchProviders  WatchProviderList `json:"watch/providers"`
+	Similar         MovieList         `json:"similar"`
+	Recommendations MovieList         `json:"recommendations"`
+}
+
+type Credits struct {
+	Cast []CastCredit `json:"cast"`
+	Crew []CrewCredit `json:"crew"`
+}
+
+type CastCredit struct {
+	ID          int    `json:"id"`
+	Name        string `json:"name"`
+	Character   string `json:"character"`
+	ProfilePath string `json:"profile_path"`
+	Order       int    `json:"order"`
+}
+
+type CrewCredit struct {
+	ID          int    `json:"id"`
+	Name        string `json:"name"`
+	Job         string `json:"job"`
+	Department  string `json:"department"`
+	ProfilePath string `json:"profile_path"`
+}
+
+type VideoList struct {
+	Results []Video `json:"results"`
+}
+
+type Video struct {
+	Name        string `json:"name"`
+	Site        string `json:"site"`
+	Key         string `json:"key"`
+	Type        string `json:"type"`
+	Official    bool   `json:"official"`
+	PublishedAt string `json:"published_at"`
+}
+
+type ReleaseDateList struct {
+	Results []RegionReleaseDates `json:"results"`
+}
+
+type RegionReleaseDates struct {
+	Region       string        `json:"iso_3166_1"`
+	ReleaseDates []ReleaseDate `json:"release_dates"`
+}
+
+type ReleaseDate struct {
+	Certification string `json:"certification"`
+	ReleaseDate   string `json:"release_date"`
+	Type          int    `json:"type"`
+}
+
+// WatchProviderList is keyed by region.
+type WatchProviderList struct {
+	Results map[string]RegionProviders `json:"results"`
+}
+
+type RegionProviders struct {
+	Link     string     `json:"link"`
+	Flatrate []Provider `json:"flatrate"`
+	Rent     []Provider `json:"rent"`
+	Buy      []Provider `json:"buy"`
+	Ads      []Provider `json:"ads"`
+	Free     []Provider `json:"free"`
+}
+
+type Provider struct {
+	ID       int    `json:"provider_id"`
+	Name     string `json:"provider_name"`
+	LogoPath string `json:"logo_path"`
+}
+
+type MovieList struct {
+	Results []Movie `json:"results"`
+}
+
+// ToDomainMovieDetails is ToDomainMovie with Details filled from the
+// appended sub-resources, trimmed to what a movie page shows.
+func (c *Client) ToDomainMovieDetails(td *MovieDetails) *domain.Movie {
+	if td == nil {
+		return nil
+	}
+	dm := c.ToDomainMovie(&td.Movie)
+	dm.Details = td.toDomain(time.Now())
+	return dm
+}
+
+func (td *MovieDetails) toDomain(now time.Time) *domain.MovieDetails {
+	d := &domain.MovieDetails{
+		Cast:            []domain.CastMember{},
+		Crew:            []domain.CrewMember{},
+		Trailers:        []domain.Video{},
+		Releases:        map[string][]domain.Release{},
+		Providers:       map[string]domain.WatchProviders{},
+		Similar:         movieRefs(td.Similar.Results),
+		Recommendations: movieRefs(td.Recommendations.Results),
+		FetchedAt:       now,
+	}
+
+	cast := append([]CastCredit(nil), td.Credits.Cast...)
+	sort.SliceStable(cast, func(i, j int) bool { return cast[i].Order < cast[j].Order })
+	for _, c := range cast[:min(len(cast), maxCast)] {
+		d.Cast = append(d.Cast, domain.CastMember{PersonID: c.ID, Name: c.Name, Character: c.Character, ProfilePath: c.ProfilePath, Order: c.Order})
+	}
+	for _, c := range td.Credits.Crew {
+		if crewJobs[c.Job] {
+			d.Crew = append(d.Crew, domain.CrewMember{PersonID: c.ID, Name: c.Name, Job: c.Job, Department: c.Department, ProfilePath: c.ProfilePath})
+		}
+	}
+
+	for _, v := range td.Videos.Results {
+		if (v.Site != "YouTube" && v.Site != "Vimeo") || (v.Type != "Trailer" && v.Type != "Teaser") {
+			continue
+		}
+		published, _ := time.Parse(time.RFC3339, v.PublishedAt)
+		d.Trailers = append(d.Trailers, domain.Video{Name: v.Name, Site: v.Site, Key: v.Key, Type: v.Type, Official: v.Official, PublishedAt: published})
+	}
+	// Official trailers first, then teasers, newest first within each.
+	sort.SliceStable(d.Trailers, func(i, j int) bool {
+		a, b := d.Trailers[i], d.Trailers[j]
+		if a.Official != b.Official {
+			return a.Official
+		}
+		if a.Type != b.Type {
+			return a.Type == "Trailer"
+		}
+		return a.PublishedAt.After(b.PublishedAt)
+	})
+
+	for _, r := range td.ReleaseDates.Results {
+		releases := make([]domain.Release, 0, len(r.ReleaseDates))
+		for _, rd := range r.ReleaseDates {
+			date, err := time.Parse(time.RFC3339, rd.ReleaseDate)
+			if err != nil {
+				continue
+			}
+			releases = append(releases, domain.Release{Date: date, Type: rd.Type, Certification: rd.Certification})
+		}
+		sort.SliceStable(releases, func(i, j int) bool { return releases[i].Date.Before(releases[j].Date) })
+		if len(releases) > 0 {
+			d.Releases[r.Region] = releases
+		}
+	}
+
+	for region, p := range td.WatchProviders.Results {
+		d.Providers[region] = domain.WatchProviders{
+			Link:    p.Link,
+			Stream:  providers(p.Flatrate),
+			Rent:    providers(p.Rent),
+			Buy:     providers(p.Buy),
+			FreeAds: providers(p.Ads),
+			Free:    providers(p.Free),
+		}
+	}
+	return d
+}
+
+func providers(ps []Provider) []domain.Provider {
+	out := make([]domain.Provider, 0, len(ps))
+	for _, p := range ps {
+		out = append(out, domain.Provider{ID: p.ID, Name: p.Name, LogoPath: p.LogoPath})
+	}
+	return out
+}
+
+func movieRefs(ms []Movie) []domain.MovieRef {
+	out := make([]domain.MovieRef, 0, min(len(ms), maxRelated))
+	for _, m := range ms[:min(len(ms), maxRelated)] {
+		ref := domain.MovieRef{TMDBID: int(m.ID), Title: m.Title, PosterPath: m.PosterPath}
+		if len(m.ReleaseDate) >= 4 {
+			ref.Year, _ = strconv.Atoi(m.ReleaseDate[:4])
+		}
+		out = append(out, ref)
+	}
+	return out
+}
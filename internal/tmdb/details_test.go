@@ -0,0 +1,82 @@
+package tmdb
+
+import (
+	"encoding/json"
+	"testing"
+	"time"
+)
+
+const detailsJSON = `{
+	"id": 27205,
+	"title": "Inception",
+	"release_date": "2010-07-15",
+	"credits": {
+		"cast": [
+			{"id": 2, "name": "Joseph Gordon-Levitt", "character": "Arthur", "order": 1},
+			{"id": 1, "name": "Leonardo DiCaprio", "character": "Cobb", "order": 0}
+		],
+		"crew": [
+			{"id": 10, "name": "Christopher Nolan", "job": "Director", "department": "Directing"},
+			{"id": 11, "name": "Someone", "job": "Catering", "department": "Crew"}
+		]
+	},
+	"videos": {"results": [
+		{"name": "Teaser", "site": "YouTube", "key": "t", "type": "Teaser", "official": true, "published_at": "2010-05-01T00:00:00.000Z"},
+		{"name": "Featurette", "site": "YouTube", "key": "f", "type": "Featurette", "official": true},
+		{"name": "Fan cut", "site": "YouTube", "key": "x", "type": "Trailer", "official": false, "published_at": "2011-01-01T00:00:00.000Z"},
+		{"name": "Trailer", "site": "YouTube", "key": "a", "type": "Trailer", "official": true, "published_at": "2010-06-01T00:00:00.000Z"}
+	]},
+	"release_dates": {"results": [
+		{"iso_3166_1": "US", "release_dates": [
+			{"certification": "PG-13", "release_date": "2010-07-16T00:00:00.000Z", "type": 3},
+			{"certification": "", "release_date": "2010-07-13T00:00:00.000Z", "type": 1}
+		]}
+	]},
+	"watch/providers": {"results": {
+		"GB": {"link": "https://www.themoviedb.org/movie/27205/watch?locale=GB", "flatrate": [{"provider_id": 8, "provider_name": "Netflix", "logo_path": "/n.jpg"}]}
+	}},
+	"similar": {"results": [{"id": 1, "title": "The Matrix", "release_date": "1999-03-30"}]}
+}`
+
+func TestMovieDetailsToDomain(t *testing.T) {
+	var td MovieDetails
+	if err := json.Unmarshal([]byte(detailsJSON), &td); err != nil {
+		t.Fatal(err)
+	}
+	now := time.Date(2025, 12, 1, 0, 0, 0, 0, time.UTC)
+	d := td.toDomain(now)
+
+	if td.Title != "Inception" {
+		t.Errorf("Title = %q, want the embedded movie decoded", td.Title)
+	}
+	if len(d.Cast) != 2 || d.Cast[0].Name != "Leonardo DiCaprio" {
+		t.Errorf("Cast = %+v, want billing order", d.Cast)
+	}
+	if len(d.Crew) != 1 || d.Crew[0].Job != "Director" {
+		t.Errorf("Crew = %+v, want only notable jobs", d.Crew)
+	}
+	var keys []string
+	for _, v := range d.Trailers {
+		keys = append(keys, v.Key)
+	}
+	if want := []string{"a", "t", "x"}; len(keys) != len(want) || keys[0] != want[0] || keys[1] != want[1] || keys[2] != want[2] {
+		t.Errorf("Trailers = %v, want %v", keys, want)
+	}
+	if us := d.Releases["US"]; len(us) != 2 || us[0].Type != 1 || us[1].Certification != "PG-13" {
+		t.Errorf("Releases[US] = %+v, want premiere then theatrical", us)
+	}
+	if gb := d.Providers["GB"]; len(gb.Stream) != 1 || gb.Stream[0].Name != "Netflix" {
+		t.Errorf("Providers[GB] = %+v", gb)
+	}
+	if len(d.Similar) != 1 || d.Similar[0].Year != 1999 {
+		t.Errorf("Similar = %+v", d.Similar)
+	}
+	if !d.FetchedAt.Equal(now) || d.Stale(now.Add(time.Hour), 24*time.Hour) {
+		t.Errorf("FetchedAt = %v, want fresh at %v", d.FetchedAt, now)
+	}
+
+	d.ForRegion("GB")
+	if len(d.Releases) != 0 || len(d.Providers) != 1 {
+		t.Errorf("ForRegion(GB) left %d release regions and %d provider regions", len(d.Releases), len(d.Providers))
+	}
+}
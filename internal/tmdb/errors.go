@@ -0,0 +1,35 @@
+package tmdb
+
+import (
+	"errors"
+	"fmt"
+)
+
+// Errors a StatusError unwraps to, so callers can tell them apart with
+// errors.Is.
+var (
+	// ErrNotFound is TMDB answering 404: the ID doesn't exist.
+	ErrNotFound = errors.New("tmdb: not found")
+	// ErrRateLimited is TMDB still answering 429 after retries.
+	ErrRateLimited = errors.New("tmdb: rate limited")
+	// ErrUnavailable is TMDB failing with 5xx or not answering, or the
+	// circuit breaker refusing to call it.
+	ErrUnavailable = errors.New("tmdb: unavailable")
+)
+
+// StatusError is a request TMDB didn't answer with 200. Err is one of the
+// sentinel errors above, or nil for other statuses such as 401.
+type StatusError struct {
+	Path   string
+	Status int
+	Err    error
+}
+
+func (e *StatusError) Error() string {
+	if e.Status == 0 {
+		return fmt.Sprintf("tmdb %s: %v", e.Path, e.Err)
+	}
+	return fmt.Sprintf("tmdb %s: status %d", e.Path, e.Status)
+}
+
+func (e *StatusError) Unwrap() error { return e.Err }
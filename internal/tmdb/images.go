@@ -0,0 +1,15 @@
+package tmdb
+
+import "strings"
+
+// ImageBaseURL is TMDb's image CDN; sizes are path segments like "w342".
+const ImageBaseURL = "https://image.tmdb.org/t/p/"
+
+// ImageURL turns a TMDb image path ("/abc.jpg") into a full URL at size.
+// Values that are already absolute URLs are returned unchanged.
+func ImageURL(path, size string) string {
+	if path == "" || strings.HasPrefix(path, "http://") || strings.HasPrefix(path, "https://") {
+		return path
+	}
+	return ImageBaseURL + size + "/" + strings.TrimLeft(path, "/")
+}
@@ -0,0 +1,136 @@
+package tmdb
+
+import (
+	"context"
+	"fmt"
+	"net/url"
+	"sort"
+	"time"
+
+	"github.com/Dubjay18/scenee/internal/domain"
+	"github.com/Dubjay18/scenee/internal/models"
+)
+
+// Person is a person search result, or the person part of GetPerson.
+type Person struct {
+	ID                 int64         `json:"id"`
+	Name               string        `json:"name"`
+	Biography          string        `json:"biography"`
+	KnownForDepartment string        `json:"known_for_department"`
+	ProfilePath        string        `json:"profile_path"`
+	Birthday           string        `json:"birthday"`
+	Deathday           string        `json:"deathday"`
+	PlaceOfBirth       string        `json:"place_of_birth"`
+	KnownFor           []PersonTitle `json:"known_for"`
+}
+
+// PersonTitle is a movie or series in someone's credits. Movies have a
+// Title and ReleaseDate, series a Name and FirstAirDate.
+type PersonTitle struct {
+	ID           int64  `json:"id"`
+	MediaType    string `json:"media_type"`
+	Title        string `json:"title"`
+	Name         string `json:"name"`
+	ReleaseDate  string `json:"release_date"`
+	FirstAirDate string `json:"first_air_date"`
+	PosterPath   string `json:"poster_path"`
+	Character    string `json:"character"`
+	Job          string `json:"job"`
+	Department   string `json:"department"`
+}
+
+type SearchPeopleResponse struct {
+	Page         int      `json:"page"`
+	TotalPages   int      `json:"total_pages"`
+	TotalResults int      `json:"total_results"`
+	Results      []Person `json:"results"`
+}
+
+// PersonDetails is a person with their combined movie and TV credits.
+type PersonDetails struct {
+	Person
+	CombinedCredits struct {
+		Cast []PersonTitle `json:"cast"`
+		Crew []PersonTitle `json:"crew"`
+	} `json:"combined_credits"`
+}
+
+func (c *Client) SearchPeople(ctx context.Context, query string, page int) (*SearchPeopleResponse, error) {
+	q := url.Values{"query": {query}}
+	if page > 0 {
+		q.Set("page", fmt.Sprint(page))
+	}
+	var out SearchPeopleResponse
+	if err := c.get(ctx, "/search/person", q, &out); err != nil {
+		return nil, err
+	}
+	return &out, nil
+}
+
+// GetPerson fetches a person with /person/{id}/combined_credits appended.
+func (c *Client) GetPerson(ctx context.Context, id int64) (*PersonDetails, error) {
+	var out PersonDetails
+	if err := c.get(ctx, fmt.Sprintf("/person/%d", id), url.Values{"append_to_response": {"combined_credits"}}, &out); err != nil {
+		return nil, err
+	}
+	return &out, nil
+}
+
+// ToDomainPerson converts a person and their credits, newest first with
+// undated (unannounced) titles leading.
+func (c *Client) ToDomainPerson(pd *PersonDetails) *domain.Person {
+	if pd == nil {
+		return nil
+	}
+	return pd.toDomain(time.Now())
+}
+
+func (pd *PersonDetails) toDomain(now time.Time) *domain.Person {
+	p := &domain.Person{
+		TMDBID:             int(pd.ID),
+		Name:               pd.Name,
+		Biography:          pd.Biography,
+		KnownForDepartment: pd.KnownForDepartment,
+		ProfilePath:        pd.ProfilePath,
+		Birthday:           parseDate(pd.Birthday),
+		Deathday:           parseDate(pd.Deathday),
+		PlaceOfBirth:       pd.PlaceOfBirth,
+		Credits:            make([]domain.Credit, 0, len(pd.CombinedCredits.Cast)+len(pd.CombinedCredits.Crew)),
+		FetchedAt:          now,
+	}
+	for _, t := range pd.CombinedCredits.Cast {
+		t.Department = "Acting"
+		p.Credits = append(p.Credits, t.toCredit())
+	}
+	for _, t := range pd.CombinedCredits.Crew {
+		p.Credits = append(p.Credits, t.toCredit())
+	}
+	sort.SliceStable(p.Credits, func(i, j int) bool {
+		a, b := p.Credits[i].ReleaseDate, p.Credits[j].ReleaseDate
+		if a == nil || b == nil {
+			return a == nil && b != nil
+		}
+		return a.After(*b)
+	})
+	return p
+}
+
+func (t PersonTitle) toCredit() domain.Credit {
+	c := domain.Credit{
+		TMDBID:     int(t.ID),
+		MediaType:  models.MediaMovie,
+		Title:      t.Title,
+		PosterPath: t.PosterPath,
+		Department: t.Department,
+		Character:  t.Character,
+		Job:        t.Job,
+	}
+	date := t.ReleaseDate
+	if t.MediaType == models.MediaTV {
+		c.MediaType, c.Title, date = models.MediaTV, t.Name, t.FirstAirDate
+	}
+	if c.ReleaseDate = parseDate(date); c.ReleaseDate != nil {
+		c.Year = c.ReleaseDate.Year()
+	}
+	return c
+}
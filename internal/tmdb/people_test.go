@@ -0,0 +1,58 @@
+package tmdb
+
+import (
+	"encoding/json"
+	"testing"
+	"time"
+)
+
+const personJSON = `{
+	"id": 525,
+	"name": "Christopher Nolan",
+	"known_for_department": "Directing",
+	"birthday": "1970-07-30",
+	"deathday": null,
+	"combined_credits": {
+		"cast": [
+			{"id": 1, "media_type": "tv", "name": "Some Documentary", "first_air_date": "2015-01-01", "character": "Himself"}
+		],
+		"crew": [
+			{"id": 27205, "media_type": "movie", "title": "Inception", "release_date": "2010-07-15", "job": "Director", "department": "Directing"},
+			{"id": 872585, "media_type": "movie", "title": "Oppenheimer", "release_date": "2023-07-19", "job": "Director", "department": "Directing"},
+			{"id": 99, "media_type": "movie", "title": "Untitled", "release_date": "", "job": "Director", "department": "Directing"}
+		]
+	}
+}`
+
+func TestPersonDetailsToDomain(t *testing.T) {
+	var pd PersonDetails
+	if err := json.Unmarshal([]byte(personJSON), &pd); err != nil {
+		t.Fatal(err)
+	}
+	p := pd.toDomain(time.Date(2025, 12, 1, 0, 0, 0, 0, time.UTC))
+
+	if p.Birthday == nil || p.Deathday != nil {
+		t.Errorf("Birthday = %v, Deathday = %v", p.Birthday, p.Deathday)
+	}
+	var titles []string
+	for _, c := range p.Credits {
+		titles = append(titles, c.Title)
+	}
+	want := []string{"Untitled", "Oppenheimer", "Some Documentary", "Inception"}
+	if len(titles) != len(want) {
+		t.Fatalf("credits = %v, want %v", titles, want)
+	}
+	for i := range want {
+		if titles[i] != want[i] {
+			t.Fatalf("credits = %v, want undated first then newest", titles)
+		}
+	}
+	if c := p.Credits[2]; c.MediaType != "tv" || c.Department != "Acting" || c.Year != 2015 {
+		t.Errorf("cast credit = %+v", c)
+	}
+
+	p.Filter("movie", "Directing")
+	if len(p.Credits) != 3 {
+		t.Errorf("Filter(movie, Directing) kept %d credits, want 3", len(p.Credits))
+	}
+}
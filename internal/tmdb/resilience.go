@@ -0,0 +1,201 @@
+package tmdb
+
+import (
+	"context"
+	"errors"
+	"math/rand/v2"
+	"net/http"
+	"strconv"
+	"sync"
+	"time"
+)
+
+const (
+	// TMDB allows roughly 50 requests a second per IP; stay under it.
+	defaultRate  = 40
+	defaultBurst = 20
+
+	// maxAttempts counts the first try. Backoff doubles from retryBase up
+	// to maxBackoff with full jitter; a longer Retry-After than
+	// maxRetryAfter isn't waited for, a shorter one gets up to a quarter
+	// more so throttled clients don't all come back at once.
+	maxAttempts   = 3
+	retryBase     = 250 * time.Millisecond
+	maxBackoff    = 4 * time.Second
+	maxRetryAfter = 10 * time.Second
+
+	// The breaker opens after breakerThreshold consecutive upstream
+	// failures and lets a probe through after breakerCooldown.
+	breakerThreshold = 5
+	breakerCooldown  = 30 * time.Second
+
+	// staleBytes bounds the response bodies kept to serve while TMDB is down.
+	staleBytes = 32 << 20
+)
+
+// tokenBucket is a rate limiter holding up to burst tokens, refilled at
+// rate per second.
+type tokenBucket struct {
+	mu     sync.Mutex
+	rate   float64
+	burst  float64
+	tokens float64
+	last   time.Time
+}
+
+func newTokenBucket(rate, burst float64) *tokenBucket {
+	return &tokenBucket{rate: rate, burst: burst, tokens: burst, last: time.Now()}
+}
+
+// wait blocks until a token is available or ctx is done.
+func (b *tokenBucket) wait(ctx context.Context) error {
+	for {
+		b.mu.Lock()
+		now := time.Now()
+		b.tokens = min(b.burst, b.tokens+now.Sub(b.last).Seconds()*b.rate)
+		b.last = now
+		if b.tokens >= 1 {
+			b.tokens--
+			b.mu.Unlock()
+			return nil
+		}
+		need := time.Duration((1 - b.tokens) / b.rate * float64(time.Second))
+		b.mu.Unlock()
+		if err := sleep(ctx, need); err != nil {
+			return err
+		}
+	}
+}
+
+// breaker is a circuit breaker. While open, calls fail fast; once the
+// cooldown passes a single probe is let through and its outcome closes or
+// reopens the circuit.
+type breaker struct {
+	mu        sync.Mutex
+	threshold int
+	cooldown  time.Duration
+	failures  int
+	openUntil time.Time
+	probing   bool
+}
+
+func newBreaker(threshold int, cooldown time.Duration) *breaker {
+	return &breaker{threshold: threshold, cooldown: cooldown}
+}
+
+// allow reports whether a call may go ahead and whether it is the
+// half-open probe. Every allowed call must be finished with done.
+func (b *breaker) allow(now time.Time) (ok, probe bool) {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+	if b.failures < b.threshold {
+		return true, false
+	}
+	if now.Before(b.openUntil) || b.probing {
+		return false, false
+	}
+	b.probing = true
+	return true, true
+}
+
+// done records the outcome of an allowed call. Only upstream failures
+// count; a call its caller abandoned says nothing about TMDB, so it only
+// hands the probe on to the next call.
+func (b *breaker) done(probe bool, err error, abandoned bool, now time.Time) {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+	if probe {
+		b.probing = false
+	}
+	switch {
+	case abandoned:
+	case errors.Is(err, ErrUnavailable):
+		b.failures++
+		if b.failures >= b.threshold {
+			b.openUntil = now.Add(b.cooldown)
+		}
+	default:
+		b.failures = 0
+	}
+}
+
+// staleCache remembers the last good response body per request, evicting
+// the oldest entries once the bodies add up to more than max bytes.
+type staleCache struct {
+	mu      sync.Mutex
+	max     int
+	size    int
+	entries map[string][]byte
+	order   []string
+}
+
+func newStaleCache(maxBytes int) *staleCache {
+	return &staleCache{max: maxBytes, entries: make(map[string][]byte)}
+}
+
+func (c *staleCache) get(key string) ([]byte, bool) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	body, ok := c.entries[key]
+	return body, ok
+}
+
+func (c *staleCache) put(key string, body []byte) {
+	if len(body) > c.max {
+		return
+	}
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	if old, ok := c.entries[key]; ok {
+		c.size -= len(old)
+	} else {
+		c.order = append(c.order, key)
+	}
+	c.entries[key] = body
+	c.size += len(body)
+	for c.size > c.max {
+		oldest := c.order[0]
+		c.order = c.order[1:]
+		c.size -= len(c.entries[oldest])
+		delete(c.entries, oldest)
+	}
+}
+
+// backoff is the wait before retry attempt+1: exponential with full jitter.
+func backoff(base time.Duration, attempt int) time.Duration {
+	return jitter(min(maxBackoff, base<<attempt))
+}
+
+// jitter is a random duration in [0, d).
+func jitter(d time.Duration) time.Duration {
+	if d <= 0 {
+		return 0
+	}
+	return rand.N(d)
+}
+
+// retryAfter parses a Retry-After header, in seconds or as an HTTP date.
+func retryAfter(h http.Header, now time.Time) time.Duration {
+	v := h.Get("Retry-After")
+	if v == "" {
+		return 0
+	}
+	if secs, err := strconv.Atoi(v); err == nil {
+		return max(0, time.Duration(secs)*time.Second)
+	}
+	if t, err := http.ParseTime(v); err == nil {
+		return max(0, t.Sub(now))
+	}
+	return 0
+}
+
+func sleep(ctx context.Context, d time.Duration) error {
+	t := time.NewTimer(d)
+	defer t.Stop()
+	select {
+	case <-ctx.Done():
+		return ctx.Err()
+	case <-t.C:
+		return nil
+	}
+}
@@ -0,0 +1,180 @@
+package tmdb
+
+import (
+	"context"
+	"fmt"
+	"net/url"
+	"time"
+
+	"github.com/google/uuid"
+
+	"github.com/Dubjay18/scenee/internal/domain"
+	"github.com/Dubjay18/scenee/internal/models"
+)
+
+// TV is a series as TMDB's /tv endpoints return it. Search results leave
+// out the season list and counts.
+type TV struct {
+	ID               int64    `json:"id"`
+	Name             string   `json:"name"`
+	Overview         string   `json:"overview"`
+	PosterPath       string   `json:"poster_path"`
+	BackdropPath     string   `json:"backdrop_path"`
+	FirstAirDate     string   `json:"first_air_date"`
+	LastAirDate      string   `json:"last_air_date"`
+	Genres           []Genre  `json:"genres"`
+	EpisodeRunTime   []int    `json:"episode_run_time"`
+	Status           string   `json:"status"`
+	NumberOfSeasons  int      `json:"number_of_seasons"`
+	NumberOfEpisodes int      `json:"number_of_episodes"`
+	Seasons          []Season `json:"seasons"`
+}
+
+// Season is a season summary within a TV.
+type Season struct {
+	SeasonNumber int    `json:"season_number"`
+	Name         string `json:"name"`
+	Overview     string `json:"overview"`
+	EpisodeCount int    `json:"episode_count"`
+	AirDate      string `json:"air_date"`
+	PosterPath   string `json:"poster_path"`
+}
+
+type SearchTVResponse struct {
+	Page         int  `json:"page"`
+	TotalPages   int  `json:"total_pages"`
+	TotalResults int  `json:"total_results"`
+	Results      []TV `json:"results"`
+}
+
+// SeasonDetails is a season with its episodes.
+type SeasonDetails struct {
+	Season
+	Episodes []Episode `json:"episodes"`
+}
+
+type Episode struct {
+	ID            int64  `json:"id"`
+	Name          string `json:"name"`
+	Overview      string `json:"overview"`
+	SeasonNumber  int    `json:"season_number"`
+	EpisodeNumber int    `json:"episode_number"`
+	AirDate       string `json:"air_date"`
+	Runtime       *int   `json:"runtime"`
+	StillPath     string `json:"still_path"`
+}
+
+func (c *Client) SearchTV(ctx context.Context, query string, page int) (*SearchTVResponse, error) {
+	q := url.Values{"query": {query}}
+	if page > 0 {
+		q.Set("page", fmt.Sprint(page))
+	}
+	var out SearchTVResponse
+	if err := c.get(ctx, "/search/tv", q, &out); err != nil {
+		return nil, err
+	}
+	return &out, nil
+}
+
+// GetTV fetches a series with its season list.
+func (c *Client) GetTV(ctx context.Context, id int64) (*TV, error) {
+	var out TV
+	if err := c.get(ctx, fmt.Sprintf("/tv/%d", id), nil, &out); err != nil {
+		return nil, err
+	}
+	return &out, nil
+}
+
+// GetSeason fetches one season of a series with its episodes.
+func (c *Client) GetSeason(ctx context.Context, tvID int64, season int) (*SeasonDetails, error) {
+	var out SeasonDetails
+	if err := c.get(ctx, fmt.Sprintf("/tv/%d/season/%d", tvID, season), nil, &out); err != nil {
+		return nil, err
+	}
+	return &out, nil
+}
+
+// ToDomainSeries converts a series to a domain.Movie of MediaType tv. The
+// first air date stands in for the release date and the usual episode
+// length for the runtime; Series is only filled when tv has seasons, i.e.
+// it came from GetTV rather than a search.
+func (c *Client) ToDomainSeries(tv *TV) *domain.Movie {
+	if tv == nil {
+		return nil
+	}
+	return tv.toDomain(time.Now())
+}
+
+func (tv *TV) toDomain(now time.Time) *domain.Movie {
+	dm := &domain.Movie{
+		ID:          uuid.New(),
+		TMDBID:      int(tv.ID),
+		MediaType:   models.MediaTV,
+		Title:       tv.Name,
+		PosterURL:   tv.PosterPath,
+		BackdropURL: tv.BackdropPath,
+		Genres:      convertGenres(tv.Genres),
+		Metadata: map[string]interface{}{
+			"overview": tv.Overview,
+		},
+		CreatedAt: now,
+		UpdatedAt: now,
+	}
+	if first := parseDate(tv.FirstAirDate); first != nil {
+		dm.ReleaseDate, dm.Year = first, first.Year()
+	}
+	if len(tv.EpisodeRunTime) > 0 {
+		runtime := tv.EpisodeRunTime[0]
+		dm.Runtime = &runtime
+	}
+	if len(tv.Seasons) > 0 {
+		si := &domain.SeriesInfo{
+			Status:       tv.Status,
+			SeasonCount:  tv.NumberOfSeasons,
+			EpisodeCount: tv.NumberOfEpisodes,
+			LastAirDate:  parseDate(tv.LastAirDate),
+			Seasons:      make([]domain.SeasonSummary, 0, len(tv.Seasons)),
+			FetchedAt:    now,
+		}
+		for _, s := range tv.Seasons {
+			si.Seasons = append(si.Seasons, domain.SeasonSummary{
+				Number:       s.SeasonNumber,
+				Name:         s.Name,
+				Overview:     s.Overview,
+				EpisodeCount: s.EpisodeCount,
+				AirDate:      parseDate(s.AirDate),
+				PosterPath:   s.PosterPath,
+			})
+		}
+		dm.Series = si
+	}
+	return dm
+}
+
+// ToEpisodes converts a season's episodes for caching under seriesID.
+func (sd *SeasonDetails) ToEpisodes(seriesID uuid.UUID) []models.Episode {
+	out := make([]models.Episode, 0, len(sd.Episodes))
+	for _, e := range sd.Episodes {
+		out = append(out, models.Episode{
+			SeriesID:      seriesID,
+			SeasonNumber:  sd.SeasonNumber,
+			EpisodeNumber: e.EpisodeNumber,
+			TMDBID:        int(e.ID),
+			Name:          e.Name,
+			Overview:      e.Overview,
+			AirDate:       parseDate(e.AirDate),
+			Runtime:       e.Runtime,
+			StillPath:     e.StillPath,
+		})
+	}
+	return out
+}
+
+// parseDate parses TMDB's YYYY-MM-DD dates, which may be empty.
+func parseDate(s string) *time.Time {
+	t, err := time.Parse("2006-01-02", s)
+	if err != nil {
+		return nil
+	}
+	return &t
+}
@@ -0,0 +1,63 @@
+package tmdb
+
+import (
+	"encoding/json"
+	"testing"
+	"time"
+
+	"github.com/google/uuid"
+
+	"github.com/Dubjay18/scenee/internal/models"
+)
+
+const tvJSON = `{
+	"id": 1396,
+	"name": "Breaking Bad",
+	"first_air_date": "2008-01-20",
+	"last_air_date": "2013-09-29",
+	"episode_run_time": [45, 47],
+	"status": "Ended",
+	"number_of_seasons": 5,
+	"number_of_episodes": 62,
+	"seasons": [
+		{"season_number": 0, "name": "Specials", "episode_count": 9},
+		{"season_number": 1, "name": "Season 1", "episode_count": 7, "air_date": "2008-01-20"}
+	]
+}`
+
+func TestTVToDomain(t *testing.T) {
+	var tv TV
+	if err := json.Unmarshal([]byte(tvJSON), &tv); err != nil {
+		t.Fatal(err)
+	}
+	now := time.Date(2025, 12, 1, 0, 0, 0, 0, time.UTC)
+	dm := tv.toDomain(now)
+
+	if dm.MediaType != models.MediaTV || dm.Title != "Breaking Bad" || dm.Year != 2008 {
+		t.Errorf("got %s %q (%d), want tv \"Breaking Bad\" (2008)", dm.MediaType, dm.Title, dm.Year)
+	}
+	if dm.Runtime == nil || *dm.Runtime != 45 {
+		t.Errorf("Runtime = %v, want the first episode run time", dm.Runtime)
+	}
+	if dm.Series == nil || dm.Series.EpisodeCount != 62 || len(dm.Series.Seasons) != 2 {
+		t.Fatalf("Series = %+v", dm.Series)
+	}
+	if s, ok := dm.Series.Season(1); !ok || s.EpisodeCount != 7 || s.AirDate == nil {
+		t.Errorf("Season(1) = %+v, %v", s, ok)
+	}
+	if _, ok := dm.Series.Season(6); ok {
+		t.Error("Season(6) found on a five season series")
+	}
+
+	// Round trip through the model keeps the media type and season list
+	back := dm.ToModel()
+	if back.MediaType != models.MediaTV {
+		t.Errorf("ToModel MediaType = %q", back.MediaType)
+	}
+
+	sd := SeasonDetails{Season: Season{SeasonNumber: 1}, Episodes: []Episode{{ID: 62085, EpisodeNumber: 1, Name: "Pilot", AirDate: "2008-01-20"}}}
+	eps := sd.ToEpisodes(uuid.Nil)
+	if len(eps) != 1 || eps[0].SeasonNumber != 1 || eps[0].AirDate == nil {
+		t.Errorf("ToEpisodes = %+v", eps)
+	}
+}
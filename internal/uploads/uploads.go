@@ -0,0 +1,75 @@
+// Package uploads validates and normalizes user-supplied images before they
+// are stored: size limits, content sniffing, EXIF orientation and re-encoding
+// to standard sizes.
+package uploads
+
+import (
+	"bytes"
+	"errors"
+	"image"
+	"io"
+	"net/http"
+
+	"github.com/Dubjay18/scenee/internal/imaging"
+)
+
+// MaxImageBytes bounds every image upload.
+const MaxImageBytes = 5 << 20
+
+// maxPixels rejects decompression bombs before the pixels are allocated.
+const maxPixels = 40_000_000
+
+var (
+	ErrTooLarge    = errors.New("image exceeds the 5MB limit")
+	ErrUnsupported = errors.New("file is not a supported image (jpeg, png or gif)")
+)
+
+var allowedTypes = map[string]bool{
+	"image/jpeg": true,
+	"image/png":  true,
+	"image/gif":  true,
+}
+
+// Size is a square or rectangular rendition of an upload.
+type Size struct {
+	Name          string
+	Width, Height int
+}
+
+// DecodeImage reads at most maxBytes from r, checks the sniffed content type
+// (the client's claimed type is ignored) and returns the image upright.
+func DecodeImage(r io.Reader, maxBytes int64) (image.Image, error) {
+	data, err := io.ReadAll(io.LimitReader(r, maxBytes+1))
+	if err != nil {
+		return nil, err
+	}
+	if int64(len(data)) > maxBytes {
+		return nil, ErrTooLarge
+	}
+	contentType := http.DetectContentType(data)
+	if !allowedTypes[contentType] {
+		return nil, ErrUnsupported
+	}
+	cfg, _, err := image.DecodeConfig(bytes.NewReader(data))
+	if err != nil || cfg.Width*cfg.Height > maxPixels {
+		return nil, ErrUnsupported
+	}
+	img, _, err := image.Decode(bytes.NewReader(data))
+	if err != nil {
+		return nil, ErrUnsupported
+	}
+	if contentType == "image/jpeg" {
+		img = imaging.Orient(img, imaging.JPEGOrientation(data))
+	}
+	return img, nil
+}
+
+// Render crops img to size and encodes it as JPEG. Re-encoding drops all
+// metadata, EXIF (and any GPS position in it) included.
+func Render(img image.Image, size Size) ([]byte, error) {
+	var buf bytes.Buffer
+	if err := imaging.EncodeJPEG(&buf, imaging.Fill(img, size.Width, size.Height)); err != nil {
+		return nil, err
+	}
+	return buf.Bytes(), nil
+}
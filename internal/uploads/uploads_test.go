@@ -0,0 +1,55 @@
+package uploads
+
+import (
+	"bytes"
+	"errors"
+	"image"
+	"image/png"
+	"testing"
+)
+
+func pngBytes(t *testing.T, w, h int) []byte {
+	t.Helper()
+	var buf bytes.Buffer
+	if err := png.Encode(&buf, image.NewRGBA(image.Rect(0, 0, w, h))); err != nil {
+		t.Fatal(err)
+	}
+	return buf.Bytes()
+}
+
+func TestDecodeImage(t *testing.T) {
+	img, err := DecodeImage(bytes.NewReader(pngBytes(t, 4, 3)), MaxImageBytes)
+	if err != nil {
+		t.Fatalf("png: %v", err)
+	}
+	if b := img.Bounds(); b.Dx() != 4 || b.Dy() != 3 {
+		t.Errorf("bounds = %v, want 4x3", b)
+	}
+
+	data := pngBytes(t, 4, 3)
+	if _, err := DecodeImage(bytes.NewReader(data), int64(len(data)-1)); !errors.Is(err, ErrTooLarge) {
+		t.Errorf("oversized: err = %v, want ErrTooLarge", err)
+	}
+	if _, err := DecodeImage(bytes.NewReader([]byte("<svg></svg>")), MaxImageBytes); !errors.Is(err, ErrUnsupported) {
+		t.Errorf("svg: err = %v, want ErrUnsupported", err)
+	}
+	// a PNG signature with a truncated body sniffs fine but must not decode
+	if _, err := DecodeImage(bytes.NewReader(data[:20]), MaxImageBytes); !errors.Is(err, ErrUnsupported) {
+		t.Errorf("truncated: err = %v, want ErrUnsupported", err)
+	}
+}
+
+func TestRender(t *testing.T) {
+	img, _ := DecodeImage(bytes.NewReader(pngBytes(t, 40, 20)), MaxImageBytes)
+	out, err := Render(img, Size{Name: "64", Width: 64, Height: 64})
+	if err != nil {
+		t.Fatal(err)
+	}
+	got, format, err := image.Decode(bytes.NewReader(out))
+	if err != nil || format != "jpeg" {
+		t.Fatalf("decode rendered: %v (%s)", err, format)
+	}
+	if b := got.Bounds(); b.Dx() != 64 || b.Dy() != 64 {
+		t.Errorf("bounds = %v, want 64x64", b)
+	}
+}
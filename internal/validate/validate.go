@@ -2,11 +2,25 @@ package validate
 
 import (
 	"fmt"
+	"strconv"
 
 	"github.com/go-playground/validator/v10"
+
+	"github.com/Dubjay18/scenee/internal/markup"
 )
 
-var v = validator.New(validator.WithRequiredStructEnabled())
+var v = newValidator()
+
+func newValidator() *validator.Validate {
+	val := validator.New(validator.WithRequiredStructEnabled())
+	// textmax=N limits the characters a reader sees in markup text, so
+	// formatting and link URLs don't eat into the allowance.
+	_ = val.RegisterValidation("textmax", func(fl validator.FieldLevel) bool {
+		n, err := strconv.Atoi(fl.Param())
+		return err == nil && markup.Length(fl.Field().String()) <= n
+	})
+	return val
+}
 
 // Map returns field->message errors for struct validation tags.
 func Map(s any) map[string]string {
@@ -56,6 +70,10 @@ func messageFor(fe validator.FieldError) string {
 		return fmt.Sprintf("must be one of %s", fe.Param())
 	case "gt":
 		return fmt.Sprintf("must be > %s", fe.Param())
+	case "gtefield":
+		return fmt.Sprintf("must be >= %s", toLowerFirst(fe.Param()))
+	case "textmax":
+		return fmt.Sprintf("must be at most %s characters of text", fe.Param())
 	default:
 		return fe.Error()
 	}
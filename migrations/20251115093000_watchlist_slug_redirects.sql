@@ -0,0 +1,18 @@
+-- +goose Up
+-- +goose StatementBegin
+
+-- Old slugs keep resolving to the watchlist after a rename.
+CREATE TABLE IF NOT EXISTS watchlist_slug_redirects (
+    slug citext PRIMARY KEY,
+    watchlist_id uuid NOT NULL REFERENCES watchlists(id) ON DELETE CASCADE,
+    created_at timestamptz NOT NULL DEFAULT now()
+);
+
+CREATE INDEX IF NOT EXISTS idx_watchlist_slug_redirects_watchlist_id ON watchlist_slug_redirects(watchlist_id);
+
+-- +goose StatementEnd
+
+-- +goose Down
+-- +goose StatementBegin
+DROP TABLE IF EXISTS watchlist_slug_redirects;
+-- +goose StatementEnd
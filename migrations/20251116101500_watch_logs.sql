@@ -0,0 +1,24 @@
+-- +goose Up
+-- +goose StatementBegin
+
+-- Diary of what users have watched; a movie counts as watched once it has an entry.
+CREATE TABLE IF NOT EXISTS watch_logs (
+    id uuid PRIMARY KEY DEFAULT gen_random_uuid(),
+    user_id uuid NOT NULL REFERENCES users(id) ON DELETE CASCADE,
+    movie_id uuid NOT NULL REFERENCES movies(id) ON DELETE CASCADE,
+    watched_on date NOT NULL,
+    rewatch boolean NOT NULL DEFAULT false,
+    review_id uuid REFERENCES reviews(id) ON DELETE SET NULL,
+    note text,
+    created_at timestamptz NOT NULL DEFAULT now()
+);
+
+CREATE INDEX IF NOT EXISTS idx_watch_logs_user_watched_on ON watch_logs(user_id, watched_on DESC);
+CREATE INDEX IF NOT EXISTS idx_watch_logs_user_movie ON watch_logs(user_id, movie_id);
+
+-- +goose StatementEnd
+
+-- +goose Down
+-- +goose StatementBegin
+DROP TABLE IF EXISTS watch_logs;
+-- +goose StatementEnd
@@ -0,0 +1,34 @@
+-- +goose Up
+-- +goose StatementBegin
+
+-- Smart watchlists: items are materialized from rules instead of added by hand.
+ALTER TABLE watchlists
+    ADD COLUMN IF NOT EXISTS kind text NOT NULL DEFAULT 'manual',
+    ADD COLUMN IF NOT EXISTS rules jsonb,
+    ADD COLUMN IF NOT EXISTS refreshed_at timestamptz;
+
+DO $$
+BEGIN
+    IF NOT EXISTS (
+        SELECT 1 FROM pg_constraint
+        WHERE conname = 'watchlists_kind_check'
+    ) THEN
+        ALTER TABLE watchlists
+            ADD CONSTRAINT watchlists_kind_check
+            CHECK (kind IN ('manual', 'smart'));
+    END IF;
+END $$;
+
+CREATE INDEX IF NOT EXISTS idx_movies_runtime ON movies(runtime);
+
+-- +goose StatementEnd
+
+-- +goose Down
+-- +goose StatementBegin
+DROP INDEX IF EXISTS idx_movies_runtime;
+ALTER TABLE watchlists
+    DROP CONSTRAINT IF EXISTS watchlists_kind_check,
+    DROP COLUMN IF EXISTS refreshed_at,
+    DROP COLUMN IF EXISTS rules,
+    DROP COLUMN IF EXISTS kind;
+-- +goose StatementEnd
@@ -0,0 +1,23 @@
+-- +goose Up
+-- +goose StatementBegin
+
+-- Append-only change log for watchlists and their items.
+CREATE TABLE IF NOT EXISTS watchlist_revisions (
+    id uuid PRIMARY KEY DEFAULT gen_random_uuid(),
+    watchlist_id uuid NOT NULL REFERENCES watchlists(id) ON DELETE CASCADE,
+    actor_id uuid NOT NULL REFERENCES users(id) ON DELETE CASCADE,
+    action text NOT NULL CHECK (action IN ('create', 'update', 'delete', 'restore', 'add_item', 'remove_item', 'move_item', 'edit_note')),
+    item_id uuid,
+    before jsonb,
+    after jsonb,
+    created_at timestamptz NOT NULL DEFAULT now()
+);
+
+CREATE INDEX IF NOT EXISTS idx_watchlist_revisions_watchlist_created ON watchlist_revisions(watchlist_id, created_at DESC);
+
+-- +goose StatementEnd
+
+-- +goose Down
+-- +goose StatementBegin
+DROP TABLE IF EXISTS watchlist_revisions;
+-- +goose StatementEnd
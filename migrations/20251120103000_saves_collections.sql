@@ -0,0 +1,53 @@
+-- +goose Up
+-- +goose StatementBegin
+
+-- Folders users can file saved watchlists into.
+CREATE TABLE IF NOT EXISTS save_collections (
+    id uuid PRIMARY KEY DEFAULT gen_random_uuid(),
+    user_id uuid NOT NULL REFERENCES users(id) ON DELETE CASCADE,
+    name citext NOT NULL,
+    created_at timestamptz NOT NULL DEFAULT now(),
+    updated_at timestamptz NOT NULL DEFAULT now(),
+    UNIQUE (user_id, name)
+);
+
+ALTER TABLE saves
+    ADD COLUMN IF NOT EXISTS collection_id uuid REFERENCES save_collections(id) ON DELETE SET NULL;
+
+CREATE INDEX IF NOT EXISTS idx_saves_user_created ON saves(user_id, created_at DESC);
+CREATE INDEX IF NOT EXISTS idx_saves_collection_id ON saves(collection_id);
+
+-- saves is now the only record of who saved what: fold in the legacy
+-- watchlists.saved_by array and recompute save_count from it.
+DO $$
+BEGIN
+    IF EXISTS (SELECT 1 FROM information_schema.columns
+               WHERE table_name = 'watchlists' AND column_name = 'saved_by') THEN
+        INSERT INTO saves (user_id, watchlist_id)
+        SELECT u.id, w.id
+        FROM watchlists w
+        CROSS JOIN LATERAL jsonb_array_elements_text(COALESCE(w.saved_by, '[]'::jsonb)) AS s(user_id)
+        JOIN users u ON u.id::text = s.user_id
+        ON CONFLICT DO NOTHING;
+
+        ALTER TABLE watchlists DROP COLUMN saved_by;
+    END IF;
+END $$;
+
+UPDATE watchlists w
+SET save_count = (SELECT COUNT(*) FROM saves s WHERE s.watchlist_id = w.id);
+
+-- +goose StatementEnd
+
+-- +goose Down
+-- +goose StatementBegin
+ALTER TABLE watchlists ADD COLUMN IF NOT EXISTS saved_by jsonb DEFAULT '[]';
+
+UPDATE watchlists w
+SET saved_by = COALESCE((SELECT jsonb_agg(s.user_id::text) FROM saves s WHERE s.watchlist_id = w.id), '[]'::jsonb);
+
+DROP INDEX IF EXISTS idx_saves_collection_id;
+DROP INDEX IF EXISTS idx_saves_user_created;
+ALTER TABLE saves DROP COLUMN IF EXISTS collection_id;
+DROP TABLE IF EXISTS save_collections;
+-- +goose StatementEnd
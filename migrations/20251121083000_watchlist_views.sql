@@ -0,0 +1,20 @@
+-- +goose Up
+-- +goose StatementBegin
+
+-- One row per viewer per time bucket; view_count only moves when a row is new.
+CREATE TABLE IF NOT EXISTS watchlist_views (
+    watchlist_id uuid NOT NULL REFERENCES watchlists(id) ON DELETE CASCADE,
+    viewer_key text NOT NULL,
+    bucket timestamptz NOT NULL,
+    created_at timestamptz NOT NULL DEFAULT now(),
+    PRIMARY KEY (watchlist_id, viewer_key, bucket)
+);
+
+CREATE INDEX IF NOT EXISTS idx_watchlist_views_created_at ON watchlist_views(created_at);
+
+-- +goose StatementEnd
+
+-- +goose Down
+-- +goose StatementBegin
+DROP TABLE IF EXISTS watchlist_views;
+-- +goose StatementEnd
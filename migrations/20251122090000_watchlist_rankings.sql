@@ -0,0 +1,34 @@
+-- +goose Up
+-- +goose StatementBegin
+
+-- Forks copy another user's watchlist; the link feeds trending scores.
+ALTER TABLE watchlists
+    ADD COLUMN IF NOT EXISTS forked_from_id uuid REFERENCES watchlists(id) ON DELETE SET NULL;
+
+CREATE INDEX IF NOT EXISTS idx_watchlists_forked_from_id ON watchlists(forked_from_id) WHERE forked_from_id IS NOT NULL;
+CREATE INDEX IF NOT EXISTS idx_likes_created_at ON likes(created_at);
+
+-- Precomputed trending ranks. dimension is 'all', 'genre' or 'tag'; value is
+-- the genre/tag ('' for 'all').
+CREATE TABLE IF NOT EXISTS watchlist_rankings (
+    time_window text NOT NULL CHECK (time_window IN ('day', 'week', 'month')),
+    dimension text NOT NULL CHECK (dimension IN ('all', 'genre', 'tag')),
+    value text NOT NULL DEFAULT '',
+    watchlist_id uuid NOT NULL REFERENCES watchlists(id) ON DELETE CASCADE,
+    score double precision NOT NULL,
+    rank integer NOT NULL,
+    computed_at timestamptz NOT NULL DEFAULT now(),
+    PRIMARY KEY (time_window, dimension, value, watchlist_id)
+);
+
+CREATE INDEX IF NOT EXISTS idx_watchlist_rankings_lookup ON watchlist_rankings(time_window, dimension, value, rank);
+
+-- +goose StatementEnd
+
+-- +goose Down
+-- +goose StatementBegin
+DROP TABLE IF EXISTS watchlist_rankings;
+DROP INDEX IF EXISTS idx_likes_created_at;
+DROP INDEX IF EXISTS idx_watchlists_forked_from_id;
+ALTER TABLE watchlists DROP COLUMN IF EXISTS forked_from_id;
+-- +goose StatementEnd
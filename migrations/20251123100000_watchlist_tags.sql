@@ -0,0 +1,13 @@
+-- +goose Up
+-- +goose StatementBegin
+
+-- Existing tags are brought in line with tags.Normalize by the API at
+-- startup (TagService.NormalizeStored): SQL can't match its transliteration.
+CREATE INDEX IF NOT EXISTS idx_watchlists_tags ON watchlists USING GIN (tags jsonb_path_ops);
+
+-- +goose StatementEnd
+
+-- +goose Down
+-- +goose StatementBegin
+DROP INDEX IF EXISTS idx_watchlists_tags;
+-- +goose StatementEnd
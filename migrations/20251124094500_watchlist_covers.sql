@@ -0,0 +1,17 @@
+-- +goose Up
+-- +goose StatementBegin
+
+-- cover_key is the blob store key behind cover_url; cover_custom marks an
+-- owner-uploaded cover that generated collages must not replace.
+ALTER TABLE watchlists
+    ADD COLUMN IF NOT EXISTS cover_key text NOT NULL DEFAULT '',
+    ADD COLUMN IF NOT EXISTS cover_custom boolean NOT NULL DEFAULT false;
+
+-- +goose StatementEnd
+
+-- +goose Down
+-- +goose StatementBegin
+ALTER TABLE watchlists
+    DROP COLUMN IF EXISTS cover_custom,
+    DROP COLUMN IF EXISTS cover_key;
+-- +goose StatementEnd
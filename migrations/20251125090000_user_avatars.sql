@@ -0,0 +1,15 @@
+-- +goose Up
+-- +goose StatementBegin
+
+-- avatar_key is the blob key prefix of an uploaded avatar (one blob per
+-- size); empty when avatar_url is an external link.
+ALTER TABLE users
+    ADD COLUMN IF NOT EXISTS avatar_key text NOT NULL DEFAULT '';
+
+-- +goose StatementEnd
+
+-- +goose Down
+-- +goose StatementBegin
+ALTER TABLE users
+    DROP COLUMN IF EXISTS avatar_key;
+-- +goose StatementEnd
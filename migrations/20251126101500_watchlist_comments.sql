@@ -0,0 +1,40 @@
+-- +goose Up
+-- +goose StatementBegin
+
+-- Comments on watchlists. parent_id points at a top-level comment; replies
+-- to replies are not allowed, so threads are one level deep.
+CREATE TABLE IF NOT EXISTS watchlist_comments (
+    id uuid PRIMARY KEY DEFAULT gen_random_uuid(),
+    watchlist_id uuid NOT NULL REFERENCES watchlists(id) ON DELETE CASCADE,
+    author_id uuid NOT NULL REFERENCES users(id) ON DELETE CASCADE,
+    parent_id uuid REFERENCES watchlist_comments(id) ON DELETE CASCADE,
+    body text NOT NULL CHECK (length(body) BETWEEN 1 AND 2000),
+    edited_at timestamptz,
+    created_at timestamptz NOT NULL DEFAULT now(),
+    updated_at timestamptz NOT NULL DEFAULT now()
+);
+
+CREATE INDEX IF NOT EXISTS idx_watchlist_comments_top ON watchlist_comments(watchlist_id, created_at) WHERE parent_id IS NULL;
+CREATE INDEX IF NOT EXISTS idx_watchlist_comments_parent ON watchlist_comments(parent_id, created_at);
+
+ALTER TABLE watchlists
+    ADD COLUMN IF NOT EXISTS comments_enabled boolean NOT NULL DEFAULT true;
+
+-- The original check only knew likes and follows; saves were already being
+-- inserted and comments add three more kinds.
+ALTER TABLE notifications DROP CONSTRAINT IF EXISTS notifications_type_check;
+ALTER TABLE notifications ADD CONSTRAINT notifications_type_check
+    CHECK (type IN ('like', 'follow', 'save', 'comment', 'reply', 'mention'));
+
+-- +goose StatementEnd
+
+-- +goose Down
+-- +goose StatementBegin
+DELETE FROM notifications WHERE type NOT IN ('like', 'follow');
+ALTER TABLE notifications DROP CONSTRAINT IF EXISTS notifications_type_check;
+ALTER TABLE notifications ADD CONSTRAINT notifications_type_check
+    CHECK (type IN ('like', 'follow'));
+ALTER TABLE watchlists
+    DROP COLUMN IF EXISTS comments_enabled;
+DROP TABLE IF EXISTS watchlist_comments;
+-- +goose StatementEnd
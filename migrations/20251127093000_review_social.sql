@@ -0,0 +1,49 @@
+-- +goose Up
+-- +goose StatementBegin
+
+ALTER TABLE reviews
+    ADD COLUMN IF NOT EXISTS like_count int NOT NULL DEFAULT 0,
+    ADD COLUMN IF NOT EXISTS reply_count int NOT NULL DEFAULT 0;
+
+CREATE INDEX IF NOT EXISTS idx_reviews_movie_likes ON reviews(movie_id, like_count DESC, created_at DESC) WHERE deleted_at IS NULL;
+
+CREATE TABLE IF NOT EXISTS review_likes (
+    user_id uuid NOT NULL REFERENCES users(id) ON DELETE CASCADE,
+    review_id uuid NOT NULL REFERENCES reviews(id) ON DELETE CASCADE,
+    created_at timestamptz NOT NULL DEFAULT now(),
+    PRIMARY KEY (user_id, review_id)
+);
+
+CREATE INDEX IF NOT EXISTS idx_review_likes_review ON review_likes(review_id);
+
+-- Replies to a review form a single flat thread.
+CREATE TABLE IF NOT EXISTS review_replies (
+    id uuid PRIMARY KEY DEFAULT gen_random_uuid(),
+    review_id uuid NOT NULL REFERENCES reviews(id) ON DELETE CASCADE,
+    user_id uuid NOT NULL REFERENCES users(id) ON DELETE CASCADE,
+    body text NOT NULL CHECK (length(body) BETWEEN 1 AND 2000),
+    created_at timestamptz NOT NULL DEFAULT now(),
+    updated_at timestamptz NOT NULL DEFAULT now()
+);
+
+CREATE INDEX IF NOT EXISTS idx_review_replies_review_created ON review_replies(review_id, created_at);
+
+ALTER TABLE notifications DROP CONSTRAINT IF EXISTS notifications_type_check;
+ALTER TABLE notifications ADD CONSTRAINT notifications_type_check
+    CHECK (type IN ('like', 'follow', 'save', 'comment', 'reply', 'mention', 'review_like', 'review_reply'));
+
+-- +goose StatementEnd
+
+-- +goose Down
+-- +goose StatementBegin
+DELETE FROM notifications WHERE type IN ('review_like', 'review_reply');
+ALTER TABLE notifications DROP CONSTRAINT IF EXISTS notifications_type_check;
+ALTER TABLE notifications ADD CONSTRAINT notifications_type_check
+    CHECK (type IN ('like', 'follow', 'save', 'comment', 'reply', 'mention'));
+DROP TABLE IF EXISTS review_replies;
+DROP TABLE IF EXISTS review_likes;
+DROP INDEX IF EXISTS idx_reviews_movie_likes;
+ALTER TABLE reviews
+    DROP COLUMN IF EXISTS reply_count,
+    DROP COLUMN IF EXISTS like_count;
+-- +goose StatementEnd
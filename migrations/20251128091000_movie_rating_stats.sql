@@ -0,0 +1,36 @@
+-- +goose Up
+-- +goose StatementBegin
+
+-- Community ratings per movie, kept in step with reviews by the review
+-- repository. histogram[i] counts ratings of i+1 (1..10).
+CREATE TABLE IF NOT EXISTS movie_rating_stats (
+    movie_id uuid PRIMARY KEY REFERENCES movies(id) ON DELETE CASCADE,
+    rating_count int NOT NULL DEFAULT 0,
+    rating_sum bigint NOT NULL DEFAULT 0,
+    histogram jsonb NOT NULL DEFAULT '[0,0,0,0,0,0,0,0,0,0]',
+    updated_at timestamptz NOT NULL DEFAULT now()
+);
+
+CREATE INDEX IF NOT EXISTS idx_movie_rating_stats_count ON movie_rating_stats(rating_count DESC);
+
+INSERT INTO movie_rating_stats (movie_id, rating_count, rating_sum, histogram)
+SELECT movie_id,
+       count(*),
+       sum(rating),
+       jsonb_build_array(
+           count(*) FILTER (WHERE rating = 1), count(*) FILTER (WHERE rating = 2),
+           count(*) FILTER (WHERE rating = 3), count(*) FILTER (WHERE rating = 4),
+           count(*) FILTER (WHERE rating = 5), count(*) FILTER (WHERE rating = 6),
+           count(*) FILTER (WHERE rating = 7), count(*) FILTER (WHERE rating = 8),
+           count(*) FILTER (WHERE rating = 9), count(*) FILTER (WHERE rating = 10))
+FROM reviews
+WHERE deleted_at IS NULL
+GROUP BY movie_id
+ON CONFLICT (movie_id) DO NOTHING;
+
+-- +goose StatementEnd
+
+-- +goose Down
+-- +goose StatementBegin
+DROP TABLE IF EXISTS movie_rating_stats;
+-- +goose StatementEnd
@@ -0,0 +1,16 @@
+-- +goose Up
+-- +goose StatementBegin
+
+-- spoiler flags the whole review; inline ||spans|| are kept in the text.
+ALTER TABLE reviews
+    ADD COLUMN IF NOT EXISTS spoiler boolean NOT NULL DEFAULT false,
+    ADD COLUMN IF NOT EXISTS content_warnings jsonb NOT NULL DEFAULT '[]';
+
+-- +goose StatementEnd
+
+-- +goose Down
+-- +goose StatementBegin
+ALTER TABLE reviews
+    DROP COLUMN IF EXISTS content_warnings,
+    DROP COLUMN IF EXISTS spoiler;
+-- +goose StatementEnd
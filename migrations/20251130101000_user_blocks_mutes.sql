@@ -0,0 +1,30 @@
+-- +goose Up
+-- +goose StatementBegin
+
+-- A block hides both users from each other; a mute only hides the muted
+-- user from the muter's feed and notifications.
+CREATE TABLE IF NOT EXISTS user_blocks (
+    blocker_id uuid NOT NULL REFERENCES users(id) ON DELETE CASCADE,
+    blocked_id uuid NOT NULL REFERENCES users(id) ON DELETE CASCADE,
+    created_at timestamptz NOT NULL DEFAULT now(),
+    PRIMARY KEY (blocker_id, blocked_id),
+    CHECK (blocker_id <> blocked_id)
+);
+
+CREATE INDEX IF NOT EXISTS idx_user_blocks_blocked ON user_blocks(blocked_id);
+
+CREATE TABLE IF NOT EXISTS user_mutes (
+    muter_id uuid NOT NULL REFERENCES users(id) ON DELETE CASCADE,
+    muted_id uuid NOT NULL REFERENCES users(id) ON DELETE CASCADE,
+    created_at timestamptz NOT NULL DEFAULT now(),
+    PRIMARY KEY (muter_id, muted_id),
+    CHECK (muter_id <> muted_id)
+);
+
+-- +goose StatementEnd
+
+-- +goose Down
+-- +goose StatementBegin
+DROP TABLE IF EXISTS user_mutes;
+DROP TABLE IF EXISTS user_blocks;
+-- +goose StatementEnd
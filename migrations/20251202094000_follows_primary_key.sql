@@ -0,0 +1,43 @@
+-- +goose Up
+-- +goose StatementBegin
+
+CREATE TABLE IF NOT EXISTS follows (
+    follower_id uuid NOT NULL REFERENCES users(id) ON DELETE CASCADE,
+    followee_id uuid NOT NULL REFERENCES users(id) ON DELETE CASCADE,
+    created_at timestamptz NOT NULL DEFAULT now(),
+    PRIMARY KEY (follower_id, followee_id),
+    CHECK (follower_id <> followee_id)
+);
+
+-- Tables created before this migration had no key: drop double follows
+-- (keeping the earliest) and self-follows, then add it.
+DELETE FROM follows a
+USING follows b
+WHERE a.follower_id = b.follower_id
+  AND a.followee_id = b.followee_id
+  AND (a.created_at, a.ctid) > (b.created_at, b.ctid);
+
+DELETE FROM follows WHERE follower_id = followee_id;
+
+DO $$
+BEGIN
+    IF NOT EXISTS (
+        SELECT 1 FROM pg_constraint
+        WHERE conrelid = 'follows'::regclass AND contype = 'p'
+    ) THEN
+        ALTER TABLE follows ADD PRIMARY KEY (follower_id, followee_id);
+    END IF;
+END $$;
+
+-- Both directions are paged newest first.
+CREATE INDEX IF NOT EXISTS idx_follows_followee_created ON follows(followee_id, created_at DESC);
+CREATE INDEX IF NOT EXISTS idx_follows_follower_created ON follows(follower_id, created_at DESC);
+
+-- +goose StatementEnd
+
+-- +goose Down
+-- +goose StatementBegin
+DROP INDEX IF EXISTS idx_follows_follower_created;
+DROP INDEX IF EXISTS idx_follows_followee_created;
+ALTER TABLE follows DROP CONSTRAINT IF EXISTS follows_pkey;
+-- +goose StatementEnd
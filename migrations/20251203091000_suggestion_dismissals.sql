@@ -0,0 +1,17 @@
+-- +goose Up
+-- +goose StatementBegin
+
+-- Users a viewer asked not to be suggested again.
+CREATE TABLE IF NOT EXISTS suggestion_dismissals (
+    user_id uuid NOT NULL REFERENCES users(id) ON DELETE CASCADE,
+    dismissed_id uuid NOT NULL REFERENCES users(id) ON DELETE CASCADE,
+    created_at timestamptz NOT NULL DEFAULT now(),
+    PRIMARY KEY (user_id, dismissed_id)
+);
+
+-- +goose StatementEnd
+
+-- +goose Down
+-- +goose StatementBegin
+DROP TABLE IF EXISTS suggestion_dismissals;
+-- +goose StatementEnd
@@ -0,0 +1,20 @@
+-- +goose Up
+-- +goose StatementBegin
+
+-- The shared-with-me inbox lists a recipient's shares newest first.
+CREATE INDEX IF NOT EXISTS idx_shares_to_user_created ON shares(to_user_id, created_at DESC);
+
+ALTER TABLE notifications DROP CONSTRAINT IF EXISTS notifications_type_check;
+ALTER TABLE notifications ADD CONSTRAINT notifications_type_check
+    CHECK (type IN ('like', 'follow', 'save', 'comment', 'reply', 'mention', 'review_like', 'review_reply', 'follow_request', 'follow_accept', 'share'));
+
+-- +goose StatementEnd
+
+-- +goose Down
+-- +goose StatementBegin
+DELETE FROM notifications WHERE type = 'share';
+ALTER TABLE notifications DROP CONSTRAINT IF EXISTS notifications_type_check;
+ALTER TABLE notifications ADD CONSTRAINT notifications_type_check
+    CHECK (type IN ('like', 'follow', 'save', 'comment', 'reply', 'mention', 'review_like', 'review_reply', 'follow_request', 'follow_accept'));
+DROP INDEX IF EXISTS idx_shares_to_user_created;
+-- +goose StatementEnd
@@ -0,0 +1,55 @@
+-- +goose Up
+-- +goose StatementBegin
+
+-- Series live alongside movies so watchlists, covers and genre rankings
+-- keep working on one table; TMDB numbers movies and series separately, so
+-- a TMDB ID is only unique per media type.
+ALTER TABLE movies ADD COLUMN IF NOT EXISTS media_type text NOT NULL DEFAULT 'movie';
+ALTER TABLE movies DROP CONSTRAINT IF EXISTS movies_media_type_check;
+ALTER TABLE movies ADD CONSTRAINT movies_media_type_check CHECK (media_type IN ('movie', 'tv'));
+ALTER TABLE movies DROP CONSTRAINT IF EXISTS movies_tmdb_id_key;
+CREATE UNIQUE INDEX IF NOT EXISTS idx_movies_media_tmdb ON movies(media_type, tmdb_id);
+
+ALTER TABLE watchlist_items ADD COLUMN IF NOT EXISTS media_type text NOT NULL DEFAULT 'movie';
+ALTER TABLE watchlist_items DROP CONSTRAINT IF EXISTS watchlist_items_media_type_check;
+ALTER TABLE watchlist_items ADD CONSTRAINT watchlist_items_media_type_check CHECK (media_type IN ('movie', 'tv'));
+
+CREATE TABLE IF NOT EXISTS tv_episodes (
+    id uuid PRIMARY KEY DEFAULT gen_random_uuid(),
+    series_id uuid NOT NULL REFERENCES movies(id) ON DELETE CASCADE,
+    season_number int NOT NULL,
+    episode_number int NOT NULL,
+    tmdb_id int NOT NULL,
+    name text NOT NULL DEFAULT '',
+    overview text NOT NULL DEFAULT '',
+    air_date date,
+    runtime int,
+    still_path text NOT NULL DEFAULT '',
+    updated_at timestamptz NOT NULL DEFAULT now(),
+    UNIQUE (series_id, season_number, episode_number)
+);
+
+CREATE TABLE IF NOT EXISTS episode_watches (
+    user_id uuid NOT NULL REFERENCES users(id) ON DELETE CASCADE,
+    episode_id uuid NOT NULL REFERENCES tv_episodes(id) ON DELETE CASCADE,
+    watched_at timestamptz NOT NULL DEFAULT now(),
+    PRIMARY KEY (user_id, episode_id)
+);
+
+CREATE INDEX IF NOT EXISTS idx_episode_watches_episode ON episode_watches(episode_id);
+
+-- +goose StatementEnd
+
+-- +goose Down
+-- +goose StatementBegin
+DROP TABLE IF EXISTS episode_watches;
+DROP TABLE IF EXISTS tv_episodes;
+DELETE FROM watchlist_items WHERE media_type = 'tv';
+ALTER TABLE watchlist_items DROP CONSTRAINT IF EXISTS watchlist_items_media_type_check;
+ALTER TABLE watchlist_items DROP COLUMN IF EXISTS media_type;
+DELETE FROM movies WHERE media_type = 'tv';
+DROP INDEX IF EXISTS idx_movies_media_tmdb;
+ALTER TABLE movies ADD CONSTRAINT movies_tmdb_id_key UNIQUE (tmdb_id);
+ALTER TABLE movies DROP CONSTRAINT IF EXISTS movies_media_type_check;
+ALTER TABLE movies DROP COLUMN IF EXISTS media_type;
+-- +goose StatementEnd
@@ -0,0 +1,46 @@
+-- +goose Up
+-- +goose StatementBegin
+
+-- People are cached from TMDB with their combined movie and TV credits.
+CREATE TABLE IF NOT EXISTS people (
+    id uuid PRIMARY KEY DEFAULT gen_random_uuid(),
+    tmdb_id int NOT NULL UNIQUE,
+    name text NOT NULL,
+    biography text NOT NULL DEFAULT '',
+    known_for_department text NOT NULL DEFAULT '',
+    profile_path text NOT NULL DEFAULT '',
+    birthday date,
+    deathday date,
+    place_of_birth text NOT NULL DEFAULT '',
+    credits jsonb NOT NULL DEFAULT '[]',
+    fetched_at timestamptz NOT NULL DEFAULT now(),
+    created_at timestamptz NOT NULL DEFAULT now(),
+    updated_at timestamptz NOT NULL DEFAULT now()
+);
+
+CREATE TABLE IF NOT EXISTS person_follows (
+    user_id uuid NOT NULL REFERENCES users(id) ON DELETE CASCADE,
+    person_id uuid NOT NULL REFERENCES people(id) ON DELETE CASCADE,
+    created_at timestamptz NOT NULL DEFAULT now(),
+    PRIMARY KEY (user_id, person_id)
+);
+
+CREATE INDEX IF NOT EXISTS idx_person_follows_person ON person_follows(person_id);
+
+-- person_credit notifications have the person as actor and the new movie
+-- as entity.
+ALTER TABLE notifications DROP CONSTRAINT IF EXISTS notifications_type_check;
+ALTER TABLE notifications ADD CONSTRAINT notifications_type_check
+    CHECK (type IN ('like', 'follow', 'save', 'comment', 'reply', 'mention', 'review_like', 'review_reply', 'follow_request', 'follow_accept', 'share', 'person_credit'));
+
+-- +goose StatementEnd
+
+-- +goose Down
+-- +goose StatementBegin
+DELETE FROM notifications WHERE type = 'person_credit';
+ALTER TABLE notifications DROP CONSTRAINT IF EXISTS notifications_type_check;
+ALTER TABLE notifications ADD CONSTRAINT notifications_type_check
+    CHECK (type IN ('like', 'follow', 'save', 'comment', 'reply', 'mention', 'review_like', 'review_reply', 'follow_request', 'follow_accept', 'share'));
+DROP TABLE IF EXISTS person_follows;
+DROP TABLE IF EXISTS people;
+-- +goose StatementEnd
@@ -0,0 +1,20 @@
+-- +goose Up
+-- +goose StatementBegin
+
+-- Sending the same list to the same person again is a no-op: keep the
+-- first of any repeats, then enforce it.
+DELETE FROM shares s
+USING shares k
+WHERE s.from_user_id = k.from_user_id
+  AND s.to_user_id = k.to_user_id
+  AND s.watchlist_id = k.watchlist_id
+  AND (s.created_at, s.id) > (k.created_at, k.id);
+
+CREATE UNIQUE INDEX IF NOT EXISTS idx_shares_from_to_watchlist ON shares(from_user_id, to_user_id, watchlist_id);
+
+-- +goose StatementEnd
+
+-- +goose Down
+-- +goose StatementBegin
+DROP INDEX IF EXISTS idx_shares_from_to_watchlist;
+-- +goose StatementEnd
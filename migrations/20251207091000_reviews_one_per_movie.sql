@@ -0,0 +1,42 @@
+-- +goose Up
+-- +goose StatementBegin
+
+-- One review per user per movie. Keep each user's latest review of a movie,
+-- retire the rest, and rebuild the rating stats they were counted in.
+UPDATE reviews r SET deleted_at = now()
+WHERE r.deleted_at IS NULL
+  AND EXISTS (
+      SELECT 1 FROM reviews k
+      WHERE k.user_id = r.user_id AND k.movie_id = r.movie_id AND k.deleted_at IS NULL
+        AND (k.updated_at, k.id) > (r.updated_at, r.id)
+  );
+
+CREATE UNIQUE INDEX IF NOT EXISTS idx_reviews_user_movie ON reviews(user_id, movie_id) WHERE deleted_at IS NULL;
+
+UPDATE movie_rating_stats SET rating_count = 0, rating_sum = 0, histogram = '[0,0,0,0,0,0,0,0,0,0]', updated_at = now();
+
+INSERT INTO movie_rating_stats (movie_id, rating_count, rating_sum, histogram)
+SELECT movie_id,
+       count(*),
+       sum(rating),
+       jsonb_build_array(
+           count(*) FILTER (WHERE rating = 1), count(*) FILTER (WHERE rating = 2),
+           count(*) FILTER (WHERE rating = 3), count(*) FILTER (WHERE rating = 4),
+           count(*) FILTER (WHERE rating = 5), count(*) FILTER (WHERE rating = 6),
+           count(*) FILTER (WHERE rating = 7), count(*) FILTER (WHERE rating = 8),
+           count(*) FILTER (WHERE rating = 9), count(*) FILTER (WHERE rating = 10))
+FROM reviews
+WHERE deleted_at IS NULL
+GROUP BY movie_id
+ON CONFLICT (movie_id) DO UPDATE SET
+    rating_count = EXCLUDED.rating_count,
+    rating_sum = EXCLUDED.rating_sum,
+    histogram = EXCLUDED.histogram,
+    updated_at = now();
+
+-- +goose StatementEnd
+
+-- +goose Down
+-- +goose StatementBegin
+DROP INDEX IF EXISTS idx_reviews_user_movie;
+-- +goose StatementEnd
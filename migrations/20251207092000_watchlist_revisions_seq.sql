@@ -0,0 +1,37 @@
+-- +goose Up
+-- +goose StatementBegin
+
+-- Revisions written in one transaction can share a timestamp; seq keeps
+-- them in the order they were made so reverts undo them in reverse.
+CREATE SEQUENCE IF NOT EXISTS watchlist_revisions_seq_seq;
+ALTER TABLE watchlist_revisions ADD COLUMN IF NOT EXISTS seq bigint;
+UPDATE watchlist_revisions r SET seq = o.n
+FROM (SELECT id, row_number() OVER (ORDER BY created_at, id) AS n FROM watchlist_revisions) o
+WHERE o.id = r.id;
+SELECT setval('watchlist_revisions_seq_seq', COALESCE((SELECT max(seq) FROM watchlist_revisions), 0) + 1, false);
+ALTER TABLE watchlist_revisions
+    ALTER COLUMN seq SET DEFAULT nextval('watchlist_revisions_seq_seq'),
+    ALTER COLUMN seq SET NOT NULL;
+ALTER SEQUENCE watchlist_revisions_seq_seq OWNED BY watchlist_revisions.seq;
+
+CREATE INDEX IF NOT EXISTS idx_watchlist_revisions_watchlist_seq ON watchlist_revisions(watchlist_id, seq);
+-- The retention purge deletes by age across all lists.
+CREATE INDEX IF NOT EXISTS idx_watchlist_revisions_created ON watchlist_revisions(created_at);
+
+ALTER TABLE watchlist_revisions DROP CONSTRAINT IF EXISTS watchlist_revisions_action_check;
+ALTER TABLE watchlist_revisions ADD CONSTRAINT watchlist_revisions_action_check
+    CHECK (action IN ('create', 'update', 'delete', 'restore', 'add_item', 'remove_item', 'move_item', 'edit_note', 'revert'));
+
+-- +goose StatementEnd
+
+-- +goose Down
+-- +goose StatementBegin
+DELETE FROM watchlist_revisions WHERE action = 'revert';
+ALTER TABLE watchlist_revisions DROP CONSTRAINT IF EXISTS watchlist_revisions_action_check;
+ALTER TABLE watchlist_revisions ADD CONSTRAINT watchlist_revisions_action_check
+    CHECK (action IN ('create', 'update', 'delete', 'restore', 'add_item', 'remove_item', 'move_item', 'edit_note'));
+DROP INDEX IF EXISTS idx_watchlist_revisions_created;
+DROP INDEX IF EXISTS idx_watchlist_revisions_watchlist_seq;
+ALTER TABLE watchlist_revisions DROP COLUMN IF EXISTS seq;
+DROP SEQUENCE IF EXISTS watchlist_revisions_seq_seq;
+-- +goose StatementEnd
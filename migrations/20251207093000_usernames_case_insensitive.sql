@@ -0,0 +1,20 @@
+-- +goose Up
+-- +goose StatementBegin
+
+-- Usernames are looked up ignoring case, so they must be unique that way.
+-- The oldest account keeps a contested name; later ones get their ID's
+-- first block appended and can rename themselves.
+UPDATE users u
+SET username = u.username || '-' || left(u.id::text, 8)
+FROM users k
+WHERE lower(u.username) = lower(k.username)
+  AND (u.created_at, u.id) > (k.created_at, k.id);
+
+CREATE UNIQUE INDEX IF NOT EXISTS idx_users_username_lower ON users(lower(username));
+
+-- +goose StatementEnd
+
+-- +goose Down
+-- +goose StatementBegin
+DROP INDEX IF EXISTS idx_users_username_lower;
+-- +goose StatementEnd